@@ -9,12 +9,14 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App   `yaml:"app"`
-		HTTP  `yaml:"http"`
-		Log   `yaml:"logger"`
-		PG    `yaml:"postgres"`
-		Mocky `yaml:"mocky"`
-		Kafka `yaml:"kafka"`
+		App       `yaml:"app"`
+		HTTP      `yaml:"http"`
+		GRPC      `yaml:"grpc"`
+		Log       `yaml:"logger"`
+		PG        `yaml:"postgres"`
+		Mocky     `yaml:"mocky"`
+		Kafka     `yaml:"kafka"`
+		Messaging `yaml:"messaging"`
 	}
 
 	// App -.
@@ -28,6 +30,11 @@ type (
 		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
 	}
 
+	// GRPC -.
+	GRPC struct {
+		Port string `env-required:"true" yaml:"port" env:"GRPC_PORT"`
+	}
+
 	// Log -.
 	Log struct {
 		Level string `env-required:"true" yaml:"log_level"   env:"LOG_LEVEL"`
@@ -42,8 +49,15 @@ type (
 		URL string `env-required:"true"    `
 	}
 	Kafka struct {
-		KAFKA_BROKER string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
-		EVENT_TOPIC  string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
+		KAFKA_BROKER       string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
+		EVENT_TOPIC        string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
+		REPLICATION_FACTOR int    `env-required:"true"  yaml:"REPLICATION_FACTOR"  env:"KAFKA_REPLICATION_FACTOR"`
+	}
+
+	// Messaging -.
+	Messaging struct {
+		DRIVER string `env-required:"true"  yaml:"DRIVER"  env:"MESSAGING_DRIVER"` // "kafka" or "postgres"
+		PG_URL string `yaml:"PG_URL"  env:"MESSAGING_PG_URL"`                      // Queue database of the "postgres" driver
 	}
 )
 
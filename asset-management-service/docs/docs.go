@@ -38,6 +38,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.TransactionRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, a retry with the same key gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -53,6 +59,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.assetResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.assetResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.assetResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -85,6 +103,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.TransferRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, a retry with the same key gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -100,6 +124,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.assetResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.assetResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.assetResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -132,6 +168,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.TransactionRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of the request, a retry with the same key gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -147,6 +189,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.assetResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.assetResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.assetResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -215,6 +269,9 @@ const docTemplate = `{
         "v1.assetResponse": {
             "type": "object",
             "properties": {
+                "consistency_token": {
+                    "type": "string"
+                },
                 "error": {
                     "type": "string"
                 },
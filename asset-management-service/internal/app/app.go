@@ -9,16 +9,28 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/config"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/controller/command"
+	grpcv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/controller/grpc/v1"
 	v1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/controller/http/v1"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/grpcserver"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/httpserver"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/idempotency"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+	"google.golang.org/grpc"
 )
 
 func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
+	// Kafka topics
+	if cfg.Messaging.DRIVER == "kafka" {
+		if err := admin.Ensure(cfg.Kafka.KAFKA_BROKER, Topics(cfg)); err != nil {
+			l.Fatal(fmt.Errorf("app - Run - admin.Ensure: %w", err))
+		}
+	}
+
 	kafkaBroker := cfg.Kafka.KAFKA_BROKER // os.Getenv("KAFKA_BROKER")      // e.g., "kafka:9092"
 	l.Error("KAFKA_BROKER")
 	l.Error(kafkaBroker)
@@ -26,25 +38,39 @@ func Run(cfg *config.Config) {
 	l.Error("EVENT_TOPIC")
 	l.Error(eventTopic)
 
-	// Initialize Kafka Producer
-	kafkaProducer, err := producer.NewKafkaProducer(kafkaBroker)
+	// Initialize command queue publisher
+	publisher, err := newPublisher(cfg)
 	if err != nil {
-		l.Fatal(" Failed to initialize Kafka producer: %v", err)
+		l.Fatal(fmt.Errorf("app - Run - newPublisher: %w", err))
 	}
-	defer kafkaProducer.Close() // Ensure producer is closed on shutdown
+	defer publisher.Close() // Ensure publisher is closed on shutdown
 
-	commandQueue := command.NewCommandProducer(kafkaProducer, eventTopic)
+	commandQueue := command.NewCommandProducer(publisher, eventTopic)
 
 	assetUseCase := usecase.NewAssetUseCase(
 		commandQueue,
 		l,
 	)
 
+	// Idempotency keys, shared by the instances of the service
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - postgres.New: %w", err))
+	}
+	defer pg.Close()
+
+	keys := idempotency.NewPostgres(pg)
+
 	// HTTP Server
 	handler := gin.New()
-	v1.NewRouter(handler, l, assetUseCase)
+	v1.NewRouter(handler, l, assetUseCase, keys)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
+	// gRPC Server
+	grpcServer := grpcserver.New(func(s *grpc.Server) {
+		grpcv1.Register(s, l, assetUseCase)
+	}, grpcserver.Port(cfg.GRPC.Port))
+
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
@@ -57,6 +83,10 @@ func Run(cfg *config.Config) {
 	case err = <-httpServer.Notify():
 
 		l.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
+
+	case err = <-grpcServer.Notify():
+
+		l.Error(fmt.Errorf("app - Run - grpcServer.Notify: %w", err))
 	}
 
 	// Shutdown
@@ -66,4 +96,9 @@ func Run(cfg *config.Config) {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
 
+	err = grpcServer.Shutdown()
+	if err != nil {
+		l.Error(fmt.Errorf("app - Run - grpcServer.Shutdown: %w", err))
+	}
+
 }
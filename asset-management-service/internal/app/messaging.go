@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging/pgqueue"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// newPublisher creates the command queue publisher of the configured driver.
+func newPublisher(cfg *config.Config) (messaging.Publisher, error) {
+	switch cfg.Messaging.DRIVER {
+	case "kafka":
+		kafkaProducer, err := producer.NewKafkaProducer(cfg.Kafka.KAFKA_BROKER)
+		if err != nil {
+			return nil, err
+		}
+		return kafkaProducer, nil
+	case "postgres":
+		queue, err := newQueue(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return queue.Publisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown messaging driver: %s", cfg.Messaging.DRIVER)
+	}
+}
+
+// newQueue connects to the Postgres queue database. The pool lives as long as
+// the service.
+func newQueue(cfg *config.Config) (*pgqueue.Queue, error) {
+	pg, err := postgres.New(cfg.Messaging.PG_URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		return nil, fmt.Errorf("postgres.New: %w", err)
+	}
+
+	queue, err := pgqueue.New(pg)
+	if err != nil {
+		pg.Close()
+		return nil, fmt.Errorf("pgqueue.New: %w", err)
+	}
+
+	return queue, nil
+}
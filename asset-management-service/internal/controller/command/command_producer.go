@@ -6,31 +6,35 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
-// CommandProducer handles publishing commands to Kafka.
+// CommandProducer handles publishing commands to the command queue.
 type CommandProducer struct {
-	producer *producer.KafkaProducer
-	topic    string
+	publisher messaging.Publisher
+	topic     string
 }
 
-// NewCommandProducer creates a new Kafka-based command producer.
-func NewCommandProducer(kafkaProducer *producer.KafkaProducer, topic string) *CommandProducer {
+// NewCommandProducer creates a new command producer.
+func NewCommandProducer(publisher messaging.Publisher, topic string) *CommandProducer {
 	return &CommandProducer{
-		producer: kafkaProducer,
-		topic:    topic,
+		publisher: publisher,
+		topic:     topic,
 	}
 }
 
-// PublishCommand serializes and sends a command to Kafka.
+// PublishCommand serializes and sends a command to the command queue.
 func (c *CommandProducer) PublishCommand(ctx context.Context, command interface{}) error {
 	commandBytes, err := json.Marshal(command)
 	if err != nil {
 		return fmt.Errorf("failed to serialize command: %w", err)
 	}
 
-	err = c.producer.ProduceEvent(c.topic, commandBytes, fmt.Sprintf("command-%s", command))
+	err = c.publisher.Publish(ctx, messaging.Message{
+		Topic: c.topic,
+		Key:   []byte(fmt.Sprintf("command-%s", command)),
+		Value: commandBytes,
+	})
 	if err != nil {
 		log.Printf("Failed to publish command: %v", err)
 		return err
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/usecase"
+	assetv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/api/asset/v1"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type assetServer struct {
+	assetv1.UnimplementedAssetCommandServiceServer
+
+	t usecase.AssetHandler
+	l logger.Interface
+}
+
+// errInvalidInput mirrors the binding rules of the HTTP requests, every field is required.
+var errInvalidInput = status.Error(codes.InvalidArgument, "Invalid input")
+
+func (s *assetServer) Withdraw(ctx context.Context, req *assetv1.WithdrawRequest) (*assetv1.CommandResponse, error) {
+	if req.GetWalletId() == 0 || req.GetAssetName() == "" || req.GetAmount() == 0 {
+		return nil, errInvalidInput
+	}
+
+	token, err := s.t.Withdraw(ctx, int(req.GetWalletId()), req.GetAssetName(), req.GetAmount())
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - Withdraw - use case error")
+		return nil, status.Error(codes.Internal, "Withdraw failed")
+	}
+
+	return &assetv1.CommandResponse{ConsistencyToken: token}, nil
+}
+
+func (s *assetServer) Deposit(ctx context.Context, req *assetv1.DepositRequest) (*assetv1.CommandResponse, error) {
+	if req.GetWalletId() == 0 || req.GetAssetName() == "" || req.GetAmount() == 0 {
+		return nil, errInvalidInput
+	}
+
+	token, err := s.t.Deposit(ctx, int(req.GetWalletId()), req.GetAssetName(), req.GetAmount())
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - Deposit - use case error")
+		return nil, status.Error(codes.Internal, "Deposit failed")
+	}
+
+	return &assetv1.CommandResponse{ConsistencyToken: token}, nil
+}
+
+func (s *assetServer) Transfer(ctx context.Context, req *assetv1.TransferRequest) (*assetv1.CommandResponse, error) {
+	if req.GetFromWalletId() == 0 || req.GetToWalletId() == 0 || req.GetAssetName() == "" ||
+		req.GetAmount() == 0 || req.GetExecuteTime() == 0 {
+		return nil, errInvalidInput
+	}
+
+	token, err := s.t.Transfer(ctx, int(req.GetFromWalletId()), int(req.GetToWalletId()), req.GetAssetName(), req.GetAmount(), req.GetExecuteTime())
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - Transfer - use case error")
+		return nil, status.Error(codes.Internal, "Transfer failed")
+	}
+
+	return &assetv1.CommandResponse{ConsistencyToken: token}, nil
+}
@@ -0,0 +1,14 @@
+// Package v1 implements the gRPC API of the asset command service.
+package v1
+
+import (
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/usecase"
+	assetv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/api/asset/v1"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// Register adds the v1 services to a gRPC server.
+func Register(s *grpc.Server, l logger.Interface, t usecase.AssetHandler) {
+	assetv1.RegisterAssetCommandServiceServer(s, &assetServer{t: t, l: l})
+}
@@ -26,10 +26,15 @@ func newAssetRoutes(handler *gin.RouterGroup, t usecase.AssetHandler, l logger.I
 	}
 }
 
-// Generic response structure for asset operations
+// _consistencyTokenHeader carries the consistency token of an accepted command.
+const _consistencyTokenHeader = "X-Consistency-Token"
+
+// Generic response structure for asset operations. Pass the consistency token
+// to asset-query-service to read a state that includes the command.
 type assetResponse struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	Status           string `json:"status"`
+	ConsistencyToken string `json:"consistency_token,omitempty"`
+	Error            string `json:"error,omitempty"`
 }
 
 // @Summary     Withdraw funds
@@ -39,8 +44,11 @@ type assetResponse struct {
 // @Accept      json
 // @Produce     json
 // @Param       request body entity.TransactionRequest true "Withdraw request"
+// @Param       Idempotency-Key header string false "Key of the request, a retry with the same key gets the first response"
 // @Success     200 {object} assetResponse
 // @Failure     400 {object} assetResponse
+// @Failure     409 {object} assetResponse
+// @Failure     422 {object} assetResponse
 // @Failure     500 {object} assetResponse
 // @Router      /assets/withdraw [post]
 func (r *assetRoutes) Withdraw(c *gin.Context) {
@@ -52,13 +60,15 @@ func (r *assetRoutes) Withdraw(c *gin.Context) {
 	}
 
 	// Include asset_name in the use case call
-	if err := r.t.Withdraw(c.Request.Context(), req.WalletID, req.AssetName, req.Amount); err != nil {
+	token, err := r.t.Withdraw(c.Request.Context(), req.WalletID, req.AssetName, req.Amount)
+	if err != nil {
 		r.l.Error(err, "http - v1 - Withdraw - use case error")
 		errorResponse(c, http.StatusInternalServerError, "Withdraw failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, assetResponse{Status: "success"})
+	c.Header(_consistencyTokenHeader, token)
+	c.JSON(http.StatusOK, assetResponse{Status: "success", ConsistencyToken: token})
 }
 
 // @Summary     Deposit funds
@@ -68,8 +78,11 @@ func (r *assetRoutes) Withdraw(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Param       request body entity.TransactionRequest true "Deposit request"
+// @Param       Idempotency-Key header string false "Key of the request, a retry with the same key gets the first response"
 // @Success     200 {object} assetResponse
 // @Failure     400 {object} assetResponse
+// @Failure     409 {object} assetResponse
+// @Failure     422 {object} assetResponse
 // @Failure     500 {object} assetResponse
 // @Router      /assets/deposit [post]
 func (r *assetRoutes) Deposit(c *gin.Context) {
@@ -81,13 +94,15 @@ func (r *assetRoutes) Deposit(c *gin.Context) {
 	}
 
 	// Include asset_name in the use case call
-	if err := r.t.Deposit(c.Request.Context(), req.WalletID, req.AssetName, req.Amount); err != nil {
+	token, err := r.t.Deposit(c.Request.Context(), req.WalletID, req.AssetName, req.Amount)
+	if err != nil {
 		r.l.Error(err, "http - v1 - Deposit - use case error")
 		errorResponse(c, http.StatusInternalServerError, "Deposit failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, assetResponse{Status: "success"})
+	c.Header(_consistencyTokenHeader, token)
+	c.JSON(http.StatusOK, assetResponse{Status: "success", ConsistencyToken: token})
 }
 
 // @Summary     Transfer funds
@@ -97,8 +112,11 @@ func (r *assetRoutes) Deposit(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Param       request body entity.TransferRequest true "Transfer request"
+// @Param       Idempotency-Key header string false "Key of the request, a retry with the same key gets the first response"
 // @Success     200 {object} assetResponse
 // @Failure     400 {object} assetResponse
+// @Failure     409 {object} assetResponse
+// @Failure     422 {object} assetResponse
 // @Failure     500 {object} assetResponse
 // @Router      /assets/transfer [post]
 func (r *assetRoutes) Transfer(c *gin.Context) {
@@ -110,11 +128,13 @@ func (r *assetRoutes) Transfer(c *gin.Context) {
 	}
 
 	// Include asset_name in the use case call
-	if err := r.t.Transfer(c.Request.Context(), req.FromWalletID, req.ToWalletID, req.AssetName, req.Amount, req.ExecuteTime); err != nil {
+	token, err := r.t.Transfer(c.Request.Context(), req.FromWalletID, req.ToWalletID, req.AssetName, req.Amount, req.ExecuteTime)
+	if err != nil {
 		r.l.Error(err, "http - v1 - Transfer - use case error")
 		errorResponse(c, http.StatusInternalServerError, "Transfer failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, assetResponse{Status: "success"})
+	c.Header(_consistencyTokenHeader, token)
+	c.JSON(http.StatusOK, assetResponse{Status: "success", ConsistencyToken: token})
 }
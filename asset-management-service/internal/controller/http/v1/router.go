@@ -14,6 +14,7 @@ import (
 	// Swagger docs.
 	_ "github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/docs"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-management-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/idempotency"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
 )
 
@@ -24,7 +25,7 @@ import (
 // @version     1.0
 // @host        localhost:8082
 // @BasePath    /v1
-func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.AssetHandler) {
+func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.AssetHandler, keys idempotency.Store) {
 	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
@@ -32,7 +33,8 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.AssetHandler)
 	handler.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:8082"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Idempotency-Key"},
+		ExposeHeaders:    []string{"X-Consistency-Token", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -50,7 +52,7 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.AssetHandler)
 	l.Info("Asset Management Service http://localhost:8082/swagger/index.html")
 
 	// Routers
-	h := handler.Group("/v1")
+	h := handler.Group("/v1", idempotency.Middleware(keys, _consistencyTokenHeader))
 	{
 		newAssetRoutes(h, t, l)
 	}
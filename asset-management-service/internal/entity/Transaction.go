@@ -22,7 +22,7 @@ type TransferRequest struct {
 
 // WalletEvent represents an event in the event journal
 type Command struct {
-	CommandID string  `json:"comand_id"`  // Unique event identifier
+	CommandID string  `json:"command_id"` // Unique command identifier, the consistency token of the command
 	WalletID  int     `json:"wallet_id"`  // Associated wallet
 	AssetName string  `json:"asset_name"` // Asset being transacted
 	Type      string  `json:"type"`       // "withdraw", "deposit", "transfer"
@@ -25,7 +25,7 @@ func NewAssetUseCase(commandQueue CommandProducerHandler, l logger.Interface) *A
 }
 
 // Withdraw funds from a wallet (Publishes event)
-func (uc *AssetUseCase) Withdraw(ctx context.Context, walletID int, assetName string, amount float64) error {
+func (uc *AssetUseCase) Withdraw(ctx context.Context, walletID int, assetName string, amount float64) (string, error) {
 	command := entity.Command{
 		CommandID: uuid.New().String(),
 		WalletID:  walletID,
@@ -36,15 +36,15 @@ func (uc *AssetUseCase) Withdraw(ctx context.Context, walletID int, assetName st
 	}
 
 	if err := uc.commandQueue.PublishCommand(ctx, command); err != nil {
-		return fmt.Errorf("Withdraw - PublishEvent: %w", err)
+		return "", fmt.Errorf("Withdraw - PublishEvent: %w", err)
 	}
 
 	uc.log.Info("Withdraw event published", "WalletID", walletID, "AssetName", assetName, "Amount", amount)
-	return nil
+	return command.CommandID, nil
 }
 
 // Deposit funds into a wallet (Publishes event)
-func (uc *AssetUseCase) Deposit(ctx context.Context, walletID int, assetName string, amount float64) error {
+func (uc *AssetUseCase) Deposit(ctx context.Context, walletID int, assetName string, amount float64) (string, error) {
 	//wallet check , ya header üzerinden teyitli geldiğini var sayabiliriz ya da httpcall ve ya readonly bir check yapabiliriz
 	command := entity.Command{
 		CommandID: uuid.New().String(),
@@ -56,15 +56,15 @@ func (uc *AssetUseCase) Deposit(ctx context.Context, walletID int, assetName str
 	}
 
 	if err := uc.commandQueue.PublishCommand(ctx, command); err != nil {
-		return fmt.Errorf("Deposit - PublishEvent: %w", err)
+		return "", fmt.Errorf("Deposit - PublishEvent: %w", err)
 	}
 
 	uc.log.Info("Deposit event published", "WalletID", walletID, "AssetName", assetName, "Amount", amount)
-	return nil
+	return command.CommandID, nil
 }
 
 // Transfer funds between wallets (Publishes a TransferCommand)
-func (uc *AssetUseCase) Transfer(ctx context.Context, fromWalletID, toWalletID int, assetName string, amount float64, executeTime int64) error {
+func (uc *AssetUseCase) Transfer(ctx context.Context, fromWalletID, toWalletID int, assetName string, amount float64, executeTime int64) (string, error) {
 	if executeTime == 0 || executeTime <= time.Now().Unix() {
 		executeTime = time.Now().Unix()
 	}
@@ -86,7 +86,7 @@ func (uc *AssetUseCase) Transfer(ctx context.Context, fromWalletID, toWalletID i
 
 	// Komut Kafka'ya gönderiliyor
 	if err := uc.commandQueue.PublishCommand(ctx, transferCommand); err != nil {
-		return fmt.Errorf("Transfer - PublishCommand: %w", err)
+		return "", fmt.Errorf("Transfer - PublishCommand: %w", err)
 	}
 
 	uc.log.Info("Transfer command published",
@@ -95,5 +95,5 @@ func (uc *AssetUseCase) Transfer(ctx context.Context, fromWalletID, toWalletID i
 		"AssetName", assetName,
 		"Amount", amount,
 	)
-	return nil
+	return transferCommand.CommandID, nil
 }
@@ -5,11 +5,11 @@ import (
 )
 
 type (
-	/* Asset Management UseCase Interface */
+	/* Asset Management UseCase Interface, every operation returns the id of the published command */
 	AssetHandler interface {
-		Withdraw(ctx context.Context, walletID int, assetName string, amount float64) error                                        // Withdraw funds from a wallet
-		Deposit(ctx context.Context, walletID int, assetName string, amount float64) error                                         // Deposit funds into a wallet
-		Transfer(ctx context.Context, fromWalletID int, toWalletID int, assetName string, amount float64, executeTime int64) error // Transfer funds between wallets
+		Withdraw(ctx context.Context, walletID int, assetName string, amount float64) (string, error)                                        // Withdraw funds from a wallet
+		Deposit(ctx context.Context, walletID int, assetName string, amount float64) (string, error)                                         // Deposit funds into a wallet
+		Transfer(ctx context.Context, fromWalletID int, toWalletID int, assetName string, amount float64, executeTime int64) (string, error) // Transfer funds between wallets
 	}
 
 	// EventJournal defines the contract for publishing events.
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key          TEXT        PRIMARY KEY,
+    fingerprint  TEXT        NOT NULL,
+    status_code  INT,
+    header       JSONB,
+    body         BYTEA,
+    locked_until TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at ON idempotency_keys (expires_at);
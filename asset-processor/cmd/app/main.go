@@ -1,16 +1,30 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"os"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/config"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/app"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
 )
 
 func main() {
+	checkTopics := flag.Bool("check-topics", false, "report drift between the declared Kafka topics and the cluster, then exit")
+	flag.Parse()
+
 	cfg, err := config.NewConfig()
 	if err != nil {
 		log.Fatalf("Config error: %s", err)
 	}
+
+	if *checkTopics {
+		if err := admin.Check(cfg.Kafka.KAFKA_BROKER, app.Topics(cfg), os.Stdout); err != nil {
+			log.Fatalf("Topic check: %s", err)
+		}
+		return
+	}
+
 	app.Run(cfg)
 }
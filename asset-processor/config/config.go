@@ -9,12 +9,13 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App   `yaml:"app"`
-		HTTP  `yaml:"http"`
-		Log   `yaml:"logger"`
-		PG    `yaml:"postgres"`
-		Mocky `yaml:"mocky"`
-		Kafka `yaml:"kafka"`
+		App       `yaml:"app"`
+		HTTP      `yaml:"http"`
+		Log       `yaml:"logger"`
+		PG        `yaml:"postgres"`
+		Mocky     `yaml:"mocky"`
+		Kafka     `yaml:"kafka"`
+		Messaging `yaml:"messaging"`
 	}
 
 	// App -.
@@ -45,6 +46,15 @@ type (
 		KAFKA_BROKER        string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
 		EVENT_TOPIC         string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
 		COMMAND_QUEUE_TOPIC string `env-required:"true"  yaml:"COMMAND_QUEUE_TOPIC"  env:"COMMAND_QUEUE_TOPIC"`
+		COMMAND_DLQ_TOPIC   string `env-required:"true"  yaml:"COMMAND_DLQ_TOPIC"  env:"COMMAND_DLQ_TOPIC"` // Commands that can never be processed
+		TRANSACTIONAL_ID    string `env-required:"true"  yaml:"TRANSACTIONAL_ID"  env:"TRANSACTIONAL_ID"`
+		REPLICATION_FACTOR  int    `env-required:"true"  yaml:"REPLICATION_FACTOR"  env:"KAFKA_REPLICATION_FACTOR"`
+	}
+
+	// Messaging -.
+	Messaging struct {
+		DRIVER string `env-required:"true"  yaml:"DRIVER"  env:"MESSAGING_DRIVER"` // "kafka" or "postgres"
+		PG_URL string `yaml:"PG_URL"  env:"MESSAGING_PG_URL"`                      // Queue database of the "postgres" driver
 	}
 )
 
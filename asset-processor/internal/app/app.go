@@ -2,23 +2,30 @@ package app
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/controller"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/controller/command"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/usecase"
 	eventjournal "github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/usecase/event-journal"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
 )
 
 func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
+	// Kafka topics
+	if cfg.Messaging.DRIVER == "kafka" {
+		if err := admin.Ensure(cfg.Kafka.KAFKA_BROKER, Topics(cfg)); err != nil {
+			l.Fatal(fmt.Errorf("app - Run - admin.Ensure: %w", err))
+		}
+	}
+
 	kafkaBroker := cfg.Kafka.KAFKA_BROKER // os.Getenv("KAFKA_BROKER")      // e.g., "kafka:9092"
 	l.Error("KAFKA_BROKER")
 	l.Error(kafkaBroker)
@@ -28,20 +35,22 @@ func Run(cfg *config.Config) {
 
 	/*retryTopic := cfg.Kafka.RETRY_TOPIC
 	l.Error("RETRY_TOPIC")
-	l.Error(retryTopic)
+	l.Error(retryTopic) */
+
+	dlqTopic := cfg.Kafka.COMMAND_DLQ_TOPIC
 
-	dlqTopic := cfg.Kafka.DLQ_TOPIC
-	l.Error("DLQ_TOPIC")
-	l.Error(dlqTopic) */
+	commandTopic := cfg.Kafka.COMMAND_QUEUE_TOPIC
+	l.Error("COMMAND-QUEUE")
+	l.Error(commandTopic)
 
-	// Initialize Kafka Evenjournall*****
-	kafkaProducer, err := producer.NewKafkaProducer(kafkaBroker)
+	// Initialize publisher (event-journal + command-queue) and subscriber (command-queue)
+	publisher, subscriber, err := newMessaging(cfg)
 	if err != nil {
-		l.Fatal(" Failed to initialize Kafka producer: %v", err)
+		l.Fatal(fmt.Errorf("app - Run - newMessaging: %w", err))
 	}
-	defer kafkaProducer.Close() // Ensure producer is closed on shutdown
+	defer publisher.Close() // Ensure publisher is closed on shutdown
 
-	eventJournal := eventjournal.NewKafkaEventJournal(kafkaProducer, eventTopic)
+	eventJournal := eventjournal.NewEventJournal(publisher, eventTopic)
 
 	assetUseCase := usecase.NewAssetUseCase(
 		eventJournal,
@@ -55,7 +64,7 @@ func Run(cfg *config.Config) {
 	}
 	defer kafkaRetryProducer.Close()
 
-	retryProducer := producer.NewKafkaRetryEventProducer(kafkaRetryProducer, retryTopic)
+	retryProducer := controller.NewRetryEventProducer(kafkaRetryProducer, retryTopic)
 
 	// Initialize DLQ Kafka Producer
 	kafkaDlqProducer, err := producer.NewKafkaProducer(kafkaBroker)
@@ -64,55 +73,34 @@ func Run(cfg *config.Config) {
 	}
 	defer kafkaDlqProducer.Close()
 
-	dlqProducer := producer.NewDLQEventProducer(kafkaDlqProducer, dlqTopic) */
+	dlqProducer := controller.NewDLQEventProducer(kafkaDlqProducer, dlqTopic) */
 
 	/**********************************************************************************/
 
-	commandTopic := cfg.Kafka.COMMAND_QUEUE_TOPIC
-	l.Error("COMMAND-QUEUE")
-	l.Error(commandTopic)
-
-	kafkaGroupID := "asset-processor-group" // Consumer group ID
-	// Initialize Kafka consumer (command-queue)
-	consumer, err := consumer.NewKafkaConsumer(kafkaBroker, kafkaGroupID, commandTopic)
-	if err != nil {
-		log.Fatal("Failed to initialize Kafka consumer", "error", err)
-
-	}
-
-	// Initialize Kafka Producer (command-queue)
-	commandKafkaProducer, err := producer.NewKafkaProducer(kafkaBroker)
-	if err != nil {
-		l.Fatal(" Failed to initialize Kafka producer: %v", err)
-	}
-	defer commandKafkaProducer.Close() // Ensure producer is closed on shutdown
-
-	commandQueue := command.NewCommandProducer(commandKafkaProducer, commandTopic)
+	// Rescheduled commands and dead letters are written in the same transaction as the events
+	commandQueue := command.NewCommandProducer(publisher, commandTopic)
+	dlqProducer := controller.NewDLQEventProducer(publisher, dlqTopic)
 
 	// Initialize use case (business logic handler)
 
-	commandHandlerUsecase := usecase.NewCommandHandler(commandQueue, assetUseCase, l)
+	commandHandlerUsecase := usecase.NewCommandHandler(commandQueue, assetUseCase, dlqProducer, l)
 
-	commandConsumer := command.NewCommandConsumer(consumer, commandHandlerUsecase, l)
-	if err != nil {
-		log.Fatal("Failed to initialize Kafka consumer", "error", err)
-	}
+	commandConsumer := command.NewCommandConsumer(subscriber, commandHandlerUsecase, l)
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	commandConsumer.Start(ctx)
 
 	// Handle system signals for shutdown
-	go handleShutdown(cancel, commandConsumer, l)
+	go handleShutdown(cancel, l)
 	// Start consuming events
 	l.Info("Starting Event Consumer...")
 	commandConsumer.Start(ctx)
-
+	commandConsumer.Close()
 }
 
 // handleShutdown gracefully handles shutdown signals.
-func handleShutdown(cancel context.CancelFunc, consumer *command.CommandConsumer, log logger.Interface) {
+func handleShutdown(cancel context.CancelFunc, log logger.Interface) {
 	// Capture system signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -120,9 +108,6 @@ func handleShutdown(cancel context.CancelFunc, consumer *command.CommandConsumer
 	<-sigChan
 	log.Info("Shutdown signal received")
 
-	// Cancel context and close resources
+	// Cancel context, the consumer loop closes its resources on return
 	cancel()
-	consumer.Close()
-
-	log.Info("Consumer stopped")
 }
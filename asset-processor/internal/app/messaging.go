@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging/pgqueue"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const _consumerGroupID = "asset-processor-group"
+
+// newMessaging creates the publisher (event-journal + command-queue) and the
+// command-queue subscriber of the configured driver.
+func newMessaging(cfg *config.Config) (messaging.Publisher, messaging.Subscriber, error) {
+	switch cfg.Messaging.DRIVER {
+	case "kafka":
+		return newKafkaMessaging(cfg)
+	case "postgres":
+		queue, err := newQueue(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return queue.Publisher(), queue.Subscriber(_consumerGroupID, cfg.Kafka.COMMAND_QUEUE_TOPIC), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown messaging driver: %s", cfg.Messaging.DRIVER)
+	}
+}
+
+// newKafkaMessaging uses a transactional producer, the events of a command and
+// the command offset are committed together.
+func newKafkaMessaging(cfg *config.Config) (messaging.Publisher, messaging.Subscriber, error) {
+	kafkaProducer, err := producer.NewKafkaProducer(cfg.Kafka.KAFKA_BROKER, producer.WithTransactionalID(cfg.Kafka.TRANSACTIONAL_ID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("producer.NewKafkaProducer: %w", err)
+	}
+
+	if err := kafkaProducer.InitTransactions(context.Background()); err != nil {
+		kafkaProducer.Close()
+		return nil, nil, fmt.Errorf("kafkaProducer.InitTransactions: %w", err)
+	}
+
+	kafkaConsumer, err := consumer.NewKafkaConsumer(cfg.Kafka.KAFKA_BROKER, _consumerGroupID, cfg.Kafka.COMMAND_QUEUE_TOPIC,
+		consumer.WithAutoCommit(false),
+		consumer.WithReadCommitted(),
+	)
+	if err != nil {
+		kafkaProducer.Close()
+		return nil, nil, fmt.Errorf("consumer.NewKafkaConsumer: %w", err)
+	}
+
+	return kafkaProducer, consumer.NewTransactionalConsumer(kafkaConsumer, kafkaProducer), nil
+}
+
+// newQueue connects to the Postgres queue database. The pool lives as long as
+// the service.
+func newQueue(cfg *config.Config) (*pgqueue.Queue, error) {
+	pg, err := postgres.New(cfg.Messaging.PG_URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		return nil, fmt.Errorf("postgres.New: %w", err)
+	}
+
+	queue, err := pgqueue.New(pg, queueRetention(cfg)...)
+	if err != nil {
+		pg.Close()
+		return nil, fmt.Errorf("pgqueue.New: %w", err)
+	}
+
+	return queue, nil
+}
+
+// queueRetention gives the topics of the queue the retention of their Kafka topics.
+func queueRetention(cfg *config.Config) []pgqueue.Option {
+	var opts []pgqueue.Option
+	for _, topic := range Topics(cfg) {
+		if topic.RetentionMs > 0 {
+			opts = append(opts, pgqueue.Retention(topic.Name, time.Duration(topic.RetentionMs)*time.Millisecond))
+		}
+	}
+	return opts
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+)
+
+const _retention7d = int64(7 * 24 * time.Hour / time.Millisecond)
+
+// Topics declares the Kafka topics the service depends on.
+func Topics(cfg *config.Config) []admin.TopicSpec {
+	return []admin.TopicSpec{
+		{
+			// Commands with the same key share a partition and are processed in order
+			Name:              cfg.Kafka.COMMAND_QUEUE_TOPIC,
+			Partitions:        6,
+			ReplicationFactor: cfg.Kafka.REPLICATION_FACTOR,
+			RetentionMs:       _retention7d,
+			CleanupPolicy:     admin.CleanupDelete,
+		},
+		{
+			// The event journal is the source of truth, events are never deleted
+			Name:              cfg.Kafka.EVENT_TOPIC,
+			Partitions:        6,
+			ReplicationFactor: cfg.Kafka.REPLICATION_FACTOR,
+			RetentionMs:       admin.RetentionForever,
+			CleanupPolicy:     admin.CleanupDelete,
+		},
+		{
+			// Dead letters are kept until they are inspected
+			Name:              cfg.Kafka.COMMAND_DLQ_TOPIC,
+			Partitions:        1,
+			ReplicationFactor: cfg.Kafka.REPLICATION_FACTOR,
+			RetentionMs:       admin.RetentionForever,
+			CleanupPolicy:     admin.CleanupDelete,
+		},
+	}
+}
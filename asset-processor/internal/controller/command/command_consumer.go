@@ -2,29 +2,49 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/usecase"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
+const _retryBackoff = time.Second
+
 type CommandConsumer struct {
-	reader  *consumer.KafkaConsumer
+	reader  messaging.Subscriber
 	handler usecase.CommandHandler
 	log     logger.Interface
 }
 
-func NewCommandConsumer(reader *consumer.KafkaConsumer, handler usecase.CommandHandler, log logger.Interface) (consumer *CommandConsumer) {
+func NewCommandConsumer(reader messaging.Subscriber, handler usecase.CommandHandler, log logger.Interface) (consumer *CommandConsumer) {
 	r := &CommandConsumer{reader, handler, log}
 	return r
 }
 
-// Start consuming events
+// Start consuming commands. With the Kafka driver each command, the events it
+// produces and its offset are committed in one transaction. A failed command
+// is consumed again after a short backoff, commands that can never be
+// processed are sent to the DLQ by the handler.
 func (c *CommandConsumer) Start(ctx context.Context) {
-	c.reader.Consume(c.handler.MsgfessageHandler)
+	for ctx.Err() == nil {
+		err := c.reader.Subscribe(ctx, func(ctx context.Context, msg messaging.Message) error {
+			return c.handler.MsgfessageHandler(ctx, msg.Key, msg.Value)
+		})
+		if err == nil {
+			return
+		}
+
+		c.log.Error(err, "CommandConsumer - Start - command failed")
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(_retryBackoff):
+		}
+	}
 }
 
-// Close the Kafka consumer
+// Close the subscriber
 func (c *CommandConsumer) Close() {
 	c.reader.Close()
 }
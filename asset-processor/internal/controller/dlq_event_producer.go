@@ -4,24 +4,26 @@ import (
 	"context"
 	"log"
 
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
 type DLQEventProducer struct {
-	producer *producer.KafkaProducer
-	topic    string
+	publisher messaging.Publisher
+	topic     string
 }
 
-// KafkaEventProducer creates a new Kafka-based event journal.
-func NewDLQEventProducer(kafkaProducer *producer.KafkaProducer, topic string) *DLQEventProducer {
+// NewDLQEventProducer creates a new dead letter queue producer.
+func NewDLQEventProducer(publisher messaging.Publisher, topic string) *DLQEventProducer {
 	return &DLQEventProducer{
-		producer: kafkaProducer,
-		topic:    topic,
+		publisher: publisher,
+		topic:     topic,
 	}
 }
 
-func (e *DLQEventProducer) PublishDLQEvent(ctx context.Context, event []byte) error {
-	err := e.producer.ProduceEvent(e.topic, event, "")
+// PublishDLQEvent publishes a command that can never be processed with the reason in
+// its error header.
+func (e *DLQEventProducer) PublishDLQEvent(ctx context.Context, key, event []byte, reason string) error {
+	err := e.publisher.Publish(ctx, messaging.Message{Topic: e.topic, Key: key, Value: event, Headers: map[string]string{"error": reason}})
 	if err != nil {
 		log.Printf("Failed to publish DLQ event: %v", err)
 		return err
@@ -4,25 +4,25 @@ import (
 	"context"
 	"log"
 
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
-// KafkaEventProducer is the implementation of EventJournal using Kafka.
-type KafkaRetryEventProducer struct {
-	producer *producer.KafkaProducer
-	topic    string
+// RetryEventProducer publishes failed events to the retry topic.
+type RetryEventProducer struct {
+	publisher messaging.Publisher
+	topic     string
 }
 
-// KafkaEventProducer creates a new Kafka-based event journal.
-func NewKafkaRetryEventProducer(kafkaProducer *producer.KafkaProducer, topic string) *KafkaRetryEventProducer {
-	return &KafkaRetryEventProducer{
-		producer: kafkaProducer,
-		topic:    topic,
+// NewRetryEventProducer creates a new retry event producer.
+func NewRetryEventProducer(publisher messaging.Publisher, topic string) *RetryEventProducer {
+	return &RetryEventProducer{
+		publisher: publisher,
+		topic:     topic,
 	}
 }
 
-func (e *KafkaRetryEventProducer) PublishRetryEvent(ctx context.Context, event []byte) error {
-	err := e.producer.ProduceEvent(e.topic, event, "")
+func (e *RetryEventProducer) PublishRetryEvent(ctx context.Context, event []byte) error {
+	err := e.publisher.Publish(ctx, messaging.Message{Topic: e.topic, Value: event})
 	if err != nil {
 		log.Printf("Failed to publish retry event: %v", err)
 		return err
@@ -30,12 +30,15 @@ type ScheduledTransactionRequest struct {
 
 // WalletEvent represents an event in the event journal
 type WalletEvent struct {
-	EventID   string  `json:"event_id"`   // Unique event identifier
-	WalletID  int     `json:"wallet_id"`  // Associated wallet
-	AssetName string  `json:"asset_name"` // Asset being transacted
-	Type      string  `json:"type"`       // "withdraw", "deposit", "transfer"
-	Amount    float64 `json:"amount"`     // Transaction amount
-	Timestamp int64   `json:"timestamp"`  // Event time (Unix)
+	EventID        string  `json:"event_id"`                   // Unique event identifier
+	CommandID      string  `json:"command_id,omitempty"`       // Command the event was emitted for
+	WalletID       int     `json:"wallet_id"`                  // Associated wallet, the sender of a transfer
+	TargetWalletID int     `json:"target_wallet_id,omitempty"` // Receiver of a transfer
+	AssetName      string  `json:"asset_name"`                 // Asset being transacted
+	Type           string  `json:"type"`                       // "withdraw", "deposit", "transfer", "transfer_scheduled"
+	Amount         float64 `json:"amount"`                     // Transaction amount
+	Timestamp      int64   `json:"timestamp"`                  // Event time (Unix)
+	ExecuteTime    int64   `json:"execute_time,omitempty"`     // Execution time (Unix) of a transfer_scheduled
 }
 
 // ScheduledTransactionEvent represents an event for scheduled transactions
@@ -89,9 +92,10 @@ type Command struct {
 // TransferCommand transfer işlemleri için
 type TransferCommand struct {
 	BaseCommand           // TransferCommand, BaseCommand'dan özellikleri devralır
-	FromWallet  int       `json:"from_wallet"`  // Kaynak cüzdan ID'si
-	ToWallet    int       `json:"to_wallet"`    // Hedef cüzdan ID'si
-	ExecuteTime int64     `json:"execute_time"` // Unix zaman damgası
-	Status      string    `json:"status"`       // "scheduled" veya "executed"
-	CreatedAt   time.Time `json:"created_at"`   // Komutun oluşturulma zamanı
+	FromWallet  int       `json:"from_wallet"`         // Kaynak cüzdan ID'si
+	ToWallet    int       `json:"to_wallet"`           // Hedef cüzdan ID'si
+	ExecuteTime int64     `json:"execute_time"`        // Unix zaman damgası
+	Status      string    `json:"status"`              // "scheduled" veya "executed"
+	CreatedAt   time.Time `json:"created_at"`          // Komutun oluşturulma zamanı
+	Scheduled   bool      `json:"scheduled,omitempty"` // The transfer_scheduled event has been published
 }
@@ -25,9 +25,10 @@ func NewAssetUseCase(eventJournal EventJournal, l logger.Interface) *AssetUseCas
 }
 
 // Withdraw funds from a wallet (Publishes event)
-func (uc *AssetUseCase) Withdraw(ctx context.Context, walletID int, assetName string, amount float64) error {
+func (uc *AssetUseCase) Withdraw(ctx context.Context, commandID string, walletID int, assetName string, amount float64) error {
 	event := entity.WalletEvent{
 		EventID:   uuid.New().String(),
+		CommandID: commandID,
 		WalletID:  walletID,
 		Type:      "withdraw",
 		AssetName: assetName,
@@ -44,10 +45,11 @@ func (uc *AssetUseCase) Withdraw(ctx context.Context, walletID int, assetName st
 }
 
 // Deposit funds into a wallet (Publishes event)
-func (uc *AssetUseCase) Deposit(ctx context.Context, walletID int, assetName string, amount float64) error {
+func (uc *AssetUseCase) Deposit(ctx context.Context, commandID string, walletID int, assetName string, amount float64) error {
 	//wallet check , ya header üzerinden teyitli geldiğini var sayabiliriz ya da httpcall ve ya readonly bir check yapabiliriz
 	event := entity.WalletEvent{
 		EventID:   uuid.New().String(),
+		CommandID: commandID,
 		WalletID:  walletID,
 		Type:      "deposit",
 		AssetName: assetName,
@@ -62,3 +64,47 @@ func (uc *AssetUseCase) Deposit(ctx context.Context, walletID int, assetName str
 	uc.log.Info("Deposit event published", "WalletID", walletID, "AssetName", assetName, "Amount", amount)
 	return nil
 }
+
+// Transfer funds between wallets (Publishes a single event for both wallets)
+func (uc *AssetUseCase) Transfer(ctx context.Context, commandID string, fromWalletID, toWalletID int, assetName string, amount float64) error {
+	event := entity.WalletEvent{
+		EventID:        uuid.New().String(),
+		CommandID:      commandID,
+		WalletID:       fromWalletID,
+		TargetWalletID: toWalletID,
+		Type:           "transfer",
+		AssetName:      assetName,
+		Amount:         amount,
+		Timestamp:      time.Now().Unix(),
+	}
+
+	if err := uc.eventJournal.PublishEvent(ctx, event); err != nil {
+		return fmt.Errorf("Transfer - PublishEvent: %w", err)
+	}
+
+	uc.log.Info("Transfer event published", "FromWalletID", fromWalletID, "ToWalletID", toWalletID, "AssetName", assetName, "Amount", amount)
+	return nil
+}
+
+// ScheduleTransfer announces a transfer that is executed later (Publishes event). The
+// transfer event itself is published at execution time for the same command.
+func (uc *AssetUseCase) ScheduleTransfer(ctx context.Context, commandID string, fromWalletID, toWalletID int, assetName string, amount float64, executeTime int64) error {
+	event := entity.WalletEvent{
+		EventID:        uuid.New().String(),
+		CommandID:      commandID,
+		WalletID:       fromWalletID,
+		TargetWalletID: toWalletID,
+		Type:           "transfer_scheduled",
+		AssetName:      assetName,
+		Amount:         amount,
+		Timestamp:      time.Now().Unix(),
+		ExecuteTime:    executeTime,
+	}
+
+	if err := uc.eventJournal.PublishEvent(ctx, event); err != nil {
+		return fmt.Errorf("ScheduleTransfer - PublishEvent: %w", err)
+	}
+
+	uc.log.Info("Transfer scheduled event published", "FromWalletID", fromWalletID, "ToWalletID", toWalletID, "AssetName", assetName, "Amount", amount, "ExecuteTime", executeTime)
+	return nil
+}
@@ -2,29 +2,35 @@ package usecase
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/events"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
 )
 
+// ErrInvalidCommand is returned for commands that can never be processed. They are sent to
+// the DLQ instead of being consumed again.
+var ErrInvalidCommand = errors.New("invalid command")
+
 type commandHandler struct {
 	log          logger.Interface
 	assetUseCase AssetUseCaseHandler
 	commandQueue CommandProducerHandler
+	dlq          DLQEventProducer
 	handlers     map[string]func(ctx context.Context, data []byte) error
 }
 
 // NewCommandHandler initializes the command handler and maps command types to their corresponding functions.
-func NewCommandHandler(commandQueue CommandProducerHandler, assetUseCase AssetUseCaseHandler, l logger.Interface) *commandHandler {
+func NewCommandHandler(commandQueue CommandProducerHandler, assetUseCase AssetUseCaseHandler, dlq DLQEventProducer, l logger.Interface) *commandHandler {
 	h := &commandHandler{
 		log:          l,
 		assetUseCase: assetUseCase,
 		commandQueue: commandQueue,
+		dlq:          dlq,
 	}
 
 	// Initialize command handlers map
@@ -37,28 +43,37 @@ func NewCommandHandler(commandQueue CommandProducerHandler, assetUseCase AssetUs
 	return h
 }
 
-// MsgfessageHandler is the main entry point for handling messages.
-func (h *commandHandler) MsgfessageHandler(key, value []byte) error {
+// MsgfessageHandler is the main entry point for handling messages. A command that can never
+// be processed is sent to the DLQ, with the Kafka driver in the transaction that commits its
+// offset. Any other failure is returned, so the command is consumed again.
+func (h *commandHandler) MsgfessageHandler(ctx context.Context, key, value []byte) error {
 	h.log.Info("Received message", "key", string(key))
 
-	if len(value) == 0 {
-		return fmt.Errorf("empty message value")
+	err := h.handle(ctx, value)
+	if errors.Is(err, ErrInvalidCommand) {
+		h.log.Error(err, "Sending command to DLQ")
+		return h.dlq.PublishDLQEvent(ctx, key, value, err.Error())
 	}
+	return err
+}
 
-	ctx := context.Background()
+func (h *commandHandler) handle(ctx context.Context, value []byte) error {
+	if len(value) == 0 {
+		return fmt.Errorf("%w: empty message value", ErrInvalidCommand)
+	}
 
-	// Decode Base64 message
-	decodedValue, err := base64.StdEncoding.DecodeString(strings.Trim(string(value), "\""))
+	// Decode the message payload
+	decodedValue, err := events.DecodePayload(value)
 	if err != nil {
 		h.log.Error(err, "Base64 decode error")
-		return err
+		return fmt.Errorf("%w: base64 decode: %v", ErrInvalidCommand, err)
 	}
 
 	// Extract the base command to determine its type
 	var baseCommand entity.BaseCommand
 	if err := json.Unmarshal(decodedValue, &baseCommand); err != nil {
 		h.log.Error(err, "Failed to unmarshal BaseCommand")
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidCommand, err)
 	}
 
 	// Find and execute the appropriate handler
@@ -66,7 +81,7 @@ func (h *commandHandler) MsgfessageHandler(key, value []byte) error {
 		return handler(ctx, decodedValue)
 	}
 
-	return fmt.Errorf("unknown command type: %s", baseCommand.Type)
+	return fmt.Errorf("%w: unknown command type: %s", ErrInvalidCommand, baseCommand.Type)
 }
 
 // **Command Handlers**
@@ -75,7 +90,7 @@ func (h *commandHandler) handleWithdrawCommand(ctx context.Context, data []byte)
 	var command entity.Command
 	if err := json.Unmarshal(data, &command); err != nil {
 		h.log.Error(err, "Failed to unmarshal withdraw command")
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidCommand, err)
 	}
 
 	h.log.Info("Processing withdraw command",
@@ -84,7 +99,7 @@ func (h *commandHandler) handleWithdrawCommand(ctx context.Context, data []byte)
 		"Amount", command.Amount,
 	)
 
-	if err := h.assetUseCase.Withdraw(ctx, command.WalletID, command.AssetName, command.Amount); err != nil {
+	if err := h.assetUseCase.Withdraw(ctx, command.CommandID, command.WalletID, command.AssetName, command.Amount); err != nil {
 		h.log.Error(err, "Withdraw failed")
 		return fmt.Errorf("handleWithdrawCommand: %w", err)
 	}
@@ -97,7 +112,7 @@ func (h *commandHandler) handleDepositCommand(ctx context.Context, data []byte)
 	var command entity.Command
 	if err := json.Unmarshal(data, &command); err != nil {
 		h.log.Error(err, "Failed to unmarshal deposit command")
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidCommand, err)
 	}
 
 	h.log.Info("Processing deposit command",
@@ -106,7 +121,7 @@ func (h *commandHandler) handleDepositCommand(ctx context.Context, data []byte)
 		"Amount", command.Amount,
 	)
 
-	if err := h.assetUseCase.Deposit(ctx, command.WalletID, command.AssetName, command.Amount); err != nil {
+	if err := h.assetUseCase.Deposit(ctx, command.CommandID, command.WalletID, command.AssetName, command.Amount); err != nil {
 		h.log.Error(err, "Deposit failed")
 		return fmt.Errorf("handleDepositCommand: %w", err)
 	}
@@ -119,7 +134,7 @@ func (h *commandHandler) handleTransferCommand(ctx context.Context, data []byte)
 	var command entity.TransferCommand
 	if err := json.Unmarshal(data, &command); err != nil {
 		h.log.Error(err, "Failed to unmarshal transfer command")
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidCommand, err)
 	}
 
 	h.log.Info("Processing transfer command",
@@ -137,6 +152,16 @@ func (h *commandHandler) handleTransferCommand(ctx context.Context, data []byte)
 		h.log.Info("Execute time is more than 10 minutes in the future. Re-scheduling command",
 			"CommandID", command.CommandID,
 		)
+
+		// Announce the transfer to the read model once, the re-scheduled command carries the flag
+		if !command.Scheduled {
+			if err := h.assetUseCase.ScheduleTransfer(ctx, command.CommandID, command.FromWallet, command.ToWallet, command.AssetName, command.Amount, command.ExecuteTime); err != nil {
+				h.log.Error(err, "Failed to publish transfer scheduled event")
+				return fmt.Errorf("handleTransferCommand - ScheduleTransfer: %w", err)
+			}
+			command.Scheduled = true
+		}
+
 		if err := h.commandQueue.PublishCommand(ctx, command); err != nil {
 			h.log.Error(err, "Failed to re-schedule transfer command")
 			return fmt.Errorf("handleTransferCommand - Retry PublishCommand: %w", err)
@@ -144,14 +169,9 @@ func (h *commandHandler) handleTransferCommand(ctx context.Context, data []byte)
 		return nil
 	}
 
-	if err := h.assetUseCase.Withdraw(ctx, command.FromWallet, command.AssetName, command.Amount); err != nil {
-		h.log.Error(err, "Withdraw failed")
-		return fmt.Errorf("handleTransferCommand - Withdraw: %w", err)
-	}
-
-	if err := h.assetUseCase.Deposit(ctx, command.ToWallet, command.AssetName, command.Amount); err != nil {
-		h.log.Error(err, "Deposit failed")
-		return fmt.Errorf("handleTransferCommand - Deposit: %w", err)
+	if err := h.assetUseCase.Transfer(ctx, command.CommandID, command.FromWallet, command.ToWallet, command.AssetName, command.Amount); err != nil {
+		h.log.Error(err, "Transfer failed")
+		return fmt.Errorf("handleTransferCommand - Transfer: %w", err)
 	}
 
 	h.log.Info("Transfer command processed successfully")
@@ -7,31 +7,35 @@ import (
 	"log"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-processor/internal/entity"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
-// KafkaEventJournal is the implementation of EventJournal using Kafka.
-type KafkaEventJournal struct {
-	producer *producer.KafkaProducer
-	topic    string
+// EventJournal is the implementation of usecase.EventJournal on a message bus.
+type EventJournal struct {
+	publisher messaging.Publisher
+	topic     string
 }
 
-// NewKafkaEventJournal creates a new Kafka-based event journal.
-func NewKafkaEventJournal(kafkaProducer *producer.KafkaProducer, topic string) *KafkaEventJournal {
-	return &KafkaEventJournal{
-		producer: kafkaProducer,
-		topic:    topic,
+// NewEventJournal creates a new event journal publishing to topic.
+func NewEventJournal(publisher messaging.Publisher, topic string) *EventJournal {
+	return &EventJournal{
+		publisher: publisher,
+		topic:     topic,
 	}
 }
 
-// PublishEvent serializes and sends the event to Kafka.
-func (e *KafkaEventJournal) PublishEvent(ctx context.Context, event entity.WalletEvent) error {
+// PublishEvent serializes and sends the event to the event journal.
+func (e *EventJournal) PublishEvent(ctx context.Context, event entity.WalletEvent) error {
 	eventBytes, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to serialize event: %w", err)
 	}
 
-	err = e.producer.ProduceEvent(e.topic, eventBytes, fmt.Sprintf("wallet-%d", event.WalletID))
+	err = e.publisher.Publish(ctx, messaging.Message{
+		Topic: e.topic,
+		Key:   []byte(fmt.Sprintf("wallet-%d", event.WalletID)),
+		Value: eventBytes,
+	})
 	if err != nil {
 		log.Printf("Failed to publish event: %v", err)
 		return err
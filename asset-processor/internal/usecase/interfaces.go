@@ -7,10 +7,14 @@ import (
 )
 
 type (
-	/* Asset Management UseCase Interface */
+	/* Asset Management UseCase Interface, commandID is the command the event is emitted for */
 	AssetUseCaseHandler interface {
-		Withdraw(ctx context.Context, walletID int, assetName string, amount float64) error // Withdraw funds from a wallet
-		Deposit(ctx context.Context, walletID int, assetName string, amount float64) error  // Deposit funds into a wallet
+		Withdraw(ctx context.Context, commandID string, walletID int, assetName string, amount float64) error                 // Withdraw funds from a wallet
+		Deposit(ctx context.Context, commandID string, walletID int, assetName string, amount float64) error                  // Deposit funds into a wallet
+		Transfer(ctx context.Context, commandID string, fromWalletID, toWalletID int, assetName string, amount float64) error // Transfer funds between wallets
+
+		// Announce a transfer that executes later, at executeTime (Unix)
+		ScheduleTransfer(ctx context.Context, commandID string, fromWalletID, toWalletID int, assetName string, amount float64, executeTime int64) error
 	}
 
 	// EventJournal defines the contract for publishing events.
@@ -21,7 +25,7 @@ type (
 	/* Command Handler  UseCase Interface */
 	CommandHandler interface {
 		//ProcessCommand(ctx context.Context, command entity.Command) error
-		MsgfessageHandler(key, value []byte) error
+		MsgfessageHandler(ctx context.Context, key, value []byte) error
 	}
 
 	RetryEventProducer interface {
@@ -29,7 +33,7 @@ type (
 	}
 
 	DLQEventProducer interface {
-		PublishDLQEvent(ctx context.Context, event []byte) error
+		PublishDLQEvent(ctx context.Context, key, event []byte, reason string) error
 	}
 	// Command Queue defines the contract for publishing events.
 	CommandProducerHandler interface {
@@ -1,16 +1,53 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"os"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/config"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/app"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
 )
 
 func main() {
+	checkTopics := flag.Bool("check-topics", false, "report drift between the declared Kafka topics and the cluster, then exit")
+	rebuild := flag.Bool("rebuild", false, "replay the event journal into a new projection version, or into -version")
+	version := flag.Int("version", 0, "projection version to resume with -rebuild or to check with -reconcile")
+	activate := flag.Int("activate", 0, "make a projection version the one read by asset-query-service, then exit")
+	maxLag := flag.Int64("max-lag", 0, "events a version may be behind the end of the event journal to be activated, -1 to skip the check")
+	versions := flag.Bool("versions", false, "list the projection versions with their lag, then exit")
+	reconcile := flag.Bool("reconcile", false, "compare the balances of the read model with the event journal, then exit")
+	repair := flag.Bool("repair", false, "send balance_correction events for the mismatches found by -reconcile")
+	reportJSON := flag.String("report-json", "reconcile-report.json", "path of the JSON report of -reconcile")
+	reportCSV := flag.String("report-csv", "reconcile-report.csv", "path of the CSV report of -reconcile")
+	flag.Parse()
+
 	cfg, err := config.NewConfig()
 	if err != nil {
 		log.Fatalf("Config error: %s", err)
 	}
-	app.Run(cfg)
+
+	switch {
+	case *checkTopics:
+		if err := admin.Check(cfg.Kafka.KAFKA_BROKER, app.Topics(cfg), os.Stdout); err != nil {
+			log.Fatalf("Topic check: %s", err)
+		}
+	case *versions:
+		if err := app.ListVersions(cfg); err != nil {
+			log.Fatalf("Projection versions: %s", err)
+		}
+	case *reconcile:
+		if err := app.Reconcile(cfg, *version, *repair, *reportJSON, *reportCSV); err != nil {
+			log.Fatalf("Reconcile: %s", err)
+		}
+	case *activate > 0:
+		if err := app.Activate(cfg, *activate, *maxLag); err != nil {
+			log.Fatalf("Activate: %s", err)
+		}
+	case *rebuild:
+		app.Rebuild(cfg, *version)
+	default:
+		app.Run(cfg)
+	}
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -10,11 +11,13 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App   `yaml:"app"`
-		HTTP  `yaml:"http"`
-		Log   `yaml:"logger"`
-		PG    `yaml:"postgres"`
-		Kafka `yaml:"kafka"`
+		App       `yaml:"app"`
+		HTTP      `yaml:"http"`
+		Log       `yaml:"logger"`
+		PG        `yaml:"postgres"`
+		Kafka     `yaml:"kafka"`
+		Messaging `yaml:"messaging"`
+		Alerts    `yaml:"alerts"`
 	}
 
 	// App -.
@@ -40,10 +43,22 @@ type (
 	}
 
 	Kafka struct {
-		KAFKA_BROKER string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
-		EVENT_TOPIC  string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
-		RETRY_TOPIC  string `env-required:"true"  yaml:"RETRY_TOPIC"  env:"RETRY_TOPIC"`
-		DLQ_TOPIC    string `env-required:"true"  yaml:"DLQ_TOPIC"  env:"DLQ_TOPIC"`
+		KAFKA_BROKER       string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
+		EVENT_TOPIC        string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
+		RETRY_TOPIC        string `env-required:"true"  yaml:"RETRY_TOPIC"  env:"RETRY_TOPIC"`
+		DLQ_TOPIC          string `env-required:"true"  yaml:"DLQ_TOPIC"  env:"DLQ_TOPIC"`
+		REPLICATION_FACTOR int    `env-required:"true"  yaml:"REPLICATION_FACTOR"  env:"KAFKA_REPLICATION_FACTOR"`
+	}
+
+	// Messaging -.
+	Messaging struct {
+		DRIVER string `env-required:"true"  yaml:"DRIVER"  env:"MESSAGING_DRIVER"` // "kafka" or "postgres"
+		PG_URL string `yaml:"PG_URL"  env:"MESSAGING_PG_URL"`                      // Queue database of the "postgres" driver
+	}
+
+	// Alerts -.
+	Alerts struct {
+		PublishInterval time.Duration `env-default:"1s" yaml:"publish_interval" env:"ALERT_PUBLISH_INTERVAL"` // How often fired alerts are appended to the event journal
 	}
 )
 
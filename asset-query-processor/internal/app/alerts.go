@@ -0,0 +1,33 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// publishAlerts appends the fired alerts to the event journal every interval
+// until ctx is done. Alerts that fail to publish are retried on the next tick.
+func publishAlerts(ctx context.Context, alerts *usecase.AlertUseCase, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		n, err := alerts.PublishAlerts(ctx)
+		if n > 0 {
+			l.Info("Published %d alerts", n)
+		}
+		if err != nil && ctx.Err() == nil {
+			l.Error(fmt.Errorf("app - publishAlerts: %w", err))
+		}
+	}
+}
@@ -3,7 +3,6 @@ package app
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,9 +11,9 @@ import (
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/controller"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase/repo"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
 )
 
@@ -22,6 +21,13 @@ import (
 func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
+	// Kafka topics
+	if cfg.Messaging.DRIVER == "kafka" {
+		if err := admin.Ensure(cfg.Kafka.KAFKA_BROKER, Topics(cfg)); err != nil {
+			l.Fatal(fmt.Errorf("app - Run - admin.Ensure: %w", err))
+		}
+	}
+
 	// Repository
 	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
 	if err != nil {
@@ -31,6 +37,35 @@ func Run(cfg *config.Config) {
 
 	l.Error("postgresql ayakta.")
 
+	// Create context for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle system signals for shutdown
+	go handleShutdown(cancel, l)
+
+	// Project into the version read by asset-query-service, and switch to the
+	// next one when another version is activated
+	versions := repo.NewProjectionVersionRepo(pg)
+	for ctx.Err() == nil {
+		version, err := versions.ActiveVersion(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			l.Fatal(fmt.Errorf("app - Run - ActiveVersion: %w", err))
+		}
+
+		activeCtx, stop := whileActive(ctx, versions, l, func(active int) bool { return active == version })
+		project(activeCtx, cfg, l, pg, version, true)
+		stop()
+	}
+}
+
+// project consumes the event journal into the tables of a projection version
+// until ctx is done. Every version has its own consumer group and checkpoints.
+// Only the processor of the active version fires and publishes alerts.
+func project(ctx context.Context, cfg *config.Config, l logger.Interface, pg *postgres.Postgres, version int, alerts bool) {
 	// Kafka setup
 
 	kafkaBroker := cfg.Kafka.KAFKA_BROKER // os.Getenv("KAFKA_BROKER")      // e.g., "kafka:9092"
@@ -40,8 +75,6 @@ func Run(cfg *config.Config) {
 
 	l.Error("EVENT_TOPIC")
 	l.Error(eventTopic)
-	kafkaGroupID := "asset-query-processor-group" // Consumer group ID
-
 	retryTopic := cfg.Kafka.RETRY_TOPIC
 	l.Error("RETRY_TOPIC")
 	l.Error(retryTopic)
@@ -50,57 +83,59 @@ func Run(cfg *config.Config) {
 	l.Error("DLQ_TOPIC")
 	l.Error(dlqTopic)
 
-	// Initialize RETRY Kafka Producer
-	kafkaRetryProducer, err := producer.NewKafkaProducer(kafkaBroker)
-	if err != nil {
-		l.Fatal(" Failed to initialize Kafka producer: %v", err)
-	}
-	defer kafkaRetryProducer.Close()
-
-	retryProducer := controller.NewKafkaRetryEventProducer(kafkaRetryProducer, retryTopic)
-
-	// Initialize DLQ Kafka Producer
-	kafkaDlqProducer, err := producer.NewKafkaProducer(kafkaBroker)
+	// Initialize publisher (retry + dlq) and subscribers (event-journal + retry)
+	publisher, subscribers, err := newMessaging(cfg, consumerGroupID(version), eventTopic, retryTopic)
 	if err != nil {
-		l.Fatal(" Failed to initialize Kafka producer: %v", err)
+		l.Fatal(fmt.Errorf("app - project - newMessaging: %w", err))
 	}
-	defer kafkaDlqProducer.Close()
+	defer publisher.Close()
 
-	dlqProducer := controller.NewDLQEventProducer(kafkaDlqProducer, dlqTopic)
+	retryProducer := controller.NewRetryEventProducer(publisher, retryTopic)
+	dlqProducer := controller.NewDLQEventProducer(publisher, dlqTopic)
 
 	/**********************************************************************************/
 
-	// Initialize Kafka consumer
-	consumer, err := consumer.NewKafkaConsumer(kafkaBroker, kafkaGroupID, eventTopic)
-	if err != nil {
-		log.Fatal("Failed to initialize Kafka consumer", "error", err)
-
-	}
-
 	// Initialize use case (business logic handler)
-	queryRepo := repo.NewAssetQueryRepo(pg)
-	eventHandler := usecase.NewEventHandler(queryRepo, retryProducer, dlqProducer, l)
+	queryRepo := repo.NewAssetQueryRepo(pg, version)
+
+	// Resume from the checkpoints stored with the read model, not from the group offsets
+	for _, subscriber := range subscribers {
+		if resumer, ok := subscriber.(messaging.Resumer); ok {
+			if err := resumer.ResumeFrom(queryRepo.GetCheckpoint); err != nil {
+				l.Fatal(fmt.Errorf("app - project - ResumeFrom: %w", err))
+			}
+		}
+	}
 
-	eventConsumer := controller.NewEventConsumer(consumer, eventHandler, l)
-	if err != nil {
-		log.Fatal("Failed to initialize Kafka consumer", "error", err)
+	var alertEvaluator usecase.AlertEvaluator
+	if alerts {
+		alertUseCase := usecase.NewAlertUseCase(queryRepo, controller.NewJournalEventProducer(publisher, eventTopic), l)
+		go publishAlerts(ctx, alertUseCase, cfg.Alerts.PublishInterval, l)
+		alertEvaluator = alertUseCase
 	}
 
-	// Create context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	eventConsumer.Start(ctx)
+	eventHandler := usecase.NewEventHandler(queryRepo, alertEvaluator, retryProducer, dlqProducer, l)
 
-	// Handle system signals for shutdown
-	go handleShutdown(cancel, eventConsumer, l)
-	// Start consuming events
-	l.Info("Starting Event Consumer...")
+	eventConsumer := controller.NewEventConsumer(subscribers[0], eventHandler, l)
+	retryConsumer := controller.NewEventConsumer(subscribers[1], eventHandler, l)
+
+	// Start consuming events and their retries
+	l.Info("Starting Event Consumer for projection version %d...", version)
+	retried := make(chan struct{})
+	go func() {
+		defer close(retried)
+		retryConsumer.Start(ctx)
+	}()
 	eventConsumer.Start(ctx)
+	<-retried
+	eventConsumer.Close()
+	retryConsumer.Close()
 
+	l.Info("Consumer stopped")
 }
 
 // handleShutdown gracefully handles shutdown signals.
-func handleShutdown(cancel context.CancelFunc, consumer *controller.EventConsumer, log logger.Interface) {
+func handleShutdown(cancel context.CancelFunc, log logger.Interface) {
 	// Capture system signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -108,9 +143,6 @@ func handleShutdown(cancel context.CancelFunc, consumer *controller.EventConsume
 	<-sigChan
 	log.Info("Shutdown signal received")
 
-	// Cancel context and close resources
+	// Cancel context, the consumer loop closes its resources on return
 	cancel()
-	consumer.Close()
-
-	log.Info("Consumer stopped")
 }
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging/pgqueue"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const _consumerGroupID = "asset-query-processor-group"
+
+// consumerGroupID names the consumer group of a projection version, version 1
+// keeps the group that predates versioning.
+func consumerGroupID(version int) string {
+	if version <= 1 {
+		return _consumerGroupID
+	}
+	return fmt.Sprintf("%s-v%d", _consumerGroupID, version)
+}
+
+// newMessaging creates the publisher (retry + dlq) and one subscriber per topic
+// of the configured driver in consumer group groupID.
+func newMessaging(cfg *config.Config, groupID string, topics ...string) (messaging.Publisher, []messaging.Subscriber, error) {
+	subscribers := make([]messaging.Subscriber, 0, len(topics))
+
+	switch cfg.Messaging.DRIVER {
+	case "kafka":
+		kafkaProducer, err := producer.NewKafkaProducer(cfg.Kafka.KAFKA_BROKER)
+		if err != nil {
+			return nil, nil, fmt.Errorf("producer.NewKafkaProducer: %w", err)
+		}
+
+		for _, topic := range topics {
+			// Events of aborted asset-processor transactions are skipped
+			kafkaConsumer, err := consumer.NewKafkaConsumer(cfg.Kafka.KAFKA_BROKER, groupID, topic, consumer.WithReadCommitted())
+			if err != nil {
+				closeAll(subscribers)
+				kafkaProducer.Close()
+				return nil, nil, fmt.Errorf("consumer.NewKafkaConsumer: %w", err)
+			}
+			subscribers = append(subscribers, kafkaConsumer)
+		}
+
+		return kafkaProducer, subscribers, nil
+	case "postgres":
+		queue, err := newQueue(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, topic := range topics {
+			subscribers = append(subscribers, queue.Subscriber(groupID, topic))
+		}
+		return queue.Publisher(), subscribers, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown messaging driver: %s", cfg.Messaging.DRIVER)
+	}
+}
+
+func closeAll(subscribers []messaging.Subscriber) {
+	for _, s := range subscribers {
+		s.Close()
+	}
+}
+
+// endOffsets returns the offset of the next message written to every partition of a topic.
+type endOffsets func(ctx context.Context, topic string) (map[int32]int64, error)
+
+// newEndOffsets reads the end of topics from the configured driver.
+func newEndOffsets(cfg *config.Config) (endOffsets, error) {
+	switch cfg.Messaging.DRIVER {
+	case "kafka":
+		return func(ctx context.Context, topic string) (map[int32]int64, error) {
+			return admin.EndOffsets(ctx, cfg.Kafka.KAFKA_BROKER, topic)
+		}, nil
+	case "postgres":
+		queue, err := newQueue(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return queue.EndOffsets, nil
+	default:
+		return nil, fmt.Errorf("messaging driver %s has no end offsets", cfg.Messaging.DRIVER)
+	}
+}
+
+// newQueue connects to the Postgres queue database. The pool lives as long as
+// the service.
+func newQueue(cfg *config.Config) (*pgqueue.Queue, error) {
+	pg, err := postgres.New(cfg.Messaging.PG_URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		return nil, fmt.Errorf("postgres.New: %w", err)
+	}
+
+	queue, err := pgqueue.New(pg, queueRetention(cfg)...)
+	if err != nil {
+		pg.Close()
+		return nil, fmt.Errorf("pgqueue.New: %w", err)
+	}
+
+	return queue, nil
+}
+
+// queueRetention gives the topics of the queue the retention of their Kafka topics.
+func queueRetention(cfg *config.Config) []pgqueue.Option {
+	var opts []pgqueue.Option
+	for _, topic := range Topics(cfg) {
+		if topic.RetentionMs > 0 {
+			opts = append(opts, pgqueue.Retention(topic.Name, time.Duration(topic.RetentionMs)*time.Millisecond))
+		}
+	}
+	return opts
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/controller"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase/repo"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const _reconcileTimeout = 30 * time.Minute
+
+// Reconcile replays the event journal up to the checkpoints of a projection
+// version, the active one when version is zero, and compares the balances it
+// implies with the read model. The report is written to jsonPath and csvPath.
+// In repair mode every mismatch is compensated by a balance_correction event,
+// otherwise mismatches are returned as an error.
+func Reconcile(cfg *config.Config, version int, repair bool, jsonPath, csvPath string) error {
+	l := logger.New(cfg.Log.Level)
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		return fmt.Errorf("postgres.New: %w", err)
+	}
+	defer pg.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), _reconcileTimeout)
+	defer cancel()
+
+	go handleShutdown(cancel, l)
+
+	versions := repo.NewProjectionVersionRepo(pg)
+	if version == 0 {
+		if version, err = versions.ActiveVersion(ctx); err != nil {
+			return fmt.Errorf("ActiveVersion: %w", err)
+		}
+	} else if _, err := versions.GetVersion(ctx, version); err != nil {
+		if errors.Is(err, repo.ErrVersionNotFound) {
+			return fmt.Errorf("unknown projection version")
+		}
+		return fmt.Errorf("GetVersion: %w", err)
+	}
+
+	// A group of its own replays the journal from the start
+	publisher, subscribers, err := newMessaging(cfg, fmt.Sprintf("%s-reconcile-%d", _consumerGroupID, time.Now().Unix()), cfg.Kafka.EVENT_TOPIC)
+	if err != nil {
+		return fmt.Errorf("newMessaging: %w", err)
+	}
+	defer publisher.Close()
+	defer closeAll(subscribers)
+
+	reconcile := usecase.NewReconcileUseCase(
+		repo.NewAssetQueryRepo(pg, version),
+		controller.NewJournalEventProducer(publisher, cfg.Kafka.EVENT_TOPIC),
+		l,
+	)
+
+	replay, err := reconcile.Start(ctx, cfg.Kafka.EVENT_TOPIC)
+	if err != nil {
+		return err
+	}
+
+	l.Info("Replaying the event journal of projection version %d", version)
+	if !replay.Done() {
+		if err := replayJournal(ctx, subscribers[0], replay); err != nil {
+			return err
+		}
+	}
+
+	report := replay.Report()
+	l.Info("Replayed %d events, %d of %d balances differ", report.EventsReplayed, len(report.Mismatches), report.BalancesChecked)
+
+	if repair && len(report.Mismatches) > 0 {
+		if err := reconcile.Repair(ctx, report); err != nil {
+			return err
+		}
+		l.Info("Sent %d balance corrections to projection version %d", report.CorrectionsSent, version)
+	}
+
+	if err := writeReportJSON(jsonPath, report); err != nil {
+		return err
+	}
+	if err := writeReportCSV(csvPath, report); err != nil {
+		return err
+	}
+
+	if !repair && len(report.Mismatches) > 0 {
+		return fmt.Errorf("%d balances differ from the event journal, see %s", len(report.Mismatches), jsonPath)
+	}
+	return nil
+}
+
+// replayJournal feeds the journal to replay until it reaches the snapshot.
+func replayJournal(ctx context.Context, subscriber messaging.Subscriber, replay *usecase.Replay) error {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	err := subscriber.Subscribe(ctx, func(ctx context.Context, msg messaging.Message) error {
+		checkpoint := entity.Checkpoint{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset + 1}
+		if replay.Apply(checkpoint, msg.Value) {
+			stop()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Subscribe: %w", err)
+	}
+
+	if !replay.Done() {
+		return fmt.Errorf("replay stopped before reaching the read model: %w", context.Cause(ctx))
+	}
+	return nil
+}
+
+func writeReportJSON(path string, report *entity.ReconciliationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
+
+func writeReportCSV(path string, report *entity.ReconciliationReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("os.Create: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"wallet_id", "asset_name", "expected", "actual", "difference"}) //nolint:errcheck // checked by w.Error
+	for _, m := range report.Mismatches {
+		w.Write([]string{ //nolint:errcheck // checked by w.Error
+			strconv.Itoa(m.WalletID),
+			m.AssetName,
+			strconv.FormatFloat(m.Expected, 'f', -1, 64),
+			strconv.FormatFloat(m.Actual, 'f', -1, 64),
+			strconv.FormatFloat(m.Difference, 'f', -1, 64),
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("csv.Writer: %w", err)
+	}
+	return f.Close()
+}
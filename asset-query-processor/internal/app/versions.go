@@ -0,0 +1,264 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase/repo"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const (
+	_progressInterval       = 10 * time.Second
+	_versionsTimeout        = 30 * time.Second
+	_versionRefreshInterval = 5 * time.Second
+)
+
+// Rebuild replays the whole event journal into the shadow tables of a new
+// projection version, or resumes building version when it is not zero. It keeps
+// the version up to date until stopped and logs its progress and lag.
+func Rebuild(cfg *config.Config, version int) {
+	l := logger.New(cfg.Log.Level)
+
+	// Kafka topics
+	if cfg.Messaging.DRIVER == "kafka" {
+		if err := admin.Ensure(cfg.Kafka.KAFKA_BROKER, Topics(cfg)); err != nil {
+			l.Fatal(fmt.Errorf("app - Rebuild - admin.Ensure: %w", err))
+		}
+	}
+
+	// Repository
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Rebuild - postgres.New: %w", err))
+	}
+	defer pg.Close()
+
+	// Create context for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go handleShutdown(cancel, l)
+
+	versions := repo.NewProjectionVersionRepo(pg)
+	if version == 0 {
+		version, err = versions.CreateVersion(ctx)
+		if err != nil {
+			l.Fatal(fmt.Errorf("app - Rebuild - CreateVersion: %w", err))
+		}
+		l.Info("Created projection version %d", version)
+	} else if _, err := versions.GetVersion(ctx, version); err != nil {
+		l.Fatal(fmt.Errorf("app - Rebuild - GetVersion: %w", err))
+	}
+
+	progress, err := newJournalProgress(cfg, versions)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Rebuild - newJournalProgress: %w", err))
+	}
+	go reportProgress(ctx, progress, version, l)
+
+	// Once the version is activated asset-query-processor projects it
+	rebuildCtx, stop := whileActive(ctx, versions, l, func(active int) bool { return active != version })
+	defer stop()
+
+	project(rebuildCtx, cfg, l, pg, version, false)
+}
+
+// whileActive returns a context that is canceled once keep reports false for the active
+// projection version, which is re-read every _versionRefreshInterval.
+func whileActive(ctx context.Context, versions *repo.ProjectionVersionRepo, l logger.Interface, keep func(active int) bool) (context.Context, context.CancelFunc) {
+	ctx, stop := context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(_versionRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			active, err := versions.ActiveVersion(ctx)
+			if err != nil && ctx.Err() == nil {
+				l.Error(fmt.Errorf("app - whileActive - ActiveVersion: %w", err))
+			}
+			if err == nil && !keep(active) {
+				l.Info("Projection version %d is active", active)
+				stop()
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ctx, stop
+}
+
+// reportProgress logs the progress of a version until ctx is done, and once
+// when it has caught up with the event journal.
+func reportProgress(ctx context.Context, journal *journalProgress, version int, l logger.Interface) {
+	ticker := time.NewTicker(_progressInterval)
+	defer ticker.Stop()
+
+	var last int64
+	caughtUp := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		progress, err := journal.Progress(ctx, version)
+		if err != nil {
+			l.Error(fmt.Errorf("app - reportProgress - Progress: %w", err))
+			continue
+		}
+
+		rate := float64(progress.Consumed-last) / _progressInterval.Seconds()
+		last = progress.Consumed
+		l.Info("Rebuild of version %d: consumed %d, lag %d, %.1f events/s", version, progress.Consumed, progress.Lag, rate)
+
+		if progress.MinLag() == 0 && progress.Consumed > 0 && !caughtUp {
+			l.Info("Version %d has caught up, switch to it with -activate %d", version, version)
+		}
+		caughtUp = progress.MinLag() == 0
+	}
+}
+
+// Activate points asset-query-service at a projection version and retires the
+// active one. A version more than maxLag events behind the end of the event
+// journal is refused, a negative maxLag skips the check, e.g. to roll back to a
+// retired version.
+func Activate(cfg *config.Config, version int, maxLag int64) error {
+	return withVersions(cfg, func(ctx context.Context, versions *repo.ProjectionVersionRepo, journal *journalProgress) error {
+		if _, err := versions.GetVersion(ctx, version); err != nil {
+			return err
+		}
+
+		if maxLag >= 0 {
+			progress, err := journal.Progress(ctx, version)
+			if err != nil {
+				return err
+			}
+			if progress.MinLag() > maxLag {
+				return fmt.Errorf("version %d is %d events behind, at most %d allowed", version, progress.MinLag(), maxLag)
+			}
+		}
+
+		if err := versions.ActivateVersion(ctx, version); err != nil {
+			return err
+		}
+
+		fmt.Printf("Projection version %d is active\n", version)
+		return nil
+	})
+}
+
+// ListVersions prints the projection versions with their progress.
+func ListVersions(cfg *config.Config) error {
+	return withVersions(cfg, func(ctx context.Context, versions *repo.ProjectionVersionRepo, journal *journalProgress) error {
+		list, err := versions.ListVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSTATUS\tCONSUMED\tLAG\tCREATED\tACTIVATED")
+		for _, v := range list {
+			progress, err := journal.Progress(ctx, v.Version)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%s\n", v.Version, v.Status, progress.Consumed, progress.Lag,
+				v.CreatedAt.Format(time.RFC3339), activatedAt(v))
+		}
+		return w.Flush()
+	})
+}
+
+func activatedAt(v entity.ProjectionVersion) string {
+	if v.ActivatedAt == nil {
+		return "-"
+	}
+	return v.ActivatedAt.Format(time.RFC3339)
+}
+
+// withVersions runs fn with the projection versions of the query database.
+func withVersions(cfg *config.Config, fn func(ctx context.Context, versions *repo.ProjectionVersionRepo, journal *journalProgress) error) error {
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		return fmt.Errorf("postgres.New: %w", err)
+	}
+	defer pg.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), _versionsTimeout)
+	defer cancel()
+
+	versions := repo.NewProjectionVersionRepo(pg)
+	journal, err := newJournalProgress(cfg, versions)
+	if err != nil {
+		return fmt.Errorf("newJournalProgress: %w", err)
+	}
+
+	err = fn(ctx, versions, journal)
+	if errors.Is(err, repo.ErrVersionNotFound) {
+		return fmt.Errorf("unknown projection version")
+	}
+	return err
+}
+
+// journalProgress measures how far projection versions have projected the event journal,
+// against the end of its partitions.
+type journalProgress struct {
+	versions *repo.ProjectionVersionRepo
+	topic    string
+	ends     endOffsets
+	markers  bool // Kafka high watermarks count transaction markers
+}
+
+func newJournalProgress(cfg *config.Config, versions *repo.ProjectionVersionRepo) (*journalProgress, error) {
+	ends, err := newEndOffsets(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &journalProgress{versions: versions, topic: cfg.Kafka.EVENT_TOPIC, ends: ends, markers: cfg.Messaging.DRIVER == "kafka"}, nil
+}
+
+// Progress compares the checkpoints of a version with the end of every journal partition.
+func (j *journalProgress) Progress(ctx context.Context, version int) (entity.ProjectionProgress, error) {
+	progress := entity.ProjectionProgress{Version: version}
+
+	ends, err := j.ends(ctx, j.topic)
+	if err != nil {
+		return progress, fmt.Errorf("journalProgress - Progress - ends: %w", err)
+	}
+
+	checkpoints, err := j.versions.Checkpoints(ctx, version, j.topic)
+	if err != nil {
+		return progress, fmt.Errorf("journalProgress - Progress - Checkpoints: %w", err)
+	}
+
+	for partition, end := range ends {
+		next := checkpoints[partition]
+		progress.Consumed += next
+		if end <= next {
+			continue
+		}
+		progress.Lag += end - next
+		if j.markers {
+			progress.Markers++
+		}
+	}
+
+	return progress, nil
+}
@@ -2,29 +2,49 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/usecase"
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
+const _retryBackoff = time.Second
+
 type EventConsumer struct {
-	reader  *consumer.KafkaConsumer
+	reader  messaging.Subscriber
 	handler usecase.EventHandler
 	log     logger.Interface
 }
 
-func NewEventConsumer(reader *consumer.KafkaConsumer, handler usecase.EventHandler, log logger.Interface) (consumer *EventConsumer) {
+func NewEventConsumer(reader messaging.Subscriber, handler usecase.EventHandler, log logger.Interface) (consumer *EventConsumer) {
 	r := &EventConsumer{reader, handler, log}
 	return r
 }
 
-// Start consuming events
+// Start consuming events until ctx is done. A failed event is consumed again
+// after a short backoff.
 func (c *EventConsumer) Start(ctx context.Context) {
-	c.reader.Consume(c.handler.MsgfessageHandler)
+	for ctx.Err() == nil {
+		err := c.reader.Subscribe(ctx, func(ctx context.Context, msg messaging.Message) error {
+			checkpoint := entity.Checkpoint{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset + 1}
+			return c.handler.MsgfessageHandler(ctx, checkpoint, msg.Key, msg.Value, msg.Headers)
+		})
+		if err == nil {
+			return
+		}
+
+		c.log.Error(err, "EventConsumer - Start - event failed")
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(_retryBackoff):
+		}
+	}
 }
 
-// Close the Kafka consumer
+// Close the subscriber
 func (c *EventConsumer) Close() {
 	c.reader.Close()
 }
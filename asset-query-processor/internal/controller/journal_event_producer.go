@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+)
+
+// JournalEventProducer appends events to the event journal.
+type JournalEventProducer struct {
+	publisher messaging.Publisher
+	topic     string
+}
+
+// NewJournalEventProducer creates a new event journal producer.
+func NewJournalEventProducer(publisher messaging.Publisher, topic string) *JournalEventProducer {
+	return &JournalEventProducer{
+		publisher: publisher,
+		topic:     topic,
+	}
+}
+
+// PublishEvent keys the event by wallet like asset-processor, so it is ordered
+// with the other events of the wallet.
+func (e *JournalEventProducer) PublishEvent(ctx context.Context, event entity.WalletEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("JournalEventProducer - PublishEvent - json.Marshal: %w", err)
+	}
+
+	err = e.publisher.Publish(ctx, messaging.Message{
+		Topic: e.topic,
+		Key:   []byte(fmt.Sprintf("wallet-%d", event.WalletID)),
+		Value: value,
+	})
+	if err != nil {
+		return fmt.Errorf("JournalEventProducer - PublishEvent - Publish: %w", err)
+	}
+
+	return nil
+}
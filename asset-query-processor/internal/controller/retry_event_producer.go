@@ -4,42 +4,26 @@ import (
 	"context"
 	"log"
 
-	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
 )
 
-// KafkaEventProducer is the implementation of EventJournal using Kafka.
-type KafkaRetryEventProducer struct {
-	producer *producer.KafkaProducer
-	topic    string
+// RetryEventProducer publishes failed events to the retry topic.
+type RetryEventProducer struct {
+	publisher messaging.Publisher
+	topic     string
 }
 
-// KafkaEventProducer creates a new Kafka-based event journal.
-func NewKafkaRetryEventProducer(kafkaProducer *producer.KafkaProducer, topic string) *KafkaRetryEventProducer {
-	return &KafkaRetryEventProducer{
-		producer: kafkaProducer,
-		topic:    topic,
+// NewRetryEventProducer creates a new retry event producer.
+func NewRetryEventProducer(publisher messaging.Publisher, topic string) *RetryEventProducer {
+	return &RetryEventProducer{
+		publisher: publisher,
+		topic:     topic,
 	}
 }
 
-// PublishEvent serializes and sends the event to Kafka.
-/*func (e *KafkaRetryEventProducer) PublishRetryEvent(ctx context.Context, event entity.WalletEvent) error {
-	eventBytes, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
-	}
-
-	err = e.producer.ProduceEvent(e.topic, eventBytes, fmt.Sprintf("wallet-%d", event.WalletID))
-	if err != nil {
-		log.Printf("Failed to publish event: %v", err)
-		return err
-	}
-
-	log.Printf("Event published: %+v", event)
-	return nil
-} */
-
-func (e *KafkaRetryEventProducer) PublishRetryEvent(ctx context.Context, event []byte) error {
-	err := e.producer.ProduceEvent(e.topic, event, "")
+// PublishRetryEvent publishes a failed event with its key and retry headers.
+func (e *RetryEventProducer) PublishRetryEvent(ctx context.Context, key, event []byte, headers map[string]string) error {
+	err := e.publisher.Publish(ctx, messaging.Message{Topic: e.topic, Key: key, Value: event, Headers: headers})
 	if err != nil {
 		log.Printf("Failed to publish retry event: %v", err)
 		return err
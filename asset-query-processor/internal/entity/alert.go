@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Alert rule conditions
+const (
+	AlertBalanceBelow  = "balance_below"  // A debit leaves the balance under the threshold
+	AlertMovementAbove = "movement_above" // A single debit or credit exceeds the threshold
+)
+
+// AlertRule fires an alert event when a movement of a wallet asset meets its condition, at most
+// once per cooldown.
+type AlertRule struct {
+	ID          int64         `json:"id" db:"id"`
+	WalletID    int           `json:"wallet_id" db:"wallet_id"`
+	AssetName   string        `json:"asset_name" db:"asset_name"`
+	Condition   string        `json:"condition" db:"condition"`
+	Threshold   float64       `json:"threshold" db:"threshold"`
+	Cooldown    time.Duration `json:"cooldown" db:"cooldown_seconds"`
+	Description string        `json:"description" db:"description"`
+	LastFiredAt *time.Time    `json:"last_fired_at,omitempty" db:"last_fired_at"` // Event time of the last alert
+}
+
+// AlertCandidate is an enabled rule of a wallet asset an event moved, with the movement.
+type AlertCandidate struct {
+	Rule         AlertRule
+	Amount       float64 // Signed, negative for debits
+	BalanceAfter float64
+}
+
+// Triggered reports whether the movement meets the condition of the rule.
+func (c AlertCandidate) Triggered() bool {
+	switch c.Rule.Condition {
+	case AlertBalanceBelow:
+		return c.Amount < 0 && c.BalanceAfter < c.Rule.Threshold
+	case AlertMovementAbove:
+		return math.Abs(c.Amount) > c.Rule.Threshold
+	default:
+		return false
+	}
+}
+
+// CoolingDown reports whether the rule fired less than its cooldown before at.
+func (c AlertCandidate) CoolingDown(at time.Time) bool {
+	return c.Rule.LastFiredAt != nil && at.Before(c.Rule.LastFiredAt.Add(c.Rule.Cooldown))
+}
+
+// AlertDetails are the metadata of an alert event.
+type AlertDetails struct {
+	RuleID         int64   `json:"rule_id"`
+	Condition      string  `json:"condition"`
+	Threshold      float64 `json:"threshold"`
+	Balance        float64 `json:"balance"`          // Balance of the wallet asset after the movement
+	TriggerEventID string  `json:"trigger_event_id"` // Event of the movement
+	Description    string  `json:"description,omitempty"`
+}
@@ -13,22 +13,51 @@ type Event struct {
 
 // WalletEvent represents a single event in the event store.{"event_id":"c0b0b54a-6c49-4848-9b6a-1413564056c1","wallet_id":1,"asset_name":"BTC","type":"deposit","amount":100,"timestamp":1738492914}%
 type WalletEvent struct {
-	EventID   string  `json:"event_id" db:"event_id"`           // Unique identifier for the event
-	WalletID  int     `json:"wallet_id" db:"wallet_id"`         // Wallet associated with this event
-	AssetName string  `json:"asset_name" db:"asset_name"`       // asset name
-	Type      string  `json:"type" db:"type"`                   // Event type: "withdraw", "deposit", "transfer"
-	Amount    float64 `json:"amount" db:"amount"`               // Transaction amount
-	Timestamp int64   `json:"timestamp" db:"timestamp"`         // Unix timestamp when the event was created
-	Metadata  string  `json:"metadata,omitempty" db:"metadata"` // Optional JSON metadata (for extensibility)
+	EventID           string  `json:"event_id" db:"event_id"`                               // Unique identifier for the event
+	CommandID         string  `json:"command_id,omitempty" db:"command_id"`                 // Command the event was emitted for
+	WalletID          int     `json:"wallet_id" db:"wallet_id"`                             // Wallet associated with this event, the sender of a transfer
+	TargetWalletID    int     `json:"target_wallet_id,omitempty" db:"target_wallet_id"`     // Receiver of a transfer
+	AssetName         string  `json:"asset_name" db:"asset_name"`                           // asset name
+	Type              string  `json:"type" db:"type"`                                       // Event type: "withdraw", "deposit", "transfer", "balance_correction", "transfer_scheduled", "alert"
+	Amount            float64 `json:"amount" db:"amount"`                                   // Transaction amount, signed for a balance_correction
+	Timestamp         int64   `json:"timestamp" db:"timestamp"`                             // Unix timestamp when the event was created
+	ProjectionVersion int     `json:"projection_version,omitempty" db:"projection_version"` // Projection version a balance_correction is for
+	ExecuteTime       int64   `json:"execute_time,omitempty" db:"execute_time"`             // Execution time (Unix) of a transfer_scheduled
+	Metadata          string  `json:"metadata,omitempty" db:"metadata"`                     // Optional JSON metadata (for extensibility), the AlertDetails of an alert
 }
 
-// Transaction represents a financial operation on a wallet.
+// Transaction represents the effect of an event on one wallet asset.
 type Transaction struct {
-	ID        int       `json:"id" db:"transaction_id"`
-	WalletID  int       `json:"wallet_id" db:"wallet_id"`
-	Type      string    `json:"type" db:"type"` // Possible values: "withdraw", "deposit", "transfer"
-	Amount    float64   `json:"amount" db:"amount"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID                   int64     `json:"id" db:"transaction_id"`
+	EventID              string    `json:"event_id" db:"event_id"`
+	WalletID             int       `json:"wallet_id" db:"wallet_id"`
+	CounterpartyWalletID *int      `json:"counterparty_wallet_id,omitempty" db:"counterparty_wallet_id"` // The other wallet of a transfer
+	AssetName            string    `json:"asset_name" db:"asset_name"`
+	Type                 string    `json:"type" db:"type"`                   // Possible values: "withdraw", "deposit", "transfer"
+	Amount               float64   `json:"amount" db:"amount"`               // Signed, negative for debits
+	BalanceAfter         float64   `json:"balance_after" db:"balance_after"` // Balance of the wallet asset after the event
+	EventTime            time.Time `json:"event_time" db:"event_time"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+}
+
+// ScheduledTransfer is a transfer deferred to its execute time.
+type ScheduledTransfer struct {
+	CommandID    string     `json:"command_id" db:"command_id"` // Command of the transfer, the command_id of its transfer event
+	FromWalletID int        `json:"from_wallet_id" db:"from_wallet_id"`
+	ToWalletID   int        `json:"to_wallet_id" db:"to_wallet_id"`
+	AssetName    string     `json:"asset_name" db:"asset_name"`
+	Amount       float64    `json:"amount" db:"amount"`
+	ExecuteTime  time.Time  `json:"execute_time" db:"execute_time"`
+	Status       string     `json:"status" db:"status"` // "scheduled" or "executed"
+	ScheduledAt  time.Time  `json:"scheduled_at" db:"scheduled_at"`
+	ExecutedAt   *time.Time `json:"executed_at,omitempty" db:"executed_at"`
+}
+
+// Checkpoint is the position of the next event to project from a partition.
+type Checkpoint struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"` // Next offset to consume
 }
 
 /*
@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Statuses of a projection version.
+const (
+	VersionBuilding = "building" // Replaying the event journal, not read by asset-query-service
+	VersionActive   = "active"   // Read by asset-query-service
+	VersionRetired  = "retired"  // Replaced by a newer version, kept for rollback
+)
+
+// ProjectionVersion is one generation of the read model tables.
+type ProjectionVersion struct {
+	Version     int        `json:"version" db:"version"`
+	Status      string     `json:"status" db:"status"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty" db:"activated_at"`
+}
+
+// ProjectionProgress is how far a version has projected the event journal.
+type ProjectionProgress struct {
+	Version  int
+	Consumed int64 // Sum of the next offsets over all partitions
+	Lag      int64 // Messages behind the end of the event journal, summed over all partitions
+	Markers  int64 // Kafka transaction markers Lag may count, one per partition behind
+}
+
+// MinLag is the lag without the transaction markers it may count. A version that is
+// up to date has a MinLag of zero.
+func (p ProjectionProgress) MinLag() int64 {
+	return p.Lag - p.Markers
+}
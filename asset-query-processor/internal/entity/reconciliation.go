@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// BalanceKey identifies the balance of one asset in one wallet.
+type BalanceKey struct {
+	WalletID  int
+	AssetName string
+}
+
+// BalanceSnapshot is the read model of a projection version together with the
+// checkpoints it was projected up to, read in one transaction.
+type BalanceSnapshot struct {
+	Version     int
+	Balances    map[BalanceKey]float64
+	Checkpoints []Checkpoint
+}
+
+// Mismatch is a balance of the read model that differs from the replayed events.
+type Mismatch struct {
+	WalletID   int     `json:"wallet_id"`
+	AssetName  string  `json:"asset_name"`
+	Expected   float64 `json:"expected"`   // Balance implied by the event journal
+	Actual     float64 `json:"actual"`     // Balance in the read model
+	Difference float64 `json:"difference"` // Actual minus expected
+}
+
+// ReconciliationReport is the result of a reconciliation run.
+type ReconciliationReport struct {
+	GeneratedAt       time.Time    `json:"generated_at"`
+	ProjectionVersion int          `json:"projection_version"`
+	Checkpoints       []Checkpoint `json:"checkpoints"` // Position the journal was replayed up to
+	EventsReplayed    int          `json:"events_replayed"`
+	BalancesChecked   int          `json:"balances_checked"`
+	Mismatches        []Mismatch   `json:"mismatches"`
+	CorrectionsSent   int          `json:"corrections_sent"` // Set in repair mode
+}
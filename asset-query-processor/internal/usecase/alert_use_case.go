@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const _alertBatchSize = 100
+
+// AlertUseCase fires the alert rules of the wallet assets an event moves and appends the
+// alerts to the event journal, where webhook-service and notification-service pick them up.
+type AlertUseCase struct {
+	repo    AlertRepositoryHandler
+	journal EventJournal
+	log     logger.Interface
+}
+
+// NewAlertUseCase creates a new instance of AlertUseCase.
+func NewAlertUseCase(r AlertRepositoryHandler, journal EventJournal, l logger.Interface) *AlertUseCase {
+	return &AlertUseCase{repo: r, journal: journal, log: l}
+}
+
+// Evaluate fires the rules the movements of an event trigger, unless they are cooling down.
+// It runs in the projection transaction of the event, so an alert is queued exactly once and
+// only with the balance change that triggered it. Cooldowns count from event times, so a
+// lagging projection fires the same alerts as one that keeps up.
+func (uc *AlertUseCase) Evaluate(ctx context.Context, event entity.WalletEvent) error {
+	candidates, err := uc.repo.GetAlertCandidates(ctx, event.EventID)
+	if err != nil {
+		return fmt.Errorf("AlertUseCase - Evaluate - uc.repo.GetAlertCandidates: %w", err)
+	}
+
+	at := time.Unix(event.Timestamp, 0).UTC()
+	if event.Timestamp == 0 {
+		at = time.Now().UTC()
+	}
+
+	for _, c := range candidates {
+		if !c.Triggered() || c.CoolingDown(at) {
+			continue
+		}
+
+		alert, err := alertEvent(c, event, at)
+		if err != nil {
+			return fmt.Errorf("AlertUseCase - Evaluate - alertEvent: %w", err)
+		}
+		if err := uc.repo.FireAlert(ctx, c.Rule.ID, at, alert); err != nil {
+			return fmt.Errorf("AlertUseCase - Evaluate - uc.repo.FireAlert: %w", err)
+		}
+		uc.log.Info("Alert rule %d fired for wallet %d %s by event %s", c.Rule.ID, c.Rule.WalletID, c.Rule.AssetName, event.EventID)
+	}
+
+	return nil
+}
+
+// PublishAlerts appends the queued alerts to the event journal in batches until none are
+// left and returns how many were published.
+func (uc *AlertUseCase) PublishAlerts(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := uc.repo.PublishAlerts(ctx, _alertBatchSize, uc.journal.PublishEvent)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("AlertUseCase - PublishAlerts - uc.repo.PublishAlerts: %w", err)
+		}
+		if n < _alertBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// alertEvent builds the alert of a triggered rule. Its id is derived from the rule and the
+// triggering event, so a redelivered event cannot fire the rule twice.
+func alertEvent(c entity.AlertCandidate, trigger entity.WalletEvent, at time.Time) (entity.WalletEvent, error) {
+	metadata, err := json.Marshal(entity.AlertDetails{
+		RuleID:         c.Rule.ID,
+		Condition:      c.Rule.Condition,
+		Threshold:      c.Rule.Threshold,
+		Balance:        c.BalanceAfter,
+		TriggerEventID: trigger.EventID,
+		Description:    c.Rule.Description,
+	})
+	if err != nil {
+		return entity.WalletEvent{}, err
+	}
+
+	return entity.WalletEvent{
+		EventID:   alertID(c.Rule.ID, trigger.EventID),
+		WalletID:  c.Rule.WalletID,
+		AssetName: c.Rule.AssetName,
+		Type:      "alert",
+		Amount:    c.Amount,
+		Timestamp: at.Unix(),
+		Metadata:  string(metadata),
+	}, nil
+}
+
+// alertID is the deterministic event id of the alert a rule fires for an event.
+func alertID(ruleID int64, eventID string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d/%s", ruleID, eventID)))
+	return "alert-" + hex.EncodeToString(sum[:16])
+}
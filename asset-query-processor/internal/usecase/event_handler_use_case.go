@@ -2,24 +2,39 @@ package usecase
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/events"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
 )
 
+// Headers of the messages the projection hands over to the retry topic
+const (
+	_retryCountHeader = "retry-count"        // How many times the message has been retried
+	_versionHeader    = "projection-version" // Projection version that retries the message
+)
+
+// ErrInvalidEvent is returned for events that can never be projected. They are handed over
+// to the retry topic or the DLQ instead of being redelivered.
+var ErrInvalidEvent = errors.New("invalid event")
+
 type eventHandler struct {
-	repo  AssetQueryRepositoryHandler
-	log   logger.Interface
-	retry RetryEventProducer
-	dlq   DLQEventProducer
+	repo   AssetQueryRepositoryHandler
+	alerts AlertEvaluator
+	log    logger.Interface
+	retry  RetryEventProducer
+	dlq    DLQEventProducer
 }
 
-func NewEventHandler(r AssetQueryRepositoryHandler, retry RetryEventProducer, dlq DLQEventProducer, l logger.Interface) EventHandler {
-	return &eventHandler{repo: r, log: l, retry: retry, dlq: dlq}
+// NewEventHandler creates the projection handler of the event journal. alerts is optional,
+// without it no alert rules are evaluated, e.g. while a projection version is rebuilt.
+func NewEventHandler(r AssetQueryRepositoryHandler, alerts AlertEvaluator, retry RetryEventProducer, dlq DLQEventProducer, l logger.Interface) EventHandler {
+	return &eventHandler{repo: r, alerts: alerts, log: l, retry: retry, dlq: dlq}
 }
 
 // EventTypeHandler defines the function signature for handling events
@@ -27,65 +42,111 @@ type EventTypeHandler func(ctx context.Context, repo AssetQueryRepositoryHandler
 
 // EventHandlers maps event types to their corresponding handler functions
 var EventHandlers = map[string]EventTypeHandler{
-	"withdraw": handleWithdraw,
-	"deposit":  handleDeposit,
-	"transfer": handleTransfer,
+	"withdraw":           handleWithdraw,
+	"deposit":            handleDeposit,
+	"transfer":           handleTransfer,
+	"balance_correction": handleBalanceCorrection,
+	"transfer_scheduled": handleTransferScheduled,
+	"alert":              handleAlert,
 }
 
-func (h *eventHandler) MsgfessageHandler(key, value []byte) error {
+// MsgfessageHandler projects an event exactly once. The event, its id, the alerts it
+// fires and the checkpoint are written in one transaction, a redelivered event is a no-op.
+// Messages that cannot be decoded or are not valid events are handed over to the retry
+// topic or the DLQ, any other failure is returned so that the message is redelivered.
+func (h *eventHandler) MsgfessageHandler(ctx context.Context, checkpoint entity.Checkpoint, key, value []byte, headers map[string]string) error {
 	fmt.Printf("Received message with key: %s, value: %s\n", string(key), string(value))
 
-	ctx := context.Background()
+	// Retries of other projection versions share the retry topic
+	if owner, ok := headers[_versionHeader]; ok && owner != strconv.Itoa(h.repo.Version()) {
+		return h.repo.SaveCheckpoint(ctx, checkpoint)
+	}
 
 	if len(value) == 0 {
-		return fmt.Errorf("empty message value")
+		h.log.Error("empty message value", "key", string(key))
+		return h.skip(ctx, checkpoint, key, value, headers, "Empty message value")
 	}
 
-	// Base64 kodlu mesajı çözme
-	decodedValue, err := base64.StdEncoding.DecodeString(strings.Trim(string(value), "\""))
+	// Mesaj içeriğini çözme
+	decodedValue, err := events.DecodePayload(value)
 	if err != nil {
 		h.log.Error(err, "Base64 decode error")
-		return h.retryOrSendToDLQ(ctx, key, value, "Base64 decode error")
+		return h.skip(ctx, checkpoint, key, value, headers, "Base64 decode error")
 	}
 
 	// JSON mesajını çözme
 	var event entity.WalletEvent
 	if err := json.Unmarshal(decodedValue, &event); err != nil {
 		h.log.Error(err, "JSON unmarshal error")
-		return h.retryOrSendToDLQ(ctx, key, decodedValue, "JSON unmarshal error")
+		return h.skip(ctx, checkpoint, key, decodedValue, headers, "JSON unmarshal error")
+	}
+
+	if event.EventID == "" {
+		h.log.Error("event without event_id", "key", string(key))
+		return h.skip(ctx, checkpoint, key, decodedValue, headers, "Missing event id")
 	}
 
 	// Event'i işleme
-	if err := h.ProcessEvent(ctx, event); err != nil {
-		h.log.Error(err, "Failed to process event")
-		return h.retryOrSendToDLQ(ctx, key, decodedValue, "Event processing error")
+	applied, err := h.repo.WithinProjection(ctx, event, checkpoint, func(ctx context.Context) error {
+		if err := h.ProcessEvent(ctx, event); err != nil {
+			return err
+		}
+		if h.alerts == nil {
+			return nil
+		}
+		return h.alerts.Evaluate(ctx, event)
+	})
+	if errors.Is(err, ErrInvalidEvent) {
+		h.log.Error(err, "Invalid event")
+		return h.skip(ctx, checkpoint, key, decodedValue, headers, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("EventHandler - MsgfessageHandler - h.repo.WithinProjection: %w", err)
+	}
+
+	if !applied {
+		h.log.Info("Event already applied", "event_id", event.EventID)
 	}
 
 	return nil
 }
 
-func (h *eventHandler) retryOrSendToDLQ(ctx context.Context, key, value []byte, errorMsg string) error {
+// skip hands a message that cannot be projected over to the retry topic or the
+// DLQ and moves the checkpoint past it.
+func (h *eventHandler) skip(ctx context.Context, checkpoint entity.Checkpoint, key, value []byte, headers map[string]string, errorMsg string) error {
+	if err := h.retryOrSendToDLQ(ctx, key, value, headers, errorMsg); err != nil {
+		return err
+	}
+	return h.repo.SaveCheckpoint(ctx, checkpoint)
+}
+
+// retryOrSendToDLQ publishes a message to the retry topic with its attempt counter
+// incremented, or to the DLQ once it has been retried maxRetries times.
+func (h *eventHandler) retryOrSendToDLQ(ctx context.Context, key, value []byte, headers map[string]string, errorMsg string) error {
 	const maxRetries = 3
 
-	// Retry deneme sayısını belirle (örnek olarak 0 başlatıldı)
-	retryCount := h.getRetryCount(key)
+	retryCount := getRetryCount(headers)
 
 	if retryCount < maxRetries {
 		h.log.Info("Retrying message", "key", string(key), "retryCount", retryCount)
-		h.incrementRetryCount(key)
-		return h.retry.PublishRetryEvent(ctx, value)
+		return h.retry.PublishRetryEvent(ctx, key, value, map[string]string{
+			_retryCountHeader: strconv.Itoa(retryCount + 1),
+			_versionHeader:    strconv.Itoa(h.repo.Version()),
+		})
 	} else {
 		h.log.Info("Sending message to DLQ", "key", string(key))
-		return h.dlq.PublishDLQEvent(ctx, value)
+		return h.dlq.PublishDLQEvent(ctx, key, value, errorMsg)
 	}
 }
 
-func (h *eventHandler) getRetryCount(key []byte) int {
-	return 3
-}
-
-func (h *eventHandler) incrementRetryCount(key []byte) {
-	// Retry sayısını bir metadata olarak artır
+// getRetryCount returns how many times a message has been retried, zero for
+// messages of the event journal.
+func getRetryCount(headers map[string]string) int {
+	count, err := strconv.Atoi(headers[_retryCountHeader])
+	if err != nil {
+		return 0
+	}
+	return count
 }
 
 // ProcessEvent dynamically handles wallet events using the map
@@ -93,28 +154,96 @@ func (h *eventHandler) ProcessEvent(ctx context.Context, event entity.WalletEven
 	if handler, exists := EventHandlers[event.Type]; exists {
 		return handler(ctx, h.repo, event)
 	}
-	return fmt.Errorf("unknown event type: %s", event.Type)
+	return fmt.Errorf("%w: unknown event type: %s", ErrInvalidEvent, event.Type)
 }
 
 // Withdraw handler
 func handleWithdraw(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent) error {
-	return repo.UpdateBalance(ctx, event.WalletID, event.AssetName, -event.Amount)
+	return applyEntry(ctx, repo, event, event.WalletID, nil, -event.Amount)
 }
 
 // Deposit handler
 func handleDeposit(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent) error {
-	return repo.UpdateBalance(ctx, event.WalletID, event.AssetName, event.Amount)
+	return applyEntry(ctx, repo, event, event.WalletID, nil, event.Amount)
 }
 
-// Transfer handler
+// Transfer handler, debits the sender and credits the target wallet
 func handleTransfer(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent) error {
-	// Withdraw from sender wallet
-	if err := repo.UpdateBalance(ctx, event.WalletID, event.AssetName, -event.Amount); err != nil {
+	if event.TargetWalletID == 0 {
+		return fmt.Errorf("%w: transfer event %s without target_wallet_id", ErrInvalidEvent, event.EventID)
+	}
+
+	sender, target := event.WalletID, event.TargetWalletID
+	if err := applyEntry(ctx, repo, event, sender, &target, -event.Amount); err != nil {
 		return err
 	}
-	// Deposit to target wallet (Assume another field such as TargetWalletID exists for a transfer event)
-	// if err := repo.UpdateBalance(ctx, event.TargetWalletID, event.AssetName, event.Amount); err != nil {
-	//     return err
-	// }
+	if err := applyEntry(ctx, repo, event, target, &sender, event.Amount); err != nil {
+		return err
+	}
+
+	if event.CommandID == "" {
+		return nil
+	}
+	return repo.MarkTransferExecuted(ctx, event.CommandID, time.Unix(event.Timestamp, 0).UTC())
+}
+
+// Transfer scheduled handler, records a transfer asset-processor executes at its execute time.
+// Balances only move with the transfer event.
+func handleTransferScheduled(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent) error {
+	if event.CommandID == "" || event.TargetWalletID == 0 {
+		return fmt.Errorf("%w: transfer_scheduled event %s without command_id or target_wallet_id", ErrInvalidEvent, event.EventID)
+	}
+
+	return repo.InsertScheduledTransfer(ctx, entity.ScheduledTransfer{
+		CommandID:    event.CommandID,
+		FromWalletID: event.WalletID,
+		ToWalletID:   event.TargetWalletID,
+		AssetName:    event.AssetName,
+		Amount:       event.Amount,
+		ExecuteTime:  time.Unix(event.ExecuteTime, 0).UTC(),
+		ScheduledAt:  time.Unix(event.Timestamp, 0).UTC(),
+	})
+}
+
+// Alert handler. Alerts are fired by this projection and move no balances.
+func handleAlert(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent) error {
 	return nil
 }
+
+// Balance correction handler. A correction compensates a drift of one projection
+// version found by reconciliation, other versions only record it as applied.
+func handleBalanceCorrection(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent) error {
+	if event.ProjectionVersion != repo.Version() {
+		return nil
+	}
+	return applyEntry(ctx, repo, event, event.WalletID, nil, event.Amount)
+}
+
+// applyEntry updates the balance of a wallet asset, writes its history row
+// with the running balance and adds it to the balance series and daily stats.
+func applyEntry(ctx context.Context, repo AssetQueryRepositoryHandler, event entity.WalletEvent, walletID int, counterparty *int, amount float64) error {
+	balance, err := repo.UpdateBalance(ctx, walletID, event.AssetName, amount)
+	if err != nil {
+		return err
+	}
+
+	txn := entity.Transaction{
+		EventID:              event.EventID,
+		WalletID:             walletID,
+		CounterpartyWalletID: counterparty,
+		AssetName:            event.AssetName,
+		Type:                 event.Type,
+		Amount:               amount,
+		BalanceAfter:         balance,
+		EventTime:            time.Unix(event.Timestamp, 0).UTC(),
+	}
+	if err := repo.InsertTransaction(ctx, txn); err != nil {
+		return err
+	}
+
+	if err := repo.UpdateBalanceSeries(ctx, txn); err != nil {
+		return err
+	}
+
+	return repo.UpdateDailyStats(ctx, txn)
+}
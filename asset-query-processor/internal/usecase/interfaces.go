@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
 )
@@ -20,27 +21,76 @@ type (
 		// GetBalance retrieves the balance of a specific asset in a wallet
 		GetBalance(ctx context.Context, walletID int, assetName string) (float64, error)
 
-		// UpdateBalance updates the balance of a specific asset in a wallet
-		UpdateBalance(ctx context.Context, walletID int, assetName string, amount float64) error
+		// UpdateBalance adds amount to the balance of a specific asset in a wallet and returns the new balance
+		UpdateBalance(ctx context.Context, walletID int, assetName string, amount float64) (float64, error)
 
-		// InsertTransaction inserts a new transaction record into the database
+		// InsertTransaction inserts a new transaction history row into the database
 		InsertTransaction(ctx context.Context, txn entity.Transaction) error
 
+		// UpdateBalanceSeries adds a transaction to the hourly and daily balance buckets of its wallet asset
+		UpdateBalanceSeries(ctx context.Context, txn entity.Transaction) error
+
+		// UpdateDailyStats adds a transaction to the daily volumes, active wallets and holdings of its asset
+		UpdateDailyStats(ctx context.Context, txn entity.Transaction) error
+
+		// InsertScheduledTransfer stores a transfer deferred to its execute time
+		InsertScheduledTransfer(ctx context.Context, transfer entity.ScheduledTransfer) error
+
+		// MarkTransferExecuted marks the scheduled transfer of a command as executed
+		MarkTransferExecuted(ctx context.Context, commandID string, executedAt time.Time) error
+
 		// GetTransactionHistory retrieves the transaction history for a wallet
 		GetTransactionHistory(ctx context.Context, walletID int, assetName string) ([]entity.Transaction, error)
+
+		// WithinProjection applies an event exactly once, together with its checkpoint
+		WithinProjection(ctx context.Context, event entity.WalletEvent, checkpoint entity.Checkpoint, fn func(ctx context.Context) error) (bool, error)
+
+		// SaveCheckpoint advances the checkpoint past an event that is not projected
+		SaveCheckpoint(ctx context.Context, checkpoint entity.Checkpoint) error
+
+		// GetCheckpoint retrieves the next offset to project from a partition
+		GetCheckpoint(ctx context.Context, topic string, partition int32) (int64, bool, error)
+
+		// Version returns the projection version the repository writes
+		Version() int
+
+		// SnapshotBalances reads all balances together with the checkpoints on topic they were projected up to
+		SnapshotBalances(ctx context.Context, topic string) (entity.BalanceSnapshot, error)
+	}
+
+	// AlertRepositoryHandler defines the interface for the alert rules and their outbox.
+	AlertRepositoryHandler interface {
+		// GetAlertCandidates retrieves the enabled alert rules of the wallet assets an event moved, with the movements
+		GetAlertCandidates(ctx context.Context, eventID string) ([]entity.AlertCandidate, error)
+
+		// FireAlert starts the cooldown of a rule and queues its alert event
+		FireAlert(ctx context.Context, ruleID int64, firedAt time.Time, alert entity.WalletEvent) error
+
+		// PublishAlerts hands up to limit queued alert events to publish and marks them published
+		PublishAlerts(ctx context.Context, limit int, publish func(ctx context.Context, alert entity.WalletEvent) error) (int, error)
+	}
+
+	/* Alert Evaluator UseCase Interface */
+	AlertEvaluator interface {
+		Evaluate(ctx context.Context, event entity.WalletEvent) error // Fire the alert rules an event triggers, within its projection
 	}
 
 	/* Event Handler  UseCase Interface */
 	EventHandler interface {
 		ProcessEvent(ctx context.Context, event entity.WalletEvent) error
-		MsgfessageHandler(key, value []byte) error
+		MsgfessageHandler(ctx context.Context, checkpoint entity.Checkpoint, key, value []byte, headers map[string]string) error
 	}
 
 	RetryEventProducer interface {
-		PublishRetryEvent(ctx context.Context, event []byte) error
+		PublishRetryEvent(ctx context.Context, key, event []byte, headers map[string]string) error
 	}
 
 	DLQEventProducer interface {
-		PublishDLQEvent(ctx context.Context, event []byte) error
+		PublishDLQEvent(ctx context.Context, key, event []byte, reason string) error
+	}
+
+	// EventJournal publishes events to the event journal.
+	EventJournal interface {
+		PublishEvent(ctx context.Context, event entity.WalletEvent) error
 	}
 )
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/events"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// _balanceTolerance absorbs float rounding of differently ordered sums.
+const _balanceTolerance = 1e-9
+
+// ReconcileUseCase compares the read model with the balances implied by the event journal.
+type ReconcileUseCase struct {
+	repo    AssetQueryRepositoryHandler
+	journal EventJournal
+	log     logger.Interface
+}
+
+// NewReconcileUseCase creates a reconciliation of the version written by repo.
+func NewReconcileUseCase(r AssetQueryRepositoryHandler, journal EventJournal, l logger.Interface) *ReconcileUseCase {
+	return &ReconcileUseCase{repo: r, journal: journal, log: l}
+}
+
+// Start snapshots the read model and returns the replay to feed with the journal topic.
+// Retried messages are not replayed, like the projection skips them on the journal.
+func (uc *ReconcileUseCase) Start(ctx context.Context, topic string) (*Replay, error) {
+	snapshot, err := uc.repo.SnapshotBalances(ctx, topic)
+	if err != nil {
+		return nil, fmt.Errorf("ReconcileUseCase - Start - uc.repo.SnapshotBalances: %w", err)
+	}
+	return newReplay(snapshot, uc.log), nil
+}
+
+// Repair publishes one balance_correction event per mismatch. The corrections are
+// applied only by the reconciled projection version, and their ids are derived
+// from the snapshot, so repairing the same report twice corrects once.
+func (uc *ReconcileUseCase) Repair(ctx context.Context, report *entity.ReconciliationReport) error {
+	position := checkpointsDigest(report.Checkpoints)
+
+	for _, m := range report.Mismatches {
+		event := entity.WalletEvent{
+			EventID:           correctionID(report.ProjectionVersion, m, position),
+			WalletID:          m.WalletID,
+			AssetName:         m.AssetName,
+			Type:              "balance_correction",
+			Amount:            -m.Difference,
+			Timestamp:         time.Now().Unix(),
+			ProjectionVersion: report.ProjectionVersion,
+		}
+		if err := uc.journal.PublishEvent(ctx, event); err != nil {
+			return fmt.Errorf("ReconcileUseCase - Repair - uc.journal.PublishEvent: %w", err)
+		}
+		report.CorrectionsSent++
+	}
+
+	return nil
+}
+
+// Replay recomputes balances from journal events up to the checkpoints of a
+// read model snapshot. It is safe for concurrent use.
+type Replay struct {
+	mu        sync.Mutex
+	snapshot  entity.BalanceSnapshot
+	targets   map[string]int64 // Checkpoint per topic partition
+	remaining int              // Partitions not replayed up to their checkpoint yet
+	balances  map[entity.BalanceKey]float64
+	seen      map[string]struct{} // Event ids, duplicates are projected once
+	replayed  int
+	log       logger.Interface
+}
+
+func newReplay(snapshot entity.BalanceSnapshot, l logger.Interface) *Replay {
+	r := &Replay{
+		snapshot: snapshot,
+		targets:  make(map[string]int64),
+		balances: make(map[entity.BalanceKey]float64),
+		seen:     make(map[string]struct{}),
+		log:      l,
+	}
+	for _, c := range snapshot.Checkpoints {
+		if c.Offset > 0 {
+			r.targets[partitionID(c.Topic, c.Partition)] = c.Offset
+			r.remaining++
+		}
+	}
+	return r
+}
+
+// Done reports whether every partition has been replayed up to its checkpoint.
+func (r *Replay) Done() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.remaining == 0
+}
+
+// Apply replays the journal message that precedes position and reports whether
+// the replay is done. Messages past the checkpoint of their partition are ignored.
+func (r *Replay) Apply(position entity.Checkpoint, value []byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := partitionID(position.Topic, position.Partition)
+	target, ok := r.targets[id]
+	if !ok {
+		return r.remaining == 0
+	}
+	if position.Offset > target {
+		r.reached(id)
+		return r.remaining == 0
+	}
+
+	r.apply(value)
+	if position.Offset == target {
+		r.reached(id)
+	}
+	return r.remaining == 0
+}
+
+// reached marks a partition as replayed up to its checkpoint.
+func (r *Replay) reached(id string) {
+	delete(r.targets, id)
+	r.remaining--
+}
+
+// apply mirrors the projection. Messages the projection hands over to the retry
+// topic or the DLQ are skipped, corrections are not part of the journal balances.
+func (r *Replay) apply(value []byte) {
+	decoded, err := events.DecodePayload(value)
+	if err != nil {
+		return
+	}
+
+	var event entity.WalletEvent
+	if err := json.Unmarshal(decoded, &event); err != nil || event.EventID == "" {
+		return
+	}
+
+	if _, dup := r.seen[event.EventID]; dup {
+		return
+	}
+
+	switch event.Type {
+	case "withdraw":
+		r.add(event.WalletID, event.AssetName, -event.Amount)
+	case "deposit":
+		r.add(event.WalletID, event.AssetName, event.Amount)
+	case "transfer":
+		if event.TargetWalletID == 0 {
+			return
+		}
+		r.add(event.WalletID, event.AssetName, -event.Amount)
+		r.add(event.TargetWalletID, event.AssetName, event.Amount)
+	default:
+		return
+	}
+
+	r.seen[event.EventID] = struct{}{}
+	r.replayed++
+}
+
+func (r *Replay) add(walletID int, assetName string, amount float64) {
+	r.balances[entity.BalanceKey{WalletID: walletID, AssetName: assetName}] += amount
+}
+
+// Report diffs the replayed balances against the read model snapshot.
+func (r *Replay) Report() *entity.ReconciliationReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &entity.ReconciliationReport{
+		GeneratedAt:       time.Now().UTC(),
+		ProjectionVersion: r.snapshot.Version,
+		Checkpoints:       r.snapshot.Checkpoints,
+		EventsReplayed:    r.replayed,
+		Mismatches:        make([]entity.Mismatch, 0),
+	}
+
+	keys := make(map[entity.BalanceKey]struct{}, len(r.balances))
+	for key := range r.balances {
+		keys[key] = struct{}{}
+	}
+	for key := range r.snapshot.Balances {
+		keys[key] = struct{}{}
+	}
+	report.BalancesChecked = len(keys)
+
+	for key := range keys {
+		expected, actual := r.balances[key], r.snapshot.Balances[key]
+		if math.Abs(actual-expected) <= _balanceTolerance*math.Max(1, math.Abs(expected)) {
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, entity.Mismatch{
+			WalletID:   key.WalletID,
+			AssetName:  key.AssetName,
+			Expected:   expected,
+			Actual:     actual,
+			Difference: actual - expected,
+		})
+	}
+
+	sort.Slice(report.Mismatches, func(i, j int) bool {
+		a, b := report.Mismatches[i], report.Mismatches[j]
+		if a.WalletID != b.WalletID {
+			return a.WalletID < b.WalletID
+		}
+		return a.AssetName < b.AssetName
+	})
+
+	return report
+}
+
+func partitionID(topic string, partition int32) string {
+	return fmt.Sprintf("%s/%d", topic, partition)
+}
+
+// checkpointsDigest identifies the journal position of a snapshot.
+func checkpointsDigest(checkpoints []entity.Checkpoint) string {
+	h := sha256.New()
+	for _, c := range checkpoints {
+		fmt.Fprintf(h, "%s/%d/%d;", c.Topic, c.Partition, c.Offset)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// correctionID is the deterministic event id of the correction of a mismatch.
+func correctionID(version int, m entity.Mismatch, position string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("v%d/%d/%s/%s", version, m.WalletID, m.AssetName, position)))
+	return "correction-" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+)
+
+// GetAlertCandidates - Retrieves the enabled alert rules of the wallet assets an event moved,
+// each with the history row of its movement. The rules are locked until the projection
+// transaction of ctx ends, so their cooldowns are checked and updated by one event at a time.
+func (r *AssetQueryRepo) GetAlertCandidates(ctx context.Context, eventID string) ([]entity.AlertCandidate, error) {
+	rows, err := r.db(ctx).Query(ctx, fmt.Sprintf(`
+	SELECT a.id, a.wallet_id, a.asset_name, a.condition, a.threshold, a.cooldown_seconds, a.description,
+		a.last_fired_at, t.amount, t.balance_after
+	FROM %s t
+	JOIN alert_rules a ON a.wallet_id = t.wallet_id AND a.asset_name = t.asset_name
+	WHERE t.event_id = $1 AND a.enabled
+	ORDER BY a.id
+	FOR UPDATE OF a
+	`, r.table("wallet_transactions")), eventID)
+	if err != nil {
+		return nil, fmt.Errorf("AssetQueryRepo - GetAlertCandidates - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []entity.AlertCandidate
+	for rows.Next() {
+		var (
+			c        entity.AlertCandidate
+			cooldown int
+		)
+		err = rows.Scan(&c.Rule.ID, &c.Rule.WalletID, &c.Rule.AssetName, &c.Rule.Condition, &c.Rule.Threshold,
+			&cooldown, &c.Rule.Description, &c.Rule.LastFiredAt, &c.Amount, &c.BalanceAfter)
+		if err != nil {
+			return nil, fmt.Errorf("AssetQueryRepo - GetAlertCandidates - Scan: %w", err)
+		}
+		c.Rule.Cooldown = time.Duration(cooldown) * time.Second
+		candidates = append(candidates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("AssetQueryRepo - GetAlertCandidates - Rows: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// FireAlert - Starts the cooldown of a rule at firedAt and queues its alert event in the
+// outbox. An alert queued before is not queued again. Joins the projection transaction of
+// ctx, if any.
+func (r *AssetQueryRepo) FireAlert(ctx context.Context, ruleID int64, firedAt time.Time, alert entity.WalletEvent) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("AssetQueryRepo - FireAlert - json.Marshal: %w", err)
+	}
+
+	db := r.db(ctx)
+	if _, err := db.Exec(ctx, `
+	UPDATE alert_rules SET last_fired_at = $2 WHERE id = $1
+	`, ruleID, firedAt); err != nil {
+		return fmt.Errorf("AssetQueryRepo - FireAlert - alert_rules: %w", err)
+	}
+
+	if _, err := db.Exec(ctx, `
+	INSERT INTO alert_events (event_id, rule_id, payload) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING
+	`, alert.EventID, ruleID, payload); err != nil {
+		return fmt.Errorf("AssetQueryRepo - FireAlert - alert_events: %w", err)
+	}
+
+	return nil
+}
+
+// PublishAlerts - Hands up to limit unpublished alert events to publish, oldest first, and
+// marks the ones it accepted published. Rows are locked while they are published, so several
+// processors do not publish the same alert. Returns how many were published, with the error
+// that stopped publishing, if any.
+func (r *AssetQueryRepo) PublishAlerts(ctx context.Context, limit int, publish func(ctx context.Context, alert entity.WalletEvent) error) (int, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	rows, err := tx.Query(ctx, `
+	SELECT payload FROM alert_events
+	WHERE published_at IS NULL
+	ORDER BY created_at, event_id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - Query: %w", err)
+	}
+
+	var alerts []entity.WalletEvent
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - Scan: %w", err)
+		}
+		var alert entity.WalletEvent
+		if err := json.Unmarshal(payload, &alert); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - json.Unmarshal: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - Rows: %w", err)
+	}
+
+	published := make([]string, 0, len(alerts))
+	var publishErr error
+	for _, alert := range alerts {
+		if publishErr = publish(ctx, alert); publishErr != nil {
+			break
+		}
+		published = append(published, alert.EventID)
+	}
+
+	if len(published) > 0 {
+		if _, err := tx.Exec(ctx, `
+		UPDATE alert_events SET published_at = NOW() WHERE event_id = ANY($1)
+		`, published); err != nil {
+			return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - Exec: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("AssetQueryRepo - PublishAlerts - Commit: %w", err)
+	}
+
+	return len(published), publishErr
+}
@@ -3,19 +3,27 @@ package repo
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
 )
 
-// AssetQueryRepo provides methods for querying balances & transactions.
+// AssetQueryRepo provides methods for querying balances & transactions
+// in the tables of one projection version.
 type AssetQueryRepo struct {
 	*postgres.Postgres
+	version int
 }
 
-// NewAssetQueryRepo - Creates a new repository instance.
-func NewAssetQueryRepo(pg *postgres.Postgres) *AssetQueryRepo {
-	return &AssetQueryRepo{pg}
+// NewAssetQueryRepo - Creates a new repository instance for a projection version.
+func NewAssetQueryRepo(pg *postgres.Postgres, version int) *AssetQueryRepo {
+	return &AssetQueryRepo{Postgres: pg, version: version}
+}
+
+// table names a read model table of the repository's version.
+func (r *AssetQueryRepo) table(name string) string {
+	return versionedName(name, r.version)
 }
 
 // GetBalance - Retrieves the current balance of a wallet's asset.
@@ -24,7 +32,7 @@ func (r *AssetQueryRepo) GetBalance(ctx context.Context, walletID int, assetName
 
 	sql, _, err := r.Builder.
 		Select("amount").
-		From("wallet_assets").
+		From(r.table("wallet_assets")).
 		Where("wallet_id = ? AND asset_name = ?", walletID, assetName).
 		ToSql()
 	if err != nil {
@@ -39,55 +47,76 @@ func (r *AssetQueryRepo) GetBalance(ctx context.Context, walletID int, assetName
 	return balance, nil
 }
 
-// UpdateBalance - Updates the balance of a wallet's asset.
-func (r *AssetQueryRepo) UpdateBalance(ctx context.Context, walletID int, assetName string, amount float64) error {
-	sql := `
-	INSERT INTO wallet_assets (wallet_id, asset_name, amount)
+// UpdateBalance - Adds amount to the balance of a wallet's asset and returns the
+// new balance. Joins the projection transaction of ctx, if any.
+func (r *AssetQueryRepo) UpdateBalance(ctx context.Context, walletID int, assetName string, amount float64) (float64, error) {
+	sql := fmt.Sprintf(`
+	INSERT INTO %s AS a (wallet_id, asset_name, amount)
 	VALUES ($1, $2, $3)
 	ON CONFLICT (wallet_id, asset_name) DO UPDATE
-	SET amount = wallet_assets.amount + $3
-	`
+	SET amount = a.amount + $3
+	RETURNING amount
+	`, r.table("wallet_assets"))
 
-	_, err := r.Pool.Exec(ctx, sql, walletID, assetName, amount)
+	var balance float64
+	err := r.db(ctx).QueryRow(ctx, sql, walletID, assetName, amount).Scan(&balance)
 	if err != nil {
-		return fmt.Errorf("AssetQueryRepo - UpdateBalance - Exec: %w", err)
+		return 0, fmt.Errorf("AssetQueryRepo - UpdateBalance - QueryRow: %w", err)
 	}
 
-	return nil
+	return balance, nil
 }
 
-// InsertTransaction - Stores a new transaction in the database.
+// InsertTransaction - Stores a history row and notifies the listeners of the history
+// table with the wallet id, see GET /v1/wallets/{id}/stream in asset-query-service.
+// Joins the projection transaction of ctx, if any, the notification is then sent on commit.
+// The row takes the next stream_seq of its wallet, whose stream head stays locked until the
+// transaction commits, so the rows of a wallet are committed in stream_seq order. A
+// redelivered event skips a number.
 func (r *AssetQueryRepo) InsertTransaction(ctx context.Context, txn entity.Transaction) error {
-	/*sql, _, err := r.Builder.
-		Insert("wallet_transactions").
-		Columns("wallet_id", "asset_name", "type", "amount", "created_at").
-		Values(txn.WalletID, txn.AssetName, txn.Type, txn.Amount, txn.CreatedAt).
-		ToSql()
+	sql := fmt.Sprintf(`
+	WITH head AS (
+		INSERT INTO %s AS h (wallet_id, last_seq)
+		VALUES ($2, 1)
+		ON CONFLICT (wallet_id) DO UPDATE
+		SET last_seq = h.last_seq + 1
+		RETURNING last_seq
+	)
+	INSERT INTO %s (event_id, wallet_id, counterparty_wallet_id, asset_name, type, amount, balance_after, event_time, stream_seq)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT last_seq FROM head))
+	ON CONFLICT (event_id, wallet_id) DO NOTHING
+	`, r.table("wallet_stream_heads"), r.table("wallet_transactions"))
+
+	tag, err := r.db(ctx).Exec(ctx, sql, txn.EventID, txn.WalletID, txn.CounterpartyWalletID, txn.AssetName, txn.Type,
+		txn.Amount, txn.BalanceAfter, txn.EventTime)
 	if err != nil {
-		return fmt.Errorf("AssetQueryRepo - InsertTransaction - Builder: %w", err)
+		return fmt.Errorf("AssetQueryRepo - InsertTransaction - Exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
 	}
 
-	_, err = r.Pool.Exec(ctx, sql)
+	_, err = r.db(ctx).Exec(ctx, `SELECT pg_notify($1, $2)`, r.table("wallet_transactions"), strconv.Itoa(txn.WalletID))
 	if err != nil {
-		return fmt.Errorf("AssetQueryRepo - InsertTransaction - Exec: %w", err)
-	} */
+		return fmt.Errorf("AssetQueryRepo - InsertTransaction - pg_notify: %w", err)
+	}
 
 	return nil
 }
 
-// GetTransactionHistory - Retrieves all transactions for a wallet and asset.
+// GetTransactionHistory - Retrieves all transactions for a wallet and asset, newest first.
 func (r *AssetQueryRepo) GetTransactionHistory(ctx context.Context, walletID int, assetName string) ([]entity.Transaction, error) {
-	/*sql, _, err := r.Builder.
-		Select("transaction_id, wallet_id, asset_name, type, amount, created_at").
-		From("wallet_transactions").
+	sql, args, err := r.Builder.
+		Select("transaction_id, event_id, wallet_id, counterparty_wallet_id, asset_name, type, amount, balance_after, event_time, created_at").
+		From(r.table("wallet_transactions")).
 		Where("wallet_id = ? AND asset_name = ?", walletID, assetName).
-		OrderBy("created_at DESC").
+		OrderBy("event_time DESC", "transaction_id DESC").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("AssetQueryRepo - GetTransactionHistory - Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql, walletID, assetName)
+	rows, err := r.Pool.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("AssetQueryRepo - GetTransactionHistory - Query: %w", err)
 	}
@@ -96,12 +125,13 @@ func (r *AssetQueryRepo) GetTransactionHistory(ctx context.Context, walletID int
 	transactions := make([]entity.Transaction, 0)
 	for rows.Next() {
 		var txn entity.Transaction
-		err = rows.Scan(&txn.ID, &txn.WalletID, &txn.AssetName, &txn.Type, &txn.Amount, &txn.CreatedAt)
+		err = rows.Scan(&txn.ID, &txn.EventID, &txn.WalletID, &txn.CounterpartyWalletID, &txn.AssetName,
+			&txn.Type, &txn.Amount, &txn.BalanceAfter, &txn.EventTime, &txn.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("AssetQueryRepo - GetTransactionHistory - Scan: %w", err)
 		}
 		transactions = append(transactions, txn)
-	} */
+	}
 
-	return nil, nil
+	return transactions, rows.Err()
 }
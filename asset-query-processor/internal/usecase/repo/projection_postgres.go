@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+)
+
+// _projection names the read model in projection_checkpoints, suffixed with the version like its tables.
+const _projection = "wallet_assets"
+
+// txKey carries the projection transaction in a context.
+type txKey struct{}
+
+// querier is satisfied by the pool and by a transaction.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// db returns the projection transaction of ctx or the pool.
+func (r *AssetQueryRepo) db(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return r.Pool
+}
+
+// WithinProjection - Records the event and its command as applied, runs fn and stores
+// the checkpoint in one transaction. Repository calls made with the ctx passed to fn
+// join it. An already applied event only advances the checkpoint and reports false.
+func (r *AssetQueryRepo) WithinProjection(ctx context.Context, event entity.WalletEvent, checkpoint entity.Checkpoint, fn func(ctx context.Context) error) (bool, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("AssetQueryRepo - WithinProjection - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	var commandID *string
+	if event.CommandID != "" {
+		commandID = &event.CommandID
+	}
+
+	tag, err := tx.Exec(ctx, fmt.Sprintf(`
+	INSERT INTO %s (event_id, command_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`, r.table("applied_events")), event.EventID, commandID)
+	if err != nil {
+		return false, fmt.Errorf("AssetQueryRepo - WithinProjection - applied_events: %w", err)
+	}
+
+	applied := tag.RowsAffected() == 1
+	if applied {
+		if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+			return false, err
+		}
+	}
+
+	if err := saveCheckpoint(ctx, tx, r.projection(), checkpoint); err != nil {
+		return false, fmt.Errorf("AssetQueryRepo - WithinProjection - %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("AssetQueryRepo - WithinProjection - Commit: %w", err)
+	}
+
+	return applied, nil
+}
+
+// SaveCheckpoint - Advances the checkpoint of an event that is not projected,
+// e.g. one handed over to the retry topic or the DLQ.
+func (r *AssetQueryRepo) SaveCheckpoint(ctx context.Context, checkpoint entity.Checkpoint) error {
+	if err := saveCheckpoint(ctx, r.db(ctx), r.projection(), checkpoint); err != nil {
+		return fmt.Errorf("AssetQueryRepo - SaveCheckpoint - %w", err)
+	}
+	return nil
+}
+
+// GetCheckpoint - Retrieves the next offset to project from a partition.
+func (r *AssetQueryRepo) GetCheckpoint(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	var offset int64
+
+	err := r.Pool.QueryRow(ctx, `
+	SELECT next_offset FROM projection_checkpoints
+	WHERE projection = $1 AND topic = $2 AND partition = $3
+	`, r.projection(), topic, partition).Scan(&offset)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("AssetQueryRepo - GetCheckpoint - QueryRow: %w", err)
+	}
+
+	return offset, true, nil
+}
+
+// projection names the checkpoints of the repository's version.
+func (r *AssetQueryRepo) projection() string {
+	return versionedName(_projection, r.version)
+}
+
+// saveCheckpoint never moves a checkpoint backwards.
+func saveCheckpoint(ctx context.Context, db querier, projection string, checkpoint entity.Checkpoint) error {
+	_, err := db.Exec(ctx, `
+	INSERT INTO projection_checkpoints (projection, topic, partition, next_offset)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (projection, topic, partition) DO UPDATE
+	SET next_offset = GREATEST(projection_checkpoints.next_offset, EXCLUDED.next_offset), updated_at = NOW()
+	`, projection, checkpoint.Topic, checkpoint.Partition, checkpoint.Offset)
+	if err != nil {
+		return fmt.Errorf("projection_checkpoints: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+)
+
+// Version - Returns the projection version the repository writes.
+func (r *AssetQueryRepo) Version() int {
+	return r.version
+}
+
+// SnapshotBalances - Reads all balances and the checkpoints of the version on topic in
+// one repeatable read transaction, so the balances are exactly those projected up to
+// the checkpoints.
+func (r *AssetQueryRepo) SnapshotBalances(ctx context.Context, topic string) (entity.BalanceSnapshot, error) {
+	snapshot := entity.BalanceSnapshot{Version: r.version, Balances: make(map[entity.BalanceKey]float64)}
+
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return snapshot, fmt.Errorf("AssetQueryRepo - SnapshotBalances - BeginTx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // read only
+
+	rows, err := tx.Query(ctx, fmt.Sprintf(`SELECT wallet_id, asset_name, amount FROM %s`, r.table("wallet_assets")))
+	if err != nil {
+		return snapshot, fmt.Errorf("AssetQueryRepo - SnapshotBalances - Query balances: %w", err)
+	}
+	for rows.Next() {
+		var (
+			key    entity.BalanceKey
+			amount float64
+		)
+		if err := rows.Scan(&key.WalletID, &key.AssetName, &amount); err != nil {
+			rows.Close()
+			return snapshot, fmt.Errorf("AssetQueryRepo - SnapshotBalances - Scan balance: %w", err)
+		}
+		snapshot.Balances[key] = amount
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return snapshot, fmt.Errorf("AssetQueryRepo - SnapshotBalances - Rows balances: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, `
+	SELECT topic, partition, next_offset FROM projection_checkpoints
+	WHERE projection = $1 AND topic = $2 ORDER BY partition
+	`, r.projection(), topic)
+	if err != nil {
+		return snapshot, fmt.Errorf("AssetQueryRepo - SnapshotBalances - Query checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var checkpoint entity.Checkpoint
+		if err := rows.Scan(&checkpoint.Topic, &checkpoint.Partition, &checkpoint.Offset); err != nil {
+			return snapshot, fmt.Errorf("AssetQueryRepo - SnapshotBalances - Scan checkpoint: %w", err)
+		}
+		snapshot.Checkpoints = append(snapshot.Checkpoints, checkpoint)
+	}
+
+	return snapshot, rows.Err()
+}
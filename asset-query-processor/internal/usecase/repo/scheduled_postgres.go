@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+)
+
+// InsertScheduledTransfer - Stores a transfer announced by a transfer_scheduled event. A
+// transfer is announced once per command, a repeated announcement is ignored. Joins the
+// projection transaction of ctx, if any.
+func (r *AssetQueryRepo) InsertScheduledTransfer(ctx context.Context, transfer entity.ScheduledTransfer) error {
+	sql, args, err := r.Builder.
+		Insert(r.table("scheduled_transfers")).
+		Columns("command_id", "from_wallet_id", "to_wallet_id", "asset_name", "amount", "execute_time", "status", "scheduled_at").
+		Values(transfer.CommandID, transfer.FromWalletID, transfer.ToWalletID, transfer.AssetName, transfer.Amount, transfer.ExecuteTime, "scheduled", transfer.ScheduledAt).
+		Suffix("ON CONFLICT (command_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AssetQueryRepo - InsertScheduledTransfer - Builder: %w", err)
+	}
+
+	if _, err := r.db(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("AssetQueryRepo - InsertScheduledTransfer - Exec: %w", err)
+	}
+
+	return nil
+}
+
+// MarkTransferExecuted - Marks the scheduled transfer of a command as executed. Transfers
+// that were executed right away have no row. Joins the projection transaction of ctx, if any.
+func (r *AssetQueryRepo) MarkTransferExecuted(ctx context.Context, commandID string, executedAt time.Time) error {
+	sql, args, err := r.Builder.
+		Update(r.table("scheduled_transfers")).
+		Set("status", "executed").
+		Set("executed_at", executedAt).
+		Where("command_id = ? AND status = ?", commandID, "scheduled").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AssetQueryRepo - MarkTransferExecuted - Builder: %w", err)
+	}
+
+	if _, err := r.db(ctx).Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("AssetQueryRepo - MarkTransferExecuted - Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+)
+
+// _seriesGranularities are the bucket sizes of wallet_balance_series.
+var _seriesGranularities = []string{"hour", "day"}
+
+// UpdateBalanceSeries - Adds a history row to its hour and day buckets. The closing
+// balance of a bucket is the sum of the amounts up to its end, so the row adds its
+// amount to its own bucket and every later one: a late event lands where its event
+// time puts it, whatever order the events are projected in. Joins the projection
+// transaction of ctx, if any.
+func (r *AssetQueryRepo) UpdateBalanceSeries(ctx context.Context, txn entity.Transaction) error {
+	table := r.table("wallet_balance_series")
+
+	upsert := fmt.Sprintf(`
+	INSERT INTO %[1]s AS s
+		(wallet_id, asset_name, granularity, bucket_start, inflow, outflow, net, closing_balance, last_event_time)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE((
+		SELECT closing_balance FROM %[1]s
+		WHERE wallet_id = $1 AND asset_name = $2 AND granularity = $3 AND bucket_start < $4
+		ORDER BY bucket_start DESC
+		LIMIT 1
+	), 0) + $7, $8)
+	ON CONFLICT (wallet_id, asset_name, granularity, bucket_start) DO UPDATE
+	SET inflow = s.inflow + EXCLUDED.inflow,
+		outflow = s.outflow + EXCLUDED.outflow,
+		net = s.net + EXCLUDED.net,
+		closing_balance = s.closing_balance + EXCLUDED.net,
+		last_event_time = GREATEST(s.last_event_time, EXCLUDED.last_event_time)
+	`, table)
+
+	carry := fmt.Sprintf(`
+	UPDATE %s SET closing_balance = closing_balance + $5
+	WHERE wallet_id = $1 AND asset_name = $2 AND granularity = $3 AND bucket_start > $4
+	`, table)
+
+	var inflow, outflow float64
+	if txn.Amount >= 0 {
+		inflow = txn.Amount
+	} else {
+		outflow = -txn.Amount
+	}
+
+	for _, granularity := range _seriesGranularities {
+		bucket := bucketStart(txn.EventTime, granularity)
+
+		_, err := r.db(ctx).Exec(ctx, upsert, txn.WalletID, txn.AssetName, granularity, bucket,
+			inflow, outflow, txn.Amount, txn.EventTime)
+		if err != nil {
+			return fmt.Errorf("AssetQueryRepo - UpdateBalanceSeries - Exec %s: %w", granularity, err)
+		}
+
+		// Usually no later bucket exists yet
+		_, err = r.db(ctx).Exec(ctx, carry, txn.WalletID, txn.AssetName, granularity, bucket, txn.Amount)
+		if err != nil {
+			return fmt.Errorf("AssetQueryRepo - UpdateBalanceSeries - Exec carry %s: %w", granularity, err)
+		}
+	}
+
+	return nil
+}
+
+// bucketStart truncates t to the start of its UTC hour or day.
+func bucketStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	if granularity == "day" {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+)
+
+// UpdateDailyStats - Adds a history row to the daily aggregates of its asset. The
+// volume of a transfer is counted on its debit row, a correction only moves the
+// holdings. The total holdings of later days move with a late event. Joins the
+// projection transaction of ctx, if any.
+func (r *AssetQueryRepo) UpdateDailyStats(ctx context.Context, txn entity.Transaction) error {
+	day := bucketStart(txn.EventTime, "day")
+
+	var (
+		depositVolume, withdrawVolume, transferVolume float64
+		depositCount, withdrawCount, transferCount    int
+		activeWallets                                 int
+	)
+
+	switch {
+	case txn.Type == "deposit":
+		depositVolume, depositCount = txn.Amount, 1
+	case txn.Type == "withdraw":
+		withdrawVolume, withdrawCount = -txn.Amount, 1
+	case txn.Type == "transfer" && txn.Amount < 0:
+		transferVolume, transferCount = -txn.Amount, 1
+	}
+
+	if txn.Type != "balance_correction" {
+		tag, err := r.db(ctx).Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s (day, asset_name, wallet_id) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		`, r.table("asset_daily_active_wallets")), day, txn.AssetName, txn.WalletID)
+		if err != nil {
+			return fmt.Errorf("AssetQueryRepo - UpdateDailyStats - Exec active wallet: %w", err)
+		}
+		activeWallets = int(tag.RowsAffected())
+	}
+
+	stats := r.table("asset_daily_stats")
+	_, err := r.db(ctx).Exec(ctx, fmt.Sprintf(`
+	INSERT INTO %[1]s AS s (day, asset_name, deposit_volume, deposit_count, withdraw_volume, withdraw_count,
+		transfer_volume, transfer_count, active_wallets, net_flow, total_holdings)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10 + COALESCE((
+		SELECT total_holdings FROM %[1]s WHERE asset_name = $2 AND day < $1 ORDER BY day DESC LIMIT 1
+	), 0))
+	ON CONFLICT (day, asset_name) DO UPDATE
+	SET deposit_volume = s.deposit_volume + EXCLUDED.deposit_volume,
+		deposit_count = s.deposit_count + EXCLUDED.deposit_count,
+		withdraw_volume = s.withdraw_volume + EXCLUDED.withdraw_volume,
+		withdraw_count = s.withdraw_count + EXCLUDED.withdraw_count,
+		transfer_volume = s.transfer_volume + EXCLUDED.transfer_volume,
+		transfer_count = s.transfer_count + EXCLUDED.transfer_count,
+		active_wallets = s.active_wallets + EXCLUDED.active_wallets,
+		net_flow = s.net_flow + EXCLUDED.net_flow,
+		total_holdings = s.total_holdings + EXCLUDED.net_flow
+	`, stats), day, txn.AssetName, depositVolume, depositCount, withdrawVolume, withdrawCount,
+		transferVolume, transferCount, activeWallets, txn.Amount)
+	if err != nil {
+		return fmt.Errorf("AssetQueryRepo - UpdateDailyStats - Exec stats: %w", err)
+	}
+
+	if txn.Amount == 0 {
+		return nil
+	}
+
+	_, err = r.db(ctx).Exec(ctx, fmt.Sprintf(`
+	UPDATE %s SET total_holdings = total_holdings + $3 WHERE asset_name = $2 AND day > $1
+	`, stats), day, txn.AssetName, txn.Amount)
+	if err != nil {
+		return fmt.Errorf("AssetQueryRepo - UpdateDailyStats - Exec later days: %w", err)
+	}
+
+	return nil
+}
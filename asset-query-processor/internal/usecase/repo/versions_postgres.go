@@ -0,0 +1,197 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-processor/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// _versionedTables are the read model tables every projection version has a copy of.
+var _versionedTables = []string{
+	"wallet_assets", "wallet_transactions", "wallet_stream_heads", "wallet_balance_series", "asset_daily_stats", "asset_daily_active_wallets", "scheduled_transfers", "applied_events",
+}
+
+// ErrVersionNotFound is returned for a projection version that does not exist.
+var ErrVersionNotFound = errors.New("projection version not found")
+
+// versionedName names a table or projection of a version. Version 1 owns the
+// unsuffixed names, so the tables that predate versioning are its tables.
+func versionedName(name string, version int) string {
+	if version <= 1 {
+		return name
+	}
+	return fmt.Sprintf("%s_v%d", name, version)
+}
+
+// ProjectionVersionRepo manages the versions of the read model.
+type ProjectionVersionRepo struct {
+	*postgres.Postgres
+}
+
+// NewProjectionVersionRepo - Creates a new repository instance.
+func NewProjectionVersionRepo(pg *postgres.Postgres) *ProjectionVersionRepo {
+	return &ProjectionVersionRepo{pg}
+}
+
+// ActiveVersion - Retrieves the version read by asset-query-service.
+func (r *ProjectionVersionRepo) ActiveVersion(ctx context.Context) (int, error) {
+	var version int
+
+	err := r.Pool.QueryRow(ctx, `SELECT version FROM projection_versions WHERE status = $1`, entity.VersionActive).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrVersionNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ProjectionVersionRepo - ActiveVersion - QueryRow: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetVersion - Retrieves a projection version.
+func (r *ProjectionVersionRepo) GetVersion(ctx context.Context, version int) (entity.ProjectionVersion, error) {
+	v := entity.ProjectionVersion{Version: version}
+
+	err := r.Pool.QueryRow(ctx, `
+	SELECT status, created_at, activated_at FROM projection_versions WHERE version = $1
+	`, version).Scan(&v.Status, &v.CreatedAt, &v.ActivatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return v, ErrVersionNotFound
+	}
+	if err != nil {
+		return v, fmt.Errorf("ProjectionVersionRepo - GetVersion - QueryRow: %w", err)
+	}
+
+	return v, nil
+}
+
+// ListVersions - Retrieves all projection versions, oldest first.
+func (r *ProjectionVersionRepo) ListVersions(ctx context.Context) ([]entity.ProjectionVersion, error) {
+	rows, err := r.Pool.Query(ctx, `SELECT version, status, created_at, activated_at FROM projection_versions ORDER BY version`)
+	if err != nil {
+		return nil, fmt.Errorf("ProjectionVersionRepo - ListVersions - Query: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]entity.ProjectionVersion, 0)
+	for rows.Next() {
+		var v entity.ProjectionVersion
+		if err := rows.Scan(&v.Version, &v.Status, &v.CreatedAt, &v.ActivatedAt); err != nil {
+			return nil, fmt.Errorf("ProjectionVersionRepo - ListVersions - Scan: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// CreateVersion - Creates empty shadow tables for the next version and registers
+// it as building. The tables are copied from the version 1 tables, which the
+// migrations keep up to date, including their indexes and constraints.
+func (r *ProjectionVersionRepo) CreateVersion(ctx context.Context) (int, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ProjectionVersionRepo - CreateVersion - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	// Concurrent rebuilds get distinct versions
+	if _, err := tx.Exec(ctx, `LOCK TABLE projection_versions IN EXCLUSIVE MODE`); err != nil {
+		return 0, fmt.Errorf("ProjectionVersionRepo - CreateVersion - Lock: %w", err)
+	}
+
+	var version int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 1) + 1 FROM projection_versions`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("ProjectionVersionRepo - CreateVersion - QueryRow: %w", err)
+	}
+
+	for _, table := range _versionedTables {
+		sql := fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING ALL)`, versionedName(table, version), table)
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return 0, fmt.Errorf("ProjectionVersionRepo - CreateVersion - %s: %w", table, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO projection_versions (version, status) VALUES ($1, $2)`, version, entity.VersionBuilding)
+	if err != nil {
+		return 0, fmt.Errorf("ProjectionVersionRepo - CreateVersion - Insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("ProjectionVersionRepo - CreateVersion - Commit: %w", err)
+	}
+
+	return version, nil
+}
+
+// ActivateVersion - Makes version the one read by asset-query-service and retires
+// the active one, in one transaction.
+func (r *ProjectionVersionRepo) ActivateVersion(ctx context.Context, version int) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ProjectionVersionRepo - ActivateVersion - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // no-op after commit
+
+	// Serializes with other switches and with CreateVersion
+	if _, err := tx.Exec(ctx, `LOCK TABLE projection_versions IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("ProjectionVersionRepo - ActivateVersion - Lock: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+	UPDATE projection_versions SET status = $1 WHERE status = $2 AND version <> $3
+	`, entity.VersionRetired, entity.VersionActive, version)
+	if err != nil {
+		return fmt.Errorf("ProjectionVersionRepo - ActivateVersion - Retire: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+	UPDATE projection_versions SET status = $1, activated_at = NOW() WHERE version = $2
+	`, entity.VersionActive, version)
+	if err != nil {
+		return fmt.Errorf("ProjectionVersionRepo - ActivateVersion - Activate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrVersionNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ProjectionVersionRepo - ActivateVersion - Commit: %w", err)
+	}
+
+	return nil
+}
+
+// Checkpoints - Retrieves the next offset a version projects from every partition of topic
+// it has a checkpoint for.
+func (r *ProjectionVersionRepo) Checkpoints(ctx context.Context, version int, topic string) (map[int32]int64, error) {
+	rows, err := r.Pool.Query(ctx, `
+	SELECT partition, next_offset FROM projection_checkpoints WHERE projection = $1 AND topic = $2
+	`, versionedName(_projection, version), topic)
+	if err != nil {
+		return nil, fmt.Errorf("ProjectionVersionRepo - Checkpoints - Query: %w", err)
+	}
+	defer rows.Close()
+
+	checkpoints := make(map[int32]int64)
+	for rows.Next() {
+		var (
+			partition int32
+			offset    int64
+		)
+		if err := rows.Scan(&partition, &offset); err != nil {
+			return nil, fmt.Errorf("ProjectionVersionRepo - Checkpoints - Scan: %w", err)
+		}
+		checkpoints[partition] = offset
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ProjectionVersionRepo - Checkpoints - Rows: %w", err)
+	}
+
+	return checkpoints, nil
+}
@@ -0,0 +1,15 @@
+-- Events already projected into the read model, a redelivered event is a no-op
+CREATE TABLE IF NOT EXISTS applied_events (
+    event_id   VARCHAR(64) PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Next offset to project per source partition, written in the same transaction as the read model
+CREATE TABLE IF NOT EXISTS projection_checkpoints (
+    projection  VARCHAR(100) NOT NULL,
+    topic       VARCHAR(255) NOT NULL,
+    partition   INT          NOT NULL,
+    next_offset BIGINT       NOT NULL,
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (projection, topic, partition)
+);
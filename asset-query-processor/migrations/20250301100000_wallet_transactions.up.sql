@@ -0,0 +1,20 @@
+-- One history row per wallet touched by an event, a transfer writes a row for both wallets
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    transaction_id         BIGSERIAL PRIMARY KEY,
+    event_id               VARCHAR(64) NOT NULL,
+    wallet_id              INT         NOT NULL,
+    counterparty_wallet_id INT,
+    asset_name             VARCHAR(50) NOT NULL,
+    type                   VARCHAR(20) NOT NULL,
+    amount                 FLOAT       NOT NULL, -- Signed, negative for debits
+    balance_after          FLOAT       NOT NULL, -- Running balance of the wallet asset
+    event_time             TIMESTAMPTZ NOT NULL,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (event_id, wallet_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_wallet_time
+    ON wallet_transactions (wallet_id, event_time DESC, transaction_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_wallet_asset_time
+    ON wallet_transactions (wallet_id, asset_name, event_time DESC, transaction_id DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_wallet_transactions_wallet_counterparty_time;
+DROP INDEX IF EXISTS idx_wallet_transactions_wallet_type_time;
@@ -0,0 +1,7 @@
+-- Keyset pagination of the filtered transaction history in asset-query-service
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_wallet_type_time
+    ON wallet_transactions (wallet_id, type, event_time DESC, transaction_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_wallet_counterparty_time
+    ON wallet_transactions (wallet_id, counterparty_wallet_id, event_time DESC, transaction_id DESC)
+    WHERE counterparty_wallet_id IS NOT NULL;
@@ -0,0 +1,27 @@
+-- End-of-period balances per wallet asset for charts, one row per hour and per day bucket (UTC)
+CREATE TABLE IF NOT EXISTS wallet_balance_series (
+    wallet_id       INT         NOT NULL,
+    asset_name      VARCHAR(50) NOT NULL,
+    granularity     VARCHAR(8)  NOT NULL, -- "hour" or "day"
+    bucket_start    TIMESTAMPTZ NOT NULL,
+    inflow          FLOAT       NOT NULL DEFAULT 0,
+    outflow         FLOAT       NOT NULL DEFAULT 0, -- Positive sum of the debits
+    net             FLOAT       NOT NULL DEFAULT 0,
+    closing_balance FLOAT       NOT NULL, -- Balance after the last event of the bucket
+    last_event_time TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (wallet_id, asset_name, granularity, bucket_start)
+);
+
+-- Backfill from the transaction history
+INSERT INTO wallet_balance_series (wallet_id, asset_name, granularity, bucket_start, inflow, outflow, net, closing_balance, last_event_time)
+SELECT t.wallet_id, t.asset_name, b.granularity, b.bucket_start,
+       SUM(GREATEST(t.amount, 0)), SUM(GREATEST(-t.amount, 0)), SUM(t.amount),
+       (ARRAY_AGG(t.balance_after ORDER BY t.event_time DESC, t.transaction_id DESC))[1],
+       MAX(t.event_time)
+FROM wallet_transactions t
+CROSS JOIN LATERAL (VALUES
+    ('hour', date_trunc('hour', t.event_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'),
+    ('day', date_trunc('day', t.event_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC')
+) AS b (granularity, bucket_start)
+GROUP BY t.wallet_id, t.asset_name, b.granularity, b.bucket_start
+ON CONFLICT DO NOTHING;
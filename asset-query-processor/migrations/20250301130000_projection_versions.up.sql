@@ -0,0 +1,16 @@
+-- Versions of the read model. Version 1 owns the unsuffixed tables, version N the tables
+-- suffixed with _vN. asset-query-service reads the active version.
+CREATE TABLE IF NOT EXISTS projection_versions (
+    version      INT         PRIMARY KEY,
+    status       VARCHAR(16) NOT NULL, -- "building", "active" or "retired"
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    activated_at TIMESTAMPTZ
+);
+
+-- At most one active version
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projection_versions_active
+    ON projection_versions (status) WHERE status = 'active';
+
+INSERT INTO projection_versions (version, status, activated_at)
+VALUES (1, 'active', NOW())
+ON CONFLICT DO NOTHING;
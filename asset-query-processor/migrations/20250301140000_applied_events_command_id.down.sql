@@ -0,0 +1,11 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOR t IN
+        SELECT CASE WHEN version = 1 THEN 'applied_events' ELSE 'applied_events_v' || version END
+        FROM projection_versions
+    LOOP
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS command_id', t);
+    END LOOP;
+END $$;
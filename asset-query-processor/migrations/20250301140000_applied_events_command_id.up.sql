@@ -0,0 +1,15 @@
+-- Command an applied event was emitted for, asset-query-service waits for it to serve
+-- read-your-writes queries. Shadow tables of later projection versions get the column too.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOR t IN
+        SELECT CASE WHEN version = 1 THEN 'applied_events' ELSE 'applied_events_v' || version END
+        FROM projection_versions
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS command_id VARCHAR(64)', t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (command_id) WHERE command_id IS NOT NULL',
+            'idx_' || t || '_command_id', t);
+    END LOOP;
+END $$;
@@ -0,0 +1,11 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOR t IN
+        SELECT CASE WHEN version = 1 THEN 'wallet_assets' ELSE 'wallet_assets_v' || version END
+        FROM projection_versions
+    LOOP
+        EXECUTE format('DROP INDEX IF EXISTS %I', 'idx_' || t || '_holders');
+    END LOOP;
+END $$;
@@ -0,0 +1,14 @@
+-- Holders of an asset, largest balance first, for GET /v1/assets/{asset}/holders in
+-- asset-query-service. Shadow tables of later projection versions get the index too.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOR t IN
+        SELECT CASE WHEN version = 1 THEN 'wallet_assets' ELSE 'wallet_assets_v' || version END
+        FROM projection_versions
+    LOOP
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (asset_name, amount DESC, wallet_id) WHERE amount > 0',
+            'idx_' || t || '_holders', t);
+    END LOOP;
+END $$;
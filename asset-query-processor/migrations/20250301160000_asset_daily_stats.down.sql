@@ -0,0 +1,13 @@
+DO $$
+DECLARE
+    v INT;
+BEGIN
+    FOR v IN SELECT version FROM projection_versions WHERE version > 1
+    LOOP
+        EXECUTE format('DROP TABLE IF EXISTS %I', 'asset_daily_stats_v' || v);
+        EXECUTE format('DROP TABLE IF EXISTS %I', 'asset_daily_active_wallets_v' || v);
+    END LOOP;
+END $$;
+
+DROP TABLE IF EXISTS asset_daily_active_wallets;
+DROP TABLE IF EXISTS asset_daily_stats;
@@ -0,0 +1,73 @@
+-- Platform-wide aggregates per UTC day and asset for the /v1/stats endpoints of asset-query-service
+CREATE TABLE IF NOT EXISTS asset_daily_stats (
+    day             DATE        NOT NULL,
+    asset_name      VARCHAR(50) NOT NULL,
+    deposit_volume  FLOAT       NOT NULL DEFAULT 0,
+    deposit_count   INT         NOT NULL DEFAULT 0,
+    withdraw_volume FLOAT       NOT NULL DEFAULT 0, -- Positive
+    withdraw_count  INT         NOT NULL DEFAULT 0,
+    transfer_volume FLOAT       NOT NULL DEFAULT 0, -- Counted once per transfer
+    transfer_count  INT         NOT NULL DEFAULT 0,
+    active_wallets  INT         NOT NULL DEFAULT 0, -- Wallets with a deposit, withdraw or transfer
+    net_flow        FLOAT       NOT NULL DEFAULT 0, -- Change of the total holdings during the day
+    total_holdings  FLOAT       NOT NULL DEFAULT 0, -- Sum of all balances at the end of the day
+    PRIMARY KEY (day, asset_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_daily_stats_asset_day ON asset_daily_stats (asset_name, day DESC);
+
+-- Wallets counted in active_wallets, so a wallet is counted once per day and asset
+CREATE TABLE IF NOT EXISTS asset_daily_active_wallets (
+    day        DATE        NOT NULL,
+    asset_name VARCHAR(50) NOT NULL,
+    wallet_id  INT         NOT NULL,
+    PRIMARY KEY (day, asset_name, wallet_id)
+);
+
+-- Shadow tables of later projection versions, then a backfill of every version from its history
+DO $$
+DECLARE
+    v INT;
+    suffix TEXT;
+BEGIN
+    FOR v IN SELECT version FROM projection_versions ORDER BY version
+    LOOP
+        suffix := CASE WHEN v = 1 THEN '' ELSE '_v' || v END;
+
+        IF v > 1 THEN
+            EXECUTE format('CREATE TABLE IF NOT EXISTS %I (LIKE asset_daily_stats INCLUDING ALL)', 'asset_daily_stats' || suffix);
+            EXECUTE format('CREATE TABLE IF NOT EXISTS %I (LIKE asset_daily_active_wallets INCLUDING ALL)', 'asset_daily_active_wallets' || suffix);
+        END IF;
+
+        EXECUTE format($sql$
+            INSERT INTO %I (day, asset_name, wallet_id)
+            SELECT DISTINCT (event_time AT TIME ZONE 'UTC')::date, asset_name, wallet_id
+            FROM %I
+            WHERE type <> 'balance_correction'
+            ON CONFLICT DO NOTHING
+        $sql$, 'asset_daily_active_wallets' || suffix, 'wallet_transactions' || suffix);
+
+        EXECUTE format($sql$
+            INSERT INTO %I (day, asset_name, deposit_volume, deposit_count, withdraw_volume, withdraw_count,
+                transfer_volume, transfer_count, active_wallets, net_flow, total_holdings)
+            SELECT d.day, d.asset_name, d.deposit_volume, d.deposit_count, d.withdraw_volume, d.withdraw_count,
+                d.transfer_volume, d.transfer_count,
+                (SELECT COUNT(*) FROM %I a WHERE a.day = d.day AND a.asset_name = d.asset_name),
+                d.net_flow,
+                SUM(d.net_flow) OVER (PARTITION BY d.asset_name ORDER BY d.day)
+            FROM (
+                SELECT (event_time AT TIME ZONE 'UTC')::date AS day, asset_name,
+                    COALESCE(SUM(amount) FILTER (WHERE type = 'deposit'), 0) AS deposit_volume,
+                    COUNT(*) FILTER (WHERE type = 'deposit') AS deposit_count,
+                    COALESCE(SUM(-amount) FILTER (WHERE type = 'withdraw'), 0) AS withdraw_volume,
+                    COUNT(*) FILTER (WHERE type = 'withdraw') AS withdraw_count,
+                    COALESCE(SUM(-amount) FILTER (WHERE type = 'transfer' AND amount < 0), 0) AS transfer_volume,
+                    COUNT(*) FILTER (WHERE type = 'transfer' AND amount < 0) AS transfer_count,
+                    SUM(amount) AS net_flow
+                FROM %I
+                GROUP BY 1, 2
+            ) d
+            ON CONFLICT DO NOTHING
+        $sql$, 'asset_daily_stats' || suffix, 'asset_daily_active_wallets' || suffix, 'wallet_transactions' || suffix);
+    END LOOP;
+END $$;
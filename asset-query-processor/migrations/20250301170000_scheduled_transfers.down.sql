@@ -0,0 +1,11 @@
+DO $$
+DECLARE
+    v INT;
+BEGIN
+    FOR v IN SELECT version FROM projection_versions WHERE version > 1
+    LOOP
+        EXECUTE format('DROP TABLE IF EXISTS %I', 'scheduled_transfers_v' || v);
+    END LOOP;
+END $$;
+
+DROP TABLE IF EXISTS scheduled_transfers;
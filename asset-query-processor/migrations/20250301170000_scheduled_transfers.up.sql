@@ -0,0 +1,29 @@
+-- Transfers asset-processor deferred to their execute time, announced by transfer_scheduled events
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    command_id     VARCHAR(64) PRIMARY KEY,
+    from_wallet_id INT         NOT NULL,
+    to_wallet_id   INT         NOT NULL,
+    asset_name     VARCHAR(50) NOT NULL,
+    amount         FLOAT       NOT NULL,
+    execute_time   TIMESTAMPTZ NOT NULL,
+    status         VARCHAR(20) NOT NULL DEFAULT 'scheduled', -- "scheduled" or "executed"
+    scheduled_at   TIMESTAMPTZ NOT NULL,
+    executed_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_from
+    ON scheduled_transfers (from_wallet_id, execute_time, command_id);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_to
+    ON scheduled_transfers (to_wallet_id, execute_time, command_id);
+
+-- Shadow tables of later projection versions
+DO $$
+DECLARE
+    v INT;
+BEGIN
+    FOR v IN SELECT version FROM projection_versions WHERE version > 1
+    LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I (LIKE scheduled_transfers INCLUDING ALL)', 'scheduled_transfers_v' || v);
+    END LOOP;
+END $$;
@@ -0,0 +1,29 @@
+-- Alert rules of wallet assets, evaluated by the active projection version after every balance change
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id               BIGSERIAL PRIMARY KEY,
+    wallet_id        INT          NOT NULL,
+    asset_name       VARCHAR(50)  NOT NULL,
+    condition        VARCHAR(20)  NOT NULL, -- "balance_below" or "movement_above"
+    threshold        FLOAT        NOT NULL,
+    cooldown_seconds INT          NOT NULL DEFAULT 0, -- Least time between two alerts of the rule
+    enabled          BOOLEAN      NOT NULL DEFAULT TRUE,
+    description      VARCHAR(255) NOT NULL DEFAULT '',
+    last_fired_at    TIMESTAMPTZ, -- Event time of the last alert
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_wallet_asset
+    ON alert_rules (wallet_id, asset_name);
+
+-- Outbox of the alert events, appended to the event journal once their projection has committed
+CREATE TABLE IF NOT EXISTS alert_events (
+    event_id     VARCHAR(64) PRIMARY KEY,
+    rule_id      BIGINT      NOT NULL,
+    payload      JSONB       NOT NULL, -- The alert event as published
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_pending
+    ON alert_events (created_at, event_id) WHERE published_at IS NULL;
@@ -0,0 +1,14 @@
+DO $$
+DECLARE
+    v INT;
+    suffix TEXT;
+BEGIN
+    FOR v IN SELECT version FROM projection_versions
+    LOOP
+        suffix := CASE WHEN v = 1 THEN '' ELSE '_v' || v END;
+
+        EXECUTE format('DROP INDEX IF EXISTS %I', 'idx_wallet_transactions' || suffix || '_stream');
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS stream_seq', 'wallet_transactions' || suffix);
+        EXECUTE format('DROP TABLE IF EXISTS %I', 'wallet_stream_heads' || suffix);
+    END LOOP;
+END $$;
@@ -0,0 +1,46 @@
+-- Commit-ordered position of a history row in the stream of its wallet, for GET
+-- /v1/wallets/{id}/stream in asset-query-service. transaction_id is drawn before a projection
+-- commits, so projections of the same wallet can commit it out of order. stream_seq is drawn
+-- under the row lock of the wallet's stream head, so it only grows in commit order.
+CREATE TABLE IF NOT EXISTS wallet_stream_heads (
+    wallet_id INT    PRIMARY KEY,
+    last_seq  BIGINT NOT NULL
+);
+
+-- Every version gets the column, numbered in projection order, and its stream heads. The
+-- positions of two versions are unrelated, so the stream event ids carry the version.
+DO $$
+DECLARE
+    v INT;
+    suffix TEXT;
+BEGIN
+    FOR v IN SELECT version FROM projection_versions ORDER BY version
+    LOOP
+        suffix := CASE WHEN v = 1 THEN '' ELSE '_v' || v END;
+
+        IF v > 1 THEN
+            EXECUTE format('CREATE TABLE IF NOT EXISTS %I (LIKE wallet_stream_heads INCLUDING ALL)', 'wallet_stream_heads' || suffix);
+        END IF;
+
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS stream_seq BIGINT', 'wallet_transactions' || suffix);
+
+        EXECUTE format($sql$
+            UPDATE %1$I t SET stream_seq = s.seq
+            FROM (
+                SELECT transaction_id, ROW_NUMBER() OVER (PARTITION BY wallet_id ORDER BY transaction_id) AS seq
+                FROM %1$I
+            ) s
+            WHERE t.transaction_id = s.transaction_id AND t.stream_seq IS NULL
+        $sql$, 'wallet_transactions' || suffix);
+
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN stream_seq SET NOT NULL', 'wallet_transactions' || suffix);
+        EXECUTE format('CREATE UNIQUE INDEX IF NOT EXISTS %I ON %I (wallet_id, stream_seq)',
+            'idx_wallet_transactions' || suffix || '_stream', 'wallet_transactions' || suffix);
+
+        EXECUTE format($sql$
+            INSERT INTO %I (wallet_id, last_seq)
+            SELECT wallet_id, MAX(stream_seq) FROM %I GROUP BY wallet_id
+            ON CONFLICT DO NOTHING
+        $sql$, 'wallet_stream_heads' || suffix, 'wallet_transactions' || suffix);
+    END LOOP;
+END $$;
@@ -0,0 +1,2 @@
+-- The recomputed closing balances are kept, the previous ones were wrong.
+SELECT 1;
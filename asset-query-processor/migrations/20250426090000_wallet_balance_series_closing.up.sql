@@ -0,0 +1,23 @@
+-- The closing balance of a bucket used to be the balance_after of its latest event, which
+-- counts the events projected before it rather than the ones earlier in event time. Recompute
+-- it as the running sum of the net flows, the way asset-query-processor now maintains it.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOR t IN
+        SELECT CASE WHEN version = 1 THEN 'wallet_balance_series' ELSE 'wallet_balance_series_v' || version END
+        FROM projection_versions
+    LOOP
+        EXECUTE format($sql$
+            UPDATE %1$I s SET closing_balance = c.closing_balance
+            FROM (
+                SELECT wallet_id, asset_name, granularity, bucket_start,
+                       SUM(net) OVER (PARTITION BY wallet_id, asset_name, granularity ORDER BY bucket_start) AS closing_balance
+                FROM %1$I
+            ) c
+            WHERE s.wallet_id = c.wallet_id AND s.asset_name = c.asset_name
+              AND s.granularity = c.granularity AND s.bucket_start = c.bucket_start
+        $sql$, t);
+    END LOOP;
+END $$;
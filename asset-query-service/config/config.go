@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -9,11 +10,13 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App   `yaml:"app"`
-		HTTP  `yaml:"http"`
-		Log   `yaml:"logger"`
-		PG    `yaml:"postgres"`
-		Mocky `yaml:"mocky"`
+		App         `yaml:"app"`
+		HTTP        `yaml:"http"`
+		GRPC        `yaml:"grpc"`
+		Log         `yaml:"logger"`
+		PG          `yaml:"postgres"`
+		Mocky       `yaml:"mocky"`
+		Consistency `yaml:"consistency"`
 	}
 
 	// App -.
@@ -27,6 +30,11 @@ type (
 		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
 	}
 
+	// GRPC -.
+	GRPC struct {
+		Port string `env-required:"true" yaml:"port" env:"GRPC_PORT"`
+	}
+
 	// Log -.
 	Log struct {
 		Level string `env-required:"true" yaml:"log_level"   env:"LOG_LEVEL"`
@@ -40,6 +48,11 @@ type (
 	Mocky struct {
 		URL string `env-required:"true"    `
 	}
+
+	// Consistency -.
+	Consistency struct {
+		Wait time.Duration `env-default:"2s" yaml:"wait" env:"CONSISTENCY_WAIT"` // Longest wait for a consistency token
+	}
 )
 
 func NewConfig() (*Config, error) {
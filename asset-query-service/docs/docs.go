@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/wallets/{id}/assets": {
+        "/alert-rules": {
             "get": {
-                "description": "Get all assets for a specific wallet by its ID",
+                "description": "Get the alert rules, optionally only those of a wallet or an asset, oldest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,28 +25,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "wallets"
+                    "alerts"
                 ],
-                "summary": "Retrieve all assets of a wallet",
-                "operationId": "get-all-assets",
+                "summary": "List alert rules",
+                "operationId": "list-alert-rules",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Wallet ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "wallet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.AssetResponse"
+                            "$ref": "#/definitions/v1.AlertRulesResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a rule that appends an alert event to the event journal when a movement of a wallet asset meets its condition: balance_below fires when a debit leaves the balance under the threshold, movement_above when a single debit or credit exceeds it. A rule fires at most once per cooldown. Alerts reach webhook subscriptions of the alert event type and wallet notifications of the alert kind.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Create an alert rule",
+                "operationId": "create-alert-rule",
+                "parameters": [
+                    {
+                        "description": "Alert rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/v1.response"
                         }
@@ -60,9 +110,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallets/{id}/assets/{asset}": {
+        "/alert-rules/{id}": {
             "get": {
-                "description": "Get the balance of a specific asset in a wallet",
+                "description": "Get an alert rule by its ID, with the event time it last fired at",
                 "consumes": [
                     "application/json"
                 ],
@@ -70,31 +120,88 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "wallets"
+                    "alerts"
                 ],
-                "summary": "Retrieve balance of a specific asset",
-                "operationId": "get-asset-balance",
+                "summary": "Retrieve an alert rule",
+                "operationId": "get-alert-rule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wallet ID",
+                        "description": "Alert Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace every field of an alert rule. The rule keeps the time it last fired, so its cooldown carries over.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Replace an alert rule",
+                "operationId": "update-alert-rule",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Asset Name",
-                        "name": "asset",
+                        "type": "integer",
+                        "description": "Alert Rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Alert rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AlertRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.AssetBalanceResponse"
+                            "$ref": "#/definitions/v1.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
                         }
                     },
                     "404": {
@@ -110,11 +217,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/wallets/{id}/transactions": {
-            "get": {
-                "description": "Get the transaction history for a wallet",
+            },
+            "delete": {
+                "description": "Delete an alert rule. Alerts it already fired are still published.",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,24 +227,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "wallets"
+                    "alerts"
                 ],
-                "summary": "Retrieve transaction history",
-                "operationId": "get-transaction-history",
+                "summary": "Delete an alert rule",
+                "operationId": "delete-alert-rule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Wallet ID",
+                        "description": "Alert Rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/v1.TransactionHistoryResponse"
+                            "$ref": "#/definitions/v1.response"
                         }
                     },
                     "404": {
@@ -156,92 +264,1334 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.Transaction": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "description": "Transaction amount",
-                    "type": "number"
-                },
-                "asset_name": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "description": "Timestamp when the transaction occurred",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "Unique transaction ID",
-                    "type": "integer"
-                },
-                "target_wallet_id": {
-                    "description": "For transfer transactions",
-                    "type": "integer"
-                },
-                "type": {
-                    "description": "\"withdraw\", \"deposit\", or \"transfer\"",
-                    "type": "string"
-                },
-                "wallet_id": {
-                    "description": "Wallet associated with the transaction",
-                    "type": "integer"
-                }
-            }
         },
-        "entity.WalletAsset": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "description": "The amount of the asset",
-                    "type": "number"
-                },
-                "asset_name": {
-                    "description": "The name of the asset (e.g., BTC, ETH)",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "description": "Timestamp of the last update",
-                    "type": "string"
-                },
-                "wallet_id": {
-                    "description": "The ID of the wallet",
-                    "type": "integer"
+        "/assets/{asset}/holders": {
+            "get": {
+                "description": "Get a page of the wallets holding a positive balance of an asset, largest balance first. Pass next_cursor of a page as cursor to get the next one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "assets"
+                ],
+                "summary": "Retrieve holders of an asset",
+                "operationId": "get-asset-holders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AssetHoldersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
                 }
             }
         },
-        "v1.AssetBalanceResponse": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "asset_name": {
-                    "type": "string"
-                },
-                "error": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "wallet_id": {
-                    "type": "integer"
+        "/commands/{id}": {
+            "get": {
+                "description": "Get how far the read model has projected a command of asset-management-service, by its consistency token. A command is applied once its events are projected, scheduled while a transfer waits for its execute time, and pending otherwise: still queued, rejected or unknown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "commands"
+                ],
+                "summary": "Retrieve the status of a command",
+                "operationId": "get-command-status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Command ID (consistency token)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CommandStatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
                 }
             }
         },
-        "v1.AssetResponse": {
-            "type": "object",
-            "properties": {
-                "assets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.WalletAsset"
-                    }
-                },
-                "error": {
+        "/graphql": {
+            "post": {
+                "description": "Query wallets, their balances, transactions, balance series and scheduled transfers, and assets in one round trip. The schema is served by introspection. A query marked stale in the extensions was answered before the consistency token was projected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "Run a GraphQL query",
+                "operationId": "graphql",
+                "parameters": [
+                    {
+                        "description": "GraphQL query",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.GraphQLRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GraphQLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/daily": {
+            "get": {
+                "description": "Get the deposit, withdraw and transfer volumes, active wallets and total holdings of every asset per UTC day. Defaults to the last 30 days; at most 366 days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Retrieve daily statistics",
+                "operationId": "get-daily-stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, inclusive (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, inclusive (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DailyStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/summary": {
+            "get": {
+                "description": "Get the volumes and distinct active wallets of every asset over a range of UTC days, with the total holdings at the end of the range. Defaults to the last 30 days; at most 366 days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Retrieve statistics summary",
+                "operationId": "get-stats-summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, inclusive (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, inclusive (YYYY-MM-DD), defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.StatsSummaryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/balances:batch": {
+            "post": {
+                "description": "Get the balances of up to 500 wallets in one request, optionally restricted to some assets. Wallets are returned in the order of the request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Retrieve balances of several wallets",
+                "operationId": "get-balances-batch",
+                "parameters": [
+                    {
+                        "description": "Wallets and assets",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BalancesBatchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BalancesBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{id}/assets": {
+            "get": {
+                "description": "Get all assets for a specific wallet by its ID, or their balances at the as_of instant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Retrieve all assets of a wallet",
+                "operationId": "get-all-assets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Point in time of the balances (RFC 3339)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AssetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{id}/assets/{asset}": {
+            "get": {
+                "description": "Get the balance of a specific asset in a wallet, or its balance at the as_of instant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Retrieve balance of a specific asset",
+                "operationId": "get-asset-balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Point in time of the balance (RFC 3339)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.AssetBalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{id}/assets/{asset}/series": {
+            "get": {
+                "description": "Get one point per UTC hour or day with the inflow, outflow, net and closing balance of a wallet asset. Defaults to the last 48 hours or 30 days; at most 1000 points.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Retrieve balance series of a specific asset",
+                "operationId": "get-balance-series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Bucket size",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From time, inclusive (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To time, exclusive (RFC 3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BalanceSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{id}/stream": {
+            "get": {
+                "description": "Server-Sent Events, or WebSocket messages of the form {\"id\",\"event\",\"data\"} when the request is a WebSocket upgrade. A new stream starts with a snapshot event of all balances, then sends a transaction and a balance event for every history row asset-query-processor projects. Event ids are \"\u003cprojection version\u003e-\u003cposition\u003e\". Pass the id of the last event received as Last-Event-ID (or last_event_id) to resume without a snapshot. A stream resumed after an id of another projection version, or open while the active version switches, gets a fresh snapshot of the active version.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Stream balance changes and transactions of a wallet",
+                "operationId": "stream-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of the last event received, resumes after it",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of the last event received, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.streamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{id}/transactions": {
+            "get": {
+                "description": "Get a page of the transaction history for a wallet. Pass next_cursor of a page as cursor to get the next one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "Retrieve transaction history",
+                "operationId": "get-transaction-history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Asset Name",
+                        "name": "asset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "withdraw",
+                            "deposit",
+                            "transfer",
+                            "balance_correction"
+                        ],
+                        "type": "string",
+                        "description": "Transaction type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum absolute amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum absolute amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From time, inclusive (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To time, exclusive (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Counterparty wallet ID of transfers",
+                        "name": "counterparty",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order by event time",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Consistency token of a command, waits until the command is projected",
+                        "name": "X-Consistency-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TransactionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.AlertRule": {
+            "type": "object",
+            "properties": {
+                "asset_name": {
+                    "type": "string",
+                    "example": "BTC"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "balance_below",
+                        "movement_above"
+                    ],
+                    "example": "balance_below"
+                },
+                "cooldown_seconds": {
+                    "description": "Least time between two alerts",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_fired_at": {
+                    "description": "Event time of the last alert",
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "entity.AlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "asset_name",
+                "condition",
+                "wallet_id"
+            ],
+            "properties": {
+                "asset_name": {
+                    "type": "string",
+                    "example": "BTC"
+                },
+                "condition": {
+                    "type": "string",
+                    "enum": [
+                        "balance_below",
+                        "movement_above"
+                    ],
+                    "example": "balance_below"
+                },
+                "cooldown_seconds": {
+                    "description": "Optional, every triggering movement alerts without it",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Hot wallet BTC float"
+                },
+                "enabled": {
+                    "description": "Optional, defaults to true",
+                    "type": "boolean"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "entity.AssetDailyStats": {
+            "type": "object",
+            "properties": {
+                "active_wallets": {
+                    "description": "Wallets with a deposit, withdraw or transfer",
+                    "type": "integer"
+                },
+                "asset_name": {
+                    "type": "string"
+                },
+                "day": {
+                    "type": "string"
+                },
+                "deposit_count": {
+                    "type": "integer"
+                },
+                "deposit_volume": {
+                    "type": "number"
+                },
+                "net_flow": {
+                    "description": "Change of the total holdings",
+                    "type": "number"
+                },
+                "total_holdings": {
+                    "description": "Sum of all balances at the end of the day",
+                    "type": "number"
+                },
+                "transfer_count": {
+                    "type": "integer"
+                },
+                "transfer_volume": {
+                    "type": "number"
+                },
+                "withdraw_count": {
+                    "type": "integer"
+                },
+                "withdraw_volume": {
+                    "description": "Positive",
+                    "type": "number"
+                }
+            }
+        },
+        "entity.AssetHolder": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.AssetStatsSummary": {
+            "type": "object",
+            "properties": {
+                "active_wallets": {
+                    "description": "Distinct wallets active on any day of the range",
+                    "type": "integer"
+                },
+                "asset_name": {
+                    "type": "string"
+                },
+                "deposit_count": {
+                    "type": "integer"
+                },
+                "deposit_volume": {
+                    "type": "number"
+                },
+                "net_flow": {
+                    "type": "number"
+                },
+                "total_holdings": {
+                    "description": "Sum of all balances at the end of the range",
+                    "type": "number"
+                },
+                "transfer_count": {
+                    "type": "integer"
+                },
+                "transfer_volume": {
+                    "type": "number"
+                },
+                "withdraw_count": {
+                    "type": "integer"
+                },
+                "withdraw_volume": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.BalancePoint": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance at the end of the bucket",
+                    "type": "number"
+                },
+                "bucket_start": {
+                    "description": "Start of the hour or day",
+                    "type": "string"
+                },
+                "inflow": {
+                    "description": "Sum of the credits",
+                    "type": "number"
+                },
+                "net": {
+                    "description": "Inflow minus outflow",
+                    "type": "number"
+                },
+                "outflow": {
+                    "description": "Sum of the debits, positive",
+                    "type": "number"
+                }
+            }
+        },
+        "entity.ScheduledTransfer": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "asset_name": {
+                    "type": "string"
+                },
+                "command_id": {
+                    "type": "string"
+                },
+                "execute_time": {
+                    "type": "string"
+                },
+                "executed_at": {
+                    "type": "string"
+                },
+                "from_wallet_id": {
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"scheduled\" or \"executed\"",
+                    "type": "string"
+                },
+                "to_wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.Transaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Signed, negative for debits",
+                    "type": "number"
+                },
+                "asset_name": {
+                    "type": "string"
+                },
+                "balance_after": {
+                    "description": "Balance of the wallet asset after the transaction",
+                    "type": "number"
+                },
+                "counterparty_wallet_id": {
+                    "description": "The other wallet of a transfer",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "Timestamp when the transaction was projected",
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "Event the transaction was projected from",
+                    "type": "string"
+                },
+                "event_time": {
+                    "description": "Timestamp when the transaction occurred",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique transaction ID",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "\"withdraw\", \"deposit\", \"transfer\" or \"balance_correction\"",
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "description": "Wallet associated with the transaction",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.WalletAsset": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "The amount of the asset",
+                    "type": "number"
+                },
+                "asset_name": {
+                    "description": "The name of the asset (e.g., BTC, ETH)",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "Timestamp of the last update",
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "description": "The ID of the wallet",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.WalletBalances": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WalletAsset"
+                    }
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.AlertRuleResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "rule": {
+                    "$ref": "#/definitions/entity.AlertRule"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AlertRulesResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AlertRule"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AssetBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "as_of": {
+                    "description": "Set for point-in-time queries",
+                    "type": "string"
+                },
+                "asset_name": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "The consistency token was not projected in time",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.AssetHoldersResponse": {
+            "type": "object",
+            "properties": {
+                "asset_name": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "holders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AssetHolder"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Empty on the last page",
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "The consistency token was not projected in time",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AssetResponse": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "description": "Set for point-in-time queries",
+                    "type": "string"
+                },
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WalletAsset"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "The consistency token was not projected in time",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.BalanceSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "asset_name": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BalancePoint"
+                    }
+                },
+                "stale": {
+                    "description": "The consistency token was not projected in time",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.BalancesBatchRequest": {
+            "type": "object",
+            "required": [
+                "wallet_ids"
+            ],
+            "properties": {
+                "assets": {
+                    "description": "Optional, all assets when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "BTC",
+                        "ETH"
+                    ]
+                },
+                "wallet_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "v1.BalancesBatchResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "The consistency token was not projected in time",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.WalletBalances"
+                    }
+                }
+            }
+        },
+        "v1.CommandStatusResponse": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "description": "When its first event was projected",
+                    "type": "string"
+                },
+                "command_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "scheduled_transfer": {
+                    "description": "Set on a deferred transfer",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ScheduledTransfer"
+                        }
+                    ]
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "applied",
+                        "scheduled",
+                        "pending"
+                    ]
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "description": "History rows of its events",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Transaction"
+                    }
+                }
+            }
+        },
+        "v1.DailyStatsResponse": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AssetDailyStats"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.GraphQLRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ wallet(id: 1) { balances { amount } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "v1.GraphQLResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "extensions": {
+                    "description": "\"stale\": the consistency token was not projected in time",
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "v1.StatsSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AssetStatsSummary"
+                    }
+                },
+                "error": {
                     "type": "string"
                 },
                 "status": {
@@ -258,6 +1608,14 @@ const docTemplate = `{
                 "error": {
                     "type": "string"
                 },
+                "next_cursor": {
+                    "description": "Empty on the last page",
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "The consistency token was not projected in time",
+                    "type": "boolean"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -277,6 +1635,19 @@ const docTemplate = `{
                     "example": "detail"
                 }
             }
+        },
+        "v1.streamEvent": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "event": {
+                    "description": "\"snapshot\", \"transaction\" or \"balance\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -1,21 +1,28 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/config"
+	grpcv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/controller/grpc/v1"
 	v1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/controller/http/v1"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase/repo"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/grpcserver"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/httpserver"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+	"google.golang.org/grpc"
 )
 
+const _versionRefreshInterval = 5 * time.Second
+
 func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
@@ -26,16 +33,47 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
+	// Read the projection version activated in asset-query-processor
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	versions := repo.NewProjectionVersion(pg)
+	if err := versions.Follow(ctx, _versionRefreshInterval); err != nil {
+		l.Error(fmt.Errorf("app - Run - versions.Follow: %w", err))
+	}
+	l.Info("Reading projection version %d", versions.Version())
+
 	walletQueryUseCase := usecase.NewWalletQueryUseCase(
-		repo.NewWalletQueryRepo(pg),
+		repo.NewWalletQueryRepo(pg, versions),
 		l,
 	)
 
+	balanceSeriesUseCase := usecase.NewBalanceSeriesUseCase(repo.NewBalanceSeriesRepo(pg, versions))
+	portfolioUseCase := usecase.NewPortfolioUseCase(repo.NewPortfolioRepo(pg, versions))
+	statsUseCase := usecase.NewStatsUseCase(repo.NewStatsRepo(pg, versions))
+	scheduledTransferUseCase := usecase.NewScheduledTransferUseCase(repo.NewScheduledTransferRepo(pg, versions))
+	commandUseCase := usecase.NewCommandUseCase(repo.NewCommandRepo(pg, versions))
+	walletStreamUseCase := usecase.NewWalletStreamUseCase(repo.NewWalletStreamRepo(pg, versions), l)
+
+	// Read-your-writes
+	consistencyUseCase := usecase.NewConsistencyUseCase(repo.NewConsistencyRepo(pg, versions), cfg.Consistency.Wait)
+
+	// Alert rules evaluated by asset-query-processor
+	alertRuleUseCase := usecase.NewAlertRuleUseCase(repo.NewAlertRuleRepo(pg), l)
+
+	// Wake up the wallet streams when asset-query-processor projects history rows
+	go walletStreamUseCase.ListenTransactions(ctx)
+
 	// HTTP Server
 	handler := gin.New()
-	v1.NewRouter(handler, l, walletQueryUseCase)
+	v1.NewRouter(handler, l, walletQueryUseCase, balanceSeriesUseCase, portfolioUseCase, statsUseCase, scheduledTransferUseCase, commandUseCase, walletStreamUseCase, consistencyUseCase, alertRuleUseCase)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
+	// gRPC Server
+	grpcServer := grpcserver.New(func(s *grpc.Server) {
+		grpcv1.Register(s, l, walletQueryUseCase, balanceSeriesUseCase, portfolioUseCase, walletStreamUseCase)
+	}, grpcserver.Port(cfg.GRPC.Port), grpcserver.UnaryInterceptor(grpcv1.ConsistencyInterceptor(l, consistencyUseCase)))
+
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
@@ -48,6 +86,10 @@ func Run(cfg *config.Config) {
 	case err = <-httpServer.Notify():
 
 		l.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
+
+	case err = <-grpcServer.Notify():
+
+		l.Error(fmt.Errorf("app - Run - grpcServer.Notify: %w", err))
 	}
 
 	// Shutdown
@@ -57,6 +99,11 @@ func Run(cfg *config.Config) {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
 
+	err = grpcServer.Shutdown()
+	if err != nil {
+		l.Error(fmt.Errorf("app - Run - grpcServer.Shutdown: %w", err))
+	}
+
 	/*
 	   messageSenderWebAPI := webapi.NewMessageSenderWebAPI(cfg.Mocky.URL)
 	   // Use case
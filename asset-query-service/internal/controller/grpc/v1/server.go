@@ -0,0 +1,64 @@
+// Package v1 implements the gRPC API of the wallet query service.
+package v1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	walletqueryv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/api/walletquery/v1"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys of read-your-writes, the counterparts of the X-Consistency-Token and
+// X-Consistency-Stale headers of the HTTP API.
+const (
+	_consistencyTokenKey = "x-consistency-token"
+	_consistencyStaleKey = "x-consistency-stale"
+)
+
+// Register adds the v1 services to a gRPC server.
+func Register(
+	s *grpc.Server,
+	l logger.Interface,
+	t usecase.WalletQueryUseCaseHandler,
+	series usecase.BalanceSeriesHandler,
+	portfolio usecase.PortfolioHandler,
+	stream usecase.WalletStreamHandler,
+) {
+	walletqueryv1.RegisterWalletQueryServiceServer(s, &walletQueryServer{t: t, series: series, portfolio: portfolio, stream: stream, l: l})
+}
+
+// ConsistencyInterceptor waits until the command of the consistency token in the
+// x-consistency-token metadata is projected before a unary call of the query service.
+// When the wait runs out the call is answered from the current state with
+// x-consistency-stale header metadata.
+func ConsistencyInterceptor(l logger.Interface, t usecase.ConsistencyHandler) grpc.UnaryServerInterceptor {
+	prefix := "/" + walletqueryv1.WalletQueryService_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		tokens := md.Get(_consistencyTokenKey)
+		if len(tokens) == 0 || tokens[0] == "" {
+			return handler(ctx, req)
+		}
+
+		applied, err := t.AwaitCommand(ctx, tokens[0])
+		if err != nil {
+			l.Error(err, "grpc - v1 - ConsistencyInterceptor")
+		}
+		if !applied {
+			if err := grpc.SetHeader(ctx, metadata.Pairs(_consistencyStaleKey, "true")); err != nil {
+				l.Error(err, "grpc - v1 - ConsistencyInterceptor - SetHeader")
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
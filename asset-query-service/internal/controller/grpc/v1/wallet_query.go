@@ -0,0 +1,243 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	walletqueryv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/api/walletquery/v1"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type walletQueryServer struct {
+	walletqueryv1.UnimplementedWalletQueryServiceServer
+
+	t         usecase.WalletQueryUseCaseHandler
+	series    usecase.BalanceSeriesHandler
+	portfolio usecase.PortfolioHandler
+	stream    usecase.WalletStreamHandler
+	l         logger.Interface
+}
+
+func (s *walletQueryServer) GetAssets(ctx context.Context, req *walletqueryv1.GetAssetsRequest) (*walletqueryv1.GetAssetsResponse, error) {
+	var (
+		assets []entity.WalletAsset
+		err    error
+	)
+	if req.GetAsOf() != nil {
+		assets, err = s.t.GetAllAssetsAsOf(ctx, int(req.GetWalletId()), req.GetAsOf().AsTime())
+	} else {
+		assets, err = s.t.GetAllAssets(ctx, int(req.GetWalletId()))
+	}
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - GetAssets")
+		return nil, status.Error(codes.Internal, "Failed to retrieve assets")
+	}
+
+	return &walletqueryv1.GetAssetsResponse{Assets: walletAssets(assets)}, nil
+}
+
+func (s *walletQueryServer) GetAssetBalance(ctx context.Context, req *walletqueryv1.GetAssetBalanceRequest) (*walletqueryv1.GetAssetBalanceResponse, error) {
+	var (
+		asset *entity.WalletAsset
+		err   error
+	)
+	if req.GetAsOf() != nil {
+		asset, err = s.t.GetAssetBalanceAsOf(ctx, int(req.GetWalletId()), req.GetAssetName(), req.GetAsOf().AsTime())
+	} else {
+		asset, err = s.t.GetAssetBalance(ctx, int(req.GetWalletId()), req.GetAssetName())
+	}
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - GetAssetBalance")
+		return nil, status.Error(codes.Internal, "Failed to retrieve asset balance")
+	}
+
+	asset.WalletID = int(req.GetWalletId())
+	return &walletqueryv1.GetAssetBalanceResponse{Asset: walletAsset(asset)}, nil
+}
+
+func (s *walletQueryServer) GetBalanceSeries(ctx context.Context, req *walletqueryv1.GetBalanceSeriesRequest) (*walletqueryv1.GetBalanceSeriesResponse, error) {
+	interval := req.GetInterval()
+	if interval == "" {
+		interval = "day"
+	}
+
+	// Zero times select the defaults of the use case
+	var from, to time.Time
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+
+	points, err := s.series.GetBalanceSeries(ctx, int(req.GetWalletId()), req.GetAssetName(), interval, from, to)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInterval):
+		return nil, status.Error(codes.InvalidArgument, "Invalid interval")
+	case errors.Is(err, usecase.ErrInvalidRange):
+		return nil, status.Error(codes.InvalidArgument, "Invalid range")
+	case err != nil:
+		s.l.Error(err, "grpc - v1 - GetBalanceSeries")
+		return nil, status.Error(codes.Internal, "Failed to retrieve balance series")
+	}
+
+	response := &walletqueryv1.GetBalanceSeriesResponse{Points: make([]*walletqueryv1.BalancePoint, 0, len(points))}
+	for _, point := range points {
+		response.Points = append(response.Points, &walletqueryv1.BalancePoint{
+			BucketStart: timestamppb.New(point.BucketStart),
+			Inflow:      point.Inflow,
+			Outflow:     point.Outflow,
+			Net:         point.Net,
+			Balance:     point.Balance,
+		})
+	}
+
+	return response, nil
+}
+
+func (s *walletQueryServer) GetTransactionHistory(ctx context.Context, req *walletqueryv1.GetTransactionHistoryRequest) (*walletqueryv1.GetTransactionHistoryResponse, error) {
+	filter := entity.TransactionFilter{
+		WalletID:  int(req.GetWalletId()),
+		AssetName: req.GetAssetName(),
+		Type:      req.GetType(),
+		MinAmount: req.MinAmount,
+		MaxAmount: req.MaxAmount,
+		Ascending: req.GetAscending(),
+		Limit:     int(req.GetLimit()),
+	}
+
+	switch filter.Type {
+	case "", "withdraw", "deposit", "transfer", "balance_correction":
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Invalid type")
+	}
+
+	if req.GetFrom() != nil {
+		from := req.GetFrom().AsTime()
+		filter.From = &from
+	}
+	if req.GetTo() != nil {
+		to := req.GetTo().AsTime()
+		filter.To = &to
+	}
+	if req.Counterparty != nil {
+		counterparty := int(req.GetCounterparty())
+		filter.Counterparty = &counterparty
+	}
+
+	page, err := s.t.GetTransactionHistory(ctx, filter, req.GetCursor())
+	if errors.Is(err, usecase.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+	}
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - GetTransactionHistory")
+		return nil, status.Error(codes.Internal, "Failed to retrieve transaction history")
+	}
+
+	response := &walletqueryv1.GetTransactionHistoryResponse{
+		Transactions: make([]*walletqueryv1.Transaction, 0, len(page.Transactions)),
+		NextCursor:   page.NextCursor,
+	}
+	for i := range page.Transactions {
+		response.Transactions = append(response.Transactions, transaction(&page.Transactions[i]))
+	}
+
+	return response, nil
+}
+
+func (s *walletQueryServer) GetBalancesBatch(ctx context.Context, req *walletqueryv1.GetBalancesBatchRequest) (*walletqueryv1.GetBalancesBatchResponse, error) {
+	walletIDs := make([]int, 0, len(req.GetWalletIds()))
+	for _, id := range req.GetWalletIds() {
+		walletIDs = append(walletIDs, int(id))
+	}
+
+	wallets, err := s.portfolio.GetBalancesBatch(ctx, walletIDs, req.GetAssets())
+	if errors.Is(err, usecase.ErrInvalidBatch) {
+		return nil, status.Error(codes.InvalidArgument, "Between 1 and 500 wallet ids are required")
+	}
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - GetBalancesBatch")
+		return nil, status.Error(codes.Internal, "Failed to retrieve balances")
+	}
+
+	response := &walletqueryv1.GetBalancesBatchResponse{Wallets: make([]*walletqueryv1.WalletBalances, 0, len(wallets))}
+	for _, wallet := range wallets {
+		response.Wallets = append(response.Wallets, &walletqueryv1.WalletBalances{
+			WalletId: int64(wallet.WalletID),
+			Assets:   walletAssets(wallet.Assets),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *walletQueryServer) GetAssetHolders(ctx context.Context, req *walletqueryv1.GetAssetHoldersRequest) (*walletqueryv1.GetAssetHoldersResponse, error) {
+	page, err := s.portfolio.GetAssetHolders(ctx, req.GetAssetName(), int(req.GetLimit()), req.GetCursor())
+	if errors.Is(err, usecase.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid cursor")
+	}
+	if err != nil {
+		s.l.Error(err, "grpc - v1 - GetAssetHolders")
+		return nil, status.Error(codes.Internal, "Failed to retrieve asset holders")
+	}
+
+	response := &walletqueryv1.GetAssetHoldersResponse{
+		Holders:    make([]*walletqueryv1.AssetHolder, 0, len(page.Holders)),
+		NextCursor: page.NextCursor,
+	}
+	for _, holder := range page.Holders {
+		response.Holders = append(response.Holders, &walletqueryv1.AssetHolder{
+			WalletId: int64(holder.WalletID),
+			Amount:   holder.Amount,
+		})
+	}
+
+	return response, nil
+}
+
+// **Conversions**
+
+func walletAssets(assets []entity.WalletAsset) []*walletqueryv1.WalletAsset {
+	out := make([]*walletqueryv1.WalletAsset, 0, len(assets))
+	for i := range assets {
+		out = append(out, walletAsset(&assets[i]))
+	}
+	return out
+}
+
+func walletAsset(asset *entity.WalletAsset) *walletqueryv1.WalletAsset {
+	out := &walletqueryv1.WalletAsset{
+		WalletId:  int64(asset.WalletID),
+		AssetName: asset.AssetName,
+		Amount:    asset.Amount,
+	}
+	if !asset.UpdatedAt.IsZero() {
+		out.UpdatedAt = timestamppb.New(asset.UpdatedAt)
+	}
+	return out
+}
+
+func transaction(txn *entity.Transaction) *walletqueryv1.Transaction {
+	out := &walletqueryv1.Transaction{
+		Id:           txn.ID,
+		EventId:      txn.EventID,
+		WalletId:     int64(txn.WalletID),
+		Type:         txn.Type,
+		AssetName:    txn.AssetName,
+		Amount:       txn.Amount,
+		BalanceAfter: txn.BalanceAfter,
+		EventTime:    timestamppb.New(txn.EventTime),
+		CreatedAt:    timestamppb.New(txn.CreatedAt),
+	}
+	if txn.CounterpartyWalletID != nil {
+		counterparty := int64(*txn.CounterpartyWalletID)
+		out.CounterpartyWalletId = &counterparty
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	walletqueryv1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/api/walletquery/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchWallet streams the events of StreamWallet. Heartbeats are dropped, HTTP/2 keeps
+// the connection alive.
+func (s *walletQueryServer) WatchWallet(req *walletqueryv1.WatchWalletRequest, stream grpc.ServerStreamingServer[walletqueryv1.WatchWalletResponse]) error {
+	send := func(ev entity.WalletStreamEvent) error {
+		out := &walletqueryv1.WatchWalletResponse{Id: ev.ID.Seq, Version: int32(ev.ID.Version)}
+		switch ev.Event {
+		case "snapshot":
+			out.Event = &walletqueryv1.WatchWalletResponse_Snapshot_{Snapshot: &walletqueryv1.WatchWalletResponse_Snapshot{
+				WalletId: int64(ev.WalletID),
+				Assets:   walletAssets(ev.Assets),
+			}}
+		case "transaction":
+			out.Event = &walletqueryv1.WatchWalletResponse_Transaction{Transaction: transaction(ev.Transaction)}
+		case "balance":
+			out.Event = &walletqueryv1.WatchWalletResponse_Balance{Balance: walletAsset(ev.Balance)}
+		default:
+			return nil
+		}
+		return stream.Send(out)
+	}
+
+	var after *entity.StreamPosition
+	if req.LastEventId != nil {
+		after = &entity.StreamPosition{Version: int(req.GetLastEventVersion()), Seq: req.GetLastEventId()}
+	}
+
+	if err := s.stream.StreamWallet(stream.Context(), int(req.GetWalletId()), after, send); err != nil {
+		s.l.Error(err, "grpc - v1 - WatchWallet")
+		return status.Error(codes.Internal, "Failed to stream wallet")
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+type alertRuleRoutes struct {
+	t usecase.AlertRuleHandler
+	l logger.Interface
+}
+
+func newAlertRuleRoutes(handler *gin.RouterGroup, t usecase.AlertRuleHandler, l logger.Interface) {
+	r := &alertRuleRoutes{t, l}
+
+	h := handler.Group("/alert-rules")
+	{
+		h.POST("", r.CreateAlertRule)       // Create an alert rule
+		h.GET("", r.ListAlertRules)         // Retrieve alert rules
+		h.GET("/:id", r.GetAlertRule)       // Retrieve an alert rule
+		h.PUT("/:id", r.UpdateAlertRule)    // Replace an alert rule
+		h.DELETE("/:id", r.DeleteAlertRule) // Delete an alert rule
+	}
+}
+
+// **Response Structs**
+
+type AlertRuleResponse struct {
+	Rule   *entity.AlertRule `json:"rule,omitempty"`
+	Status string            `json:"status"`
+	Error  string            `json:"error,omitempty"`
+}
+
+type AlertRulesResponse struct {
+	Rules  []entity.AlertRule `json:"rules"`
+	Count  int                `json:"count"`
+	Status string             `json:"status"`
+	Error  string             `json:"error,omitempty"`
+}
+
+// **Route Handlers**
+
+// @Summary     Create an alert rule
+// @Description Create a rule that appends an alert event to the event journal when a movement of a wallet asset meets its condition: balance_below fires when a debit leaves the balance under the threshold, movement_above when a single debit or credit exceeds it. A rule fires at most once per cooldown. Alerts reach webhook subscriptions of the alert event type and wallet notifications of the alert kind.
+// @ID          create-alert-rule
+// @Tags        alerts
+// @Accept      json
+// @Produce     json
+// @Param       rule body entity.AlertRuleRequest true "Alert rule"
+// @Success     201 {object} AlertRuleResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /alert-rules [post]
+func (r *alertRuleRoutes) CreateAlertRule(c *gin.Context) {
+	var req entity.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - CreateAlertRule - invalid input")
+		r.handleError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	rule, err := r.t.CreateAlertRule(c.Request.Context(), req)
+	if err != nil {
+		r.useCaseError(c, err, "Failed to create alert rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, AlertRuleResponse{Rule: rule, Status: "success"})
+}
+
+// @Summary     List alert rules
+// @Description Get the alert rules, optionally only those of a wallet or an asset, oldest first
+// @ID          list-alert-rules
+// @Tags        alerts
+// @Accept      json
+// @Produce     json
+// @Param       wallet_id query int false "Wallet ID"
+// @Param       asset query string false "Asset Name"
+// @Success     200 {object} AlertRulesResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /alert-rules [get]
+func (r *alertRuleRoutes) ListAlertRules(c *gin.Context) {
+	filter := entity.AlertRuleFilter{AssetName: c.Query("asset")}
+	if value := c.Query("wallet_id"); value != "" {
+		walletID, err := strconv.Atoi(value)
+		if err != nil || walletID <= 0 {
+			r.handleError(c, http.StatusBadRequest, "Invalid wallet ID")
+			return
+		}
+		filter.WalletID = walletID
+	}
+
+	rules, err := r.t.ListAlertRules(c.Request.Context(), filter)
+	if err != nil {
+		r.useCaseError(c, err, "Failed to retrieve alert rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, AlertRulesResponse{Rules: rules, Count: len(rules), Status: "success"})
+}
+
+// @Summary     Retrieve an alert rule
+// @Description Get an alert rule by its ID, with the event time it last fired at
+// @ID          get-alert-rule
+// @Tags        alerts
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Alert Rule ID"
+// @Success     200 {object} AlertRuleResponse
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /alert-rules/{id} [get]
+func (r *alertRuleRoutes) GetAlertRule(c *gin.Context) {
+	id, ok := r.ruleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := r.t.GetAlertRule(c.Request.Context(), id)
+	if err != nil {
+		r.useCaseError(c, err, "Failed to retrieve alert rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, AlertRuleResponse{Rule: rule, Status: "success"})
+}
+
+// @Summary     Replace an alert rule
+// @Description Replace every field of an alert rule. The rule keeps the time it last fired, so its cooldown carries over.
+// @ID          update-alert-rule
+// @Tags        alerts
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Alert Rule ID"
+// @Param       rule body entity.AlertRuleRequest true "Alert rule"
+// @Success     200 {object} AlertRuleResponse
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /alert-rules/{id} [put]
+func (r *alertRuleRoutes) UpdateAlertRule(c *gin.Context) {
+	id, ok := r.ruleID(c)
+	if !ok {
+		return
+	}
+
+	var req entity.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - UpdateAlertRule - invalid input")
+		r.handleError(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	rule, err := r.t.UpdateAlertRule(c.Request.Context(), id, req)
+	if err != nil {
+		r.useCaseError(c, err, "Failed to update alert rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, AlertRuleResponse{Rule: rule, Status: "success"})
+}
+
+// @Summary     Delete an alert rule
+// @Description Delete an alert rule. Alerts it already fired are still published.
+// @ID          delete-alert-rule
+// @Tags        alerts
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Alert Rule ID"
+// @Success     204
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /alert-rules/{id} [delete]
+func (r *alertRuleRoutes) DeleteAlertRule(c *gin.Context) {
+	id, ok := r.ruleID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.t.DeleteAlertRule(c.Request.Context(), id); err != nil {
+		r.useCaseError(c, err, "Failed to delete alert rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ruleID reads the alert rule id path parameter, answering 400 when it is not a positive integer.
+func (r *alertRuleRoutes) ruleID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		r.handleError(c, http.StatusBadRequest, "Invalid alert rule ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// useCaseError answers the error of a use case: 404 for a missing rule, 400 for an invalid
+// rule and 500 otherwise.
+func (r *alertRuleRoutes) useCaseError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrAlertRuleNotFound):
+		r.handleError(c, http.StatusNotFound, "Alert rule not found")
+	case errors.Is(err, usecase.ErrInvalidAlertRule):
+		r.handleError(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+	default:
+		r.l.Error(err, "http - v1 - alert rules")
+		r.handleError(c, http.StatusInternalServerError, message)
+	}
+}
+
+// **Helper Function for Error Handling**
+func (r *alertRuleRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
@@ -0,0 +1,108 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+type balanceSeriesRoutes struct {
+	t usecase.BalanceSeriesHandler
+	l logger.Interface
+}
+
+func newBalanceSeriesRoutes(handler *gin.RouterGroup, t usecase.BalanceSeriesHandler, l logger.Interface) {
+	r := &balanceSeriesRoutes{t, l}
+
+	h := handler.Group("/wallets")
+	{
+		h.GET("/:id/assets/:asset/series", r.GetBalanceSeries) // Retrieve balance series of a specific asset
+	}
+}
+
+// **Response Structs**
+
+type BalanceSeriesResponse struct {
+	WalletID  int                   `json:"wallet_id"`
+	AssetName string                `json:"asset_name"`
+	Interval  string                `json:"interval"`
+	Points    []entity.BalancePoint `json:"points"`
+	Stale     bool                  `json:"stale,omitempty"` // The consistency token was not projected in time
+	Status    string                `json:"status"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// **Route Handlers**
+
+// @Summary     Retrieve balance series of a specific asset
+// @Description Get one point per UTC hour or day with the inflow, outflow, net and closing balance of a wallet asset. Defaults to the last 48 hours or 30 days; at most 1000 points.
+// @ID          get-balance-series
+// @Tags        wallets
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Param       asset path string true "Asset Name"
+// @Param       interval query string false "Bucket size" Enums(hour, day) default(day)
+// @Param       from query string false "From time, inclusive (RFC 3339)"
+// @Param       to query string false "To time, exclusive (RFC 3339), defaults to now"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
+// @Success     200 {object} BalanceSeriesResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/assets/{asset}/series [get]
+func (r *balanceSeriesRoutes) GetBalanceSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, "Invalid wallet ID")
+		return
+	}
+
+	// Zero times select the defaults of the use case
+	var from, to time.Time
+	if value, err := timeQuery(c, "from"); err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	} else if value != nil {
+		from = *value
+	}
+	if value, err := timeQuery(c, "to"); err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	} else if value != nil {
+		to = *value
+	}
+
+	assetName, interval := c.Param("asset"), c.DefaultQuery("interval", "day")
+	points, err := r.t.GetBalanceSeries(c.Request.Context(), id, assetName, interval, from, to)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInterval):
+		r.handleError(c, http.StatusBadRequest, "Invalid interval")
+		return
+	case errors.Is(err, usecase.ErrInvalidRange):
+		r.handleError(c, http.StatusBadRequest, "Invalid range")
+		return
+	case err != nil:
+		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve balance series")
+		return
+	}
+
+	c.JSON(http.StatusOK, BalanceSeriesResponse{
+		WalletID:  id,
+		AssetName: assetName,
+		Interval:  interval,
+		Points:    points,
+		Stale:     isStale(c),
+		Status:    "success",
+	})
+}
+
+// **Helper Function for Error Handling**
+func (r *balanceSeriesRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+type commandRoutes struct {
+	t usecase.CommandHandler
+	l logger.Interface
+}
+
+func newCommandRoutes(handler *gin.RouterGroup, t usecase.CommandHandler, l logger.Interface) {
+	r := &commandRoutes{t, l}
+
+	h := handler.Group("/commands")
+	{
+		h.GET("/:id", r.GetCommandStatus) // Retrieve how far a command is projected
+	}
+}
+
+// **Response Structs**
+
+type CommandStatusResponse struct {
+	CommandID         string                    `json:"command_id"`
+	State             string                    `json:"state" enums:"applied,scheduled,pending"`
+	AppliedAt         *time.Time                `json:"applied_at,omitempty"`         // When its first event was projected
+	ScheduledTransfer *entity.ScheduledTransfer `json:"scheduled_transfer,omitempty"` // Set on a deferred transfer
+	Transactions      []entity.Transaction      `json:"transactions"`                 // History rows of its events
+	Status            string                    `json:"status"`
+	Error             string                    `json:"error,omitempty"`
+}
+
+// **Route Handlers**
+
+// @Summary     Retrieve the status of a command
+// @Description Get how far the read model has projected a command of asset-management-service, by its consistency token. A command is applied once its events are projected, scheduled while a transfer waits for its execute time, and pending otherwise: still queued, rejected or unknown.
+// @ID          get-command-status
+// @Tags        commands
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Command ID (consistency token)"
+// @Success     200 {object} CommandStatusResponse
+// @Failure     500 {object} response
+// @Router      /commands/{id} [get]
+func (r *commandRoutes) GetCommandStatus(c *gin.Context) {
+	command, err := r.t.GetCommandStatus(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve command status")
+		return
+	}
+
+	c.JSON(http.StatusOK, CommandStatusResponse{
+		CommandID:         command.CommandID,
+		State:             command.State,
+		AppliedAt:         command.AppliedAt,
+		ScheduledTransfer: command.ScheduledTransfer,
+		Transactions:      command.Transactions,
+		Status:            "success",
+	})
+}
+
+// **Helper Function for Error Handling**
+func (r *commandRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// **Read-your-writes**
+
+const (
+	_consistencyTokenHeader = "X-Consistency-Token"
+	_consistencyStaleHeader = "X-Consistency-Stale"
+	_staleKey               = "stale"
+)
+
+// consistency waits until the command of a consistency token, passed in the
+// X-Consistency-Token header or the consistency_token parameter, is projected.
+// When the wait runs out the request is answered from the current state and
+// marked stale.
+func consistency(t usecase.ConsistencyHandler, l logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(_consistencyTokenHeader)
+		if token == "" {
+			token = c.Query("consistency_token")
+		}
+		if token == "" {
+			return
+		}
+
+		applied, err := t.AwaitCommand(c.Request.Context(), token)
+		if err != nil {
+			l.Error(err, "http - v1 - consistency")
+		}
+		if !applied {
+			c.Set(_staleKey, true)
+			c.Header(_consistencyStaleHeader, "true")
+		}
+	}
+}
+
+func isStale(c *gin.Context) bool {
+	return c.GetBool(_staleKey)
+}
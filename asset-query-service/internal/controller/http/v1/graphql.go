@@ -0,0 +1,103 @@
+package v1
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const (
+	_graphQLMaxDepth       = 8
+	_graphQLMaxParallelism = 64 // Resolvers waiting on one loader batch together
+)
+
+//go:embed schema.graphql
+var _graphQLSchema string
+
+type graphQLRoutes struct {
+	resolver *graphQLResolver
+	schema   *graphql.Schema
+	l        logger.Interface
+}
+
+func newGraphQLRoutes(handler *gin.RouterGroup, resolver *graphQLResolver, l logger.Interface) {
+	r := &graphQLRoutes{
+		resolver: resolver,
+		schema: graphql.MustParseSchema(_graphQLSchema, resolver,
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(_graphQLMaxDepth),
+			graphql.MaxParallelism(_graphQLMaxParallelism),
+		),
+		l: l,
+	}
+
+	h := handler.Group("/graphql")
+	{
+		h.POST("", r.Query) // Run a GraphQL query
+		h.GET("", r.Query)  // Run a GraphQL query passed in the URL
+	}
+}
+
+type GraphQLRequest struct {
+	Query         string         `json:"query" binding:"required" example:"{ wallet(id: 1) { balances { amount } } }"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Errors     []*gqlerrors.QueryError `json:"errors,omitempty" swaggertype:"array,object"`
+	Data       json.RawMessage         `json:"data,omitempty" swaggertype:"object"`
+	Extensions map[string]any          `json:"extensions,omitempty"` // "stale": the consistency token was not projected in time
+}
+
+// @Summary     Run a GraphQL query
+// @Description Query wallets, their balances, transactions, balance series and scheduled transfers, and assets in one round trip. The schema is served by introspection. A query marked stale in the extensions was answered before the consistency token was projected.
+// @ID          graphql
+// @Tags        graphql
+// @Accept      json
+// @Produce     json
+// @Param       request body GraphQLRequest true "GraphQL query"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
+// @Success     200 {object} GraphQLResponse
+// @Failure     400 {object} response
+// @Router      /graphql [post]
+func (r *graphQLRoutes) Query(c *gin.Context) {
+	var request GraphQLRequest
+	if c.Request.Method == http.MethodGet {
+		request.Query = c.Query("query")
+		request.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				r.handleError(c, http.StatusBadRequest, "Invalid variables")
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&request); err != nil {
+		r.handleError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if request.Query == "" {
+		r.handleError(c, http.StatusBadRequest, "Query is required")
+		return
+	}
+
+	// Loaders batch the reads of this request only
+	ctx := withLoaders(c.Request.Context(), newLoaders(r.resolver))
+	result := r.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+
+	response := GraphQLResponse{Errors: result.Errors, Data: result.Data}
+	if isStale(c) {
+		response.Extensions = map[string]any{"stale": true}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// **Helper Function for Error Handling**
+func (r *graphQLRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
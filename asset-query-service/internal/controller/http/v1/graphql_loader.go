@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+)
+
+// _maxLoaderBatch matches the batch limit of the use case.
+const _maxLoaderBatch = 500
+
+type loadersKey struct{}
+
+// transactionsKey selects the first page of the history of a wallet.
+type transactionsKey struct {
+	walletID int
+	limit    int
+}
+
+// scheduledKey selects the first page of the scheduled transfers of a wallet.
+type scheduledKey struct {
+	walletID int
+	status   string
+	limit    int
+}
+
+// loaders collect the wallets that the resolvers of one GraphQL request ask for
+// and read them with one batch query, so nested fields of a list of wallets do
+// not read each wallet on its own. Loaders cache their results, they live for
+// one request.
+type loaders struct {
+	balances     *dataloader.Loader[int, []entity.WalletAsset]
+	transactions *dataloader.Loader[transactionsKey, *entity.TransactionPage]
+	scheduled    *dataloader.Loader[scheduledKey, *entity.ScheduledTransferPage]
+}
+
+func newLoaders(r *graphQLResolver) *loaders {
+	return &loaders{
+		balances: dataloader.NewBatchedLoader(balancesBatch(r.portfolio),
+			dataloader.WithBatchCapacity[int, []entity.WalletAsset](_maxLoaderBatch)),
+		transactions: dataloader.NewBatchedLoader(transactionsBatch(r.t),
+			dataloader.WithBatchCapacity[transactionsKey, *entity.TransactionPage](_maxLoaderBatch)),
+		scheduled: dataloader.NewBatchedLoader(scheduledBatch(r.scheduled),
+			dataloader.WithBatchCapacity[scheduledKey, *entity.ScheduledTransferPage](_maxLoaderBatch)),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders) //nolint:forcetypeassert // set by the GraphQL handler
+}
+
+func balancesBatch(t usecase.PortfolioHandler) dataloader.BatchFunc[int, []entity.WalletAsset] {
+	return func(ctx context.Context, walletIDs []int) []*dataloader.Result[[]entity.WalletAsset] {
+		results := make([]*dataloader.Result[[]entity.WalletAsset], len(walletIDs))
+
+		balances, err := t.GetBalancesBatch(ctx, walletIDs, nil)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[[]entity.WalletAsset]{Error: err}
+			}
+			return results
+		}
+
+		assets := make(map[int][]entity.WalletAsset, len(balances))
+		for _, b := range balances {
+			assets[b.WalletID] = b.Assets
+		}
+		for i, id := range walletIDs {
+			results[i] = &dataloader.Result[[]entity.WalletAsset]{Data: assets[id]}
+		}
+		return results
+	}
+}
+
+// transactionsBatch reads the keys with one query per page size.
+func transactionsBatch(t usecase.WalletQueryUseCaseHandler) dataloader.BatchFunc[transactionsKey, *entity.TransactionPage] {
+	return func(ctx context.Context, keys []transactionsKey) []*dataloader.Result[*entity.TransactionPage] {
+		results := make([]*dataloader.Result[*entity.TransactionPage], len(keys))
+
+		groups := make(map[int][]int) // limit -> key indexes
+		for i, key := range keys {
+			groups[key.limit] = append(groups[key.limit], i)
+		}
+
+		for limit, indexes := range groups {
+			walletIDs := make([]int, len(indexes))
+			for j, i := range indexes {
+				walletIDs[j] = keys[i].walletID
+			}
+
+			pages, err := t.GetTransactionHistoryBatch(ctx, walletIDs, limit)
+			for _, i := range indexes {
+				if err != nil {
+					results[i] = &dataloader.Result[*entity.TransactionPage]{Error: err}
+					continue
+				}
+				results[i] = &dataloader.Result[*entity.TransactionPage]{Data: pages[keys[i].walletID]}
+			}
+		}
+		return results
+	}
+}
+
+// scheduledBatch reads the keys with one query per status and page size.
+func scheduledBatch(t usecase.ScheduledTransferHandler) dataloader.BatchFunc[scheduledKey, *entity.ScheduledTransferPage] {
+	type group struct {
+		status string
+		limit  int
+	}
+
+	return func(ctx context.Context, keys []scheduledKey) []*dataloader.Result[*entity.ScheduledTransferPage] {
+		results := make([]*dataloader.Result[*entity.ScheduledTransferPage], len(keys))
+
+		groups := make(map[group][]int) // key indexes
+		for i, key := range keys {
+			g := group{status: key.status, limit: key.limit}
+			groups[g] = append(groups[g], i)
+		}
+
+		for g, indexes := range groups {
+			walletIDs := make([]int, len(indexes))
+			for j, i := range indexes {
+				walletIDs[j] = keys[i].walletID
+			}
+
+			pages, err := t.GetScheduledTransfersBatch(ctx, walletIDs, g.status, g.limit)
+			for _, i := range indexes {
+				if err != nil {
+					results[i] = &dataloader.Result[*entity.ScheduledTransferPage]{Error: err}
+					continue
+				}
+				results[i] = &dataloader.Result[*entity.ScheduledTransferPage]{Data: pages[keys[i].walletID]}
+			}
+		}
+		return results
+	}
+}
@@ -0,0 +1,497 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// graphQLResolver is the root resolver of the GraphQL schema. Wallets and assets
+// resolve lazily, only their fields read the use case.
+type graphQLResolver struct {
+	t         usecase.WalletQueryUseCaseHandler
+	series    usecase.BalanceSeriesHandler
+	portfolio usecase.PortfolioHandler
+	stats     usecase.StatsHandler
+	scheduled usecase.ScheduledTransferHandler
+	l         logger.Interface
+}
+
+// fail reports the errors of the use case that a client can fix and hides the others.
+func (r *graphQLResolver) fail(err error, op, message string) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidCursor),
+		errors.Is(err, usecase.ErrInvalidBatch),
+		errors.Is(err, usecase.ErrInvalidInterval),
+		errors.Is(err, usecase.ErrInvalidRange):
+		return err
+	default:
+		r.l.Error(err, "http - v1 - graphql - "+op)
+		return errors.New(message)
+	}
+}
+
+// **Query**
+
+func (r *graphQLResolver) Wallet(args struct{ ID int32 }) *walletResolver {
+	return &walletResolver{r: r, id: int(args.ID)}
+}
+
+func (r *graphQLResolver) Wallets(args struct{ IDs []int32 }) ([]*walletResolver, error) {
+	if len(args.IDs) > _maxLoaderBatch {
+		return nil, usecase.ErrInvalidBatch
+	}
+
+	wallets := make([]*walletResolver, len(args.IDs))
+	for i, id := range args.IDs {
+		wallets[i] = &walletResolver{r: r, id: int(id)}
+	}
+	return wallets, nil
+}
+
+func (r *graphQLResolver) Asset(args struct{ Name string }) *assetResolver {
+	return &assetResolver{r: r, name: args.Name}
+}
+
+// **Wallet**
+
+type walletResolver struct {
+	r  *graphQLResolver
+	id int
+}
+
+func (w *walletResolver) ID() int32 {
+	return int32(w.id) //nolint:gosec // wallet ids are GraphQL Ints
+}
+
+func (w *walletResolver) Balances(ctx context.Context, args struct{ Assets *[]string }) ([]*balanceResolver, error) {
+	assets, err := loadersFrom(ctx).balances.Load(ctx, w.id)()
+	if err != nil {
+		return nil, w.r.fail(err, "Wallet.balances", "Failed to retrieve balances")
+	}
+
+	var only map[string]bool
+	if args.Assets != nil {
+		only = make(map[string]bool, len(*args.Assets))
+		for _, name := range *args.Assets {
+			only[name] = true
+		}
+	}
+
+	balances := make([]*balanceResolver, 0, len(assets))
+	for _, asset := range assets {
+		if only != nil && !only[asset.AssetName] {
+			continue
+		}
+		balances = append(balances, &balanceResolver{r: w.r, asset: asset})
+	}
+	return balances, nil
+}
+
+type transactionsArgs struct {
+	First     int32
+	After     *string
+	Asset     *string
+	Type      *string
+	Ascending bool
+}
+
+// Transactions reads the first page of the plain history through the loader,
+// filtered and later pages one wallet at a time.
+func (w *walletResolver) Transactions(ctx context.Context, args transactionsArgs) (*transactionConnection, error) {
+	var (
+		page *entity.TransactionPage
+		err  error
+	)
+
+	if args.After == nil && args.Asset == nil && args.Type == nil && !args.Ascending {
+		page, err = loadersFrom(ctx).transactions.Load(ctx, transactionsKey{walletID: w.id, limit: int(args.First)})()
+	} else {
+		page, err = w.r.t.GetTransactionHistory(ctx, entity.TransactionFilter{
+			WalletID:  w.id,
+			AssetName: stringArg(args.Asset),
+			Type:      stringArg(args.Type),
+			Ascending: args.Ascending,
+			Limit:     int(args.First),
+		}, stringArg(args.After))
+	}
+	if err != nil {
+		return nil, w.r.fail(err, "Wallet.transactions", "Failed to retrieve transaction history")
+	}
+
+	return &transactionConnection{r: w.r, page: page}, nil
+}
+
+type scheduledTransfersArgs struct {
+	First  int32
+	After  *string
+	Status *string
+}
+
+// ScheduledTransfers reads the first page through the loader, later pages one wallet at a time.
+func (w *walletResolver) ScheduledTransfers(ctx context.Context, args scheduledTransfersArgs) (*scheduledTransferConnection, error) {
+	var (
+		page *entity.ScheduledTransferPage
+		err  error
+	)
+
+	if args.After == nil {
+		page, err = loadersFrom(ctx).scheduled.Load(ctx, scheduledKey{walletID: w.id, status: stringArg(args.Status), limit: int(args.First)})()
+	} else {
+		page, err = w.r.scheduled.GetScheduledTransfers(ctx, entity.ScheduledTransferFilter{
+			WalletID: w.id,
+			Status:   stringArg(args.Status),
+			Limit:    int(args.First),
+		}, *args.After)
+	}
+	if err != nil {
+		return nil, w.r.fail(err, "Wallet.scheduledTransfers", "Failed to retrieve scheduled transfers")
+	}
+
+	return &scheduledTransferConnection{r: w.r, page: page}, nil
+}
+
+type balanceSeriesArgs struct {
+	Asset    string
+	Interval string
+	From     *graphql.Time
+	To       *graphql.Time
+}
+
+func (w *walletResolver) BalanceSeries(ctx context.Context, args balanceSeriesArgs) ([]*balancePointResolver, error) {
+	points, err := w.r.series.GetBalanceSeries(ctx, w.id, args.Asset, args.Interval, timeArg(args.From), timeArg(args.To))
+	if err != nil {
+		return nil, w.r.fail(err, "Wallet.balanceSeries", "Failed to retrieve balance series")
+	}
+
+	series := make([]*balancePointResolver, len(points))
+	for i := range points {
+		series[i] = &balancePointResolver{point: points[i]}
+	}
+	return series, nil
+}
+
+// **Asset**
+
+type assetResolver struct {
+	r    *graphQLResolver
+	name string
+}
+
+func (a *assetResolver) Name() string {
+	return a.name
+}
+
+func (a *assetResolver) Holders(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*assetHolderConnection, error) {
+	page, err := a.r.portfolio.GetAssetHolders(ctx, a.name, int(args.First), stringArg(args.After))
+	if err != nil {
+		return nil, a.r.fail(err, "Asset.holders", "Failed to retrieve asset holders")
+	}
+	return &assetHolderConnection{r: a.r, page: page}, nil
+}
+
+func (a *assetResolver) DailyStats(ctx context.Context, args struct {
+	From *graphql.Time
+	To   *graphql.Time
+}) ([]*dailyStatsResolver, error) {
+	stats, err := a.r.stats.GetDailyStats(ctx, a.name, timeArg(args.From), timeArg(args.To))
+	if err != nil {
+		return nil, a.r.fail(err, "Asset.dailyStats", "Failed to retrieve daily stats")
+	}
+
+	days := make([]*dailyStatsResolver, len(stats))
+	for i := range stats {
+		days[i] = &dailyStatsResolver{stats: stats[i]}
+	}
+	return days, nil
+}
+
+// **Balance**
+
+type balanceResolver struct {
+	r     *graphQLResolver
+	asset entity.WalletAsset
+}
+
+func (b *balanceResolver) Wallet() *walletResolver {
+	return &walletResolver{r: b.r, id: b.asset.WalletID}
+}
+
+func (b *balanceResolver) Asset() *assetResolver {
+	return &assetResolver{r: b.r, name: b.asset.AssetName}
+}
+
+func (b *balanceResolver) Amount() float64 {
+	return b.asset.Amount
+}
+
+func (b *balanceResolver) UpdatedAt() *graphql.Time {
+	if b.asset.UpdatedAt.IsZero() {
+		return nil
+	}
+	return &graphql.Time{Time: b.asset.UpdatedAt}
+}
+
+// **Transaction**
+
+type transactionResolver struct {
+	r   *graphQLResolver
+	txn entity.Transaction
+}
+
+func (t *transactionResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(t.txn.ID, 10))
+}
+
+func (t *transactionResolver) EventID() string {
+	return t.txn.EventID
+}
+
+func (t *transactionResolver) Wallet() *walletResolver {
+	return &walletResolver{r: t.r, id: t.txn.WalletID}
+}
+
+func (t *transactionResolver) Counterparty() *walletResolver {
+	if t.txn.CounterpartyWalletID == nil {
+		return nil
+	}
+	return &walletResolver{r: t.r, id: *t.txn.CounterpartyWalletID}
+}
+
+func (t *transactionResolver) Type() string {
+	return t.txn.Type
+}
+
+func (t *transactionResolver) Asset() *assetResolver {
+	return &assetResolver{r: t.r, name: t.txn.AssetName}
+}
+
+func (t *transactionResolver) Amount() float64 {
+	return t.txn.Amount
+}
+
+func (t *transactionResolver) BalanceAfter() float64 {
+	return t.txn.BalanceAfter
+}
+
+func (t *transactionResolver) EventTime() graphql.Time {
+	return graphql.Time{Time: t.txn.EventTime}
+}
+
+func (t *transactionResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: t.txn.CreatedAt}
+}
+
+// **ScheduledTransfer**
+
+type scheduledTransferResolver struct {
+	r        *graphQLResolver
+	transfer entity.ScheduledTransfer
+}
+
+func (s *scheduledTransferResolver) CommandID() graphql.ID {
+	return graphql.ID(s.transfer.CommandID)
+}
+
+func (s *scheduledTransferResolver) From() *walletResolver {
+	return &walletResolver{r: s.r, id: s.transfer.FromWalletID}
+}
+
+func (s *scheduledTransferResolver) To() *walletResolver {
+	return &walletResolver{r: s.r, id: s.transfer.ToWalletID}
+}
+
+func (s *scheduledTransferResolver) Asset() *assetResolver {
+	return &assetResolver{r: s.r, name: s.transfer.AssetName}
+}
+
+func (s *scheduledTransferResolver) Amount() float64 {
+	return s.transfer.Amount
+}
+
+func (s *scheduledTransferResolver) ExecuteTime() graphql.Time {
+	return graphql.Time{Time: s.transfer.ExecuteTime}
+}
+
+func (s *scheduledTransferResolver) Status() string {
+	return s.transfer.Status
+}
+
+func (s *scheduledTransferResolver) ScheduledAt() graphql.Time {
+	return graphql.Time{Time: s.transfer.ScheduledAt}
+}
+
+func (s *scheduledTransferResolver) ExecutedAt() *graphql.Time {
+	if s.transfer.ExecutedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *s.transfer.ExecutedAt}
+}
+
+// **BalancePoint**
+
+type balancePointResolver struct {
+	point entity.BalancePoint
+}
+
+func (p *balancePointResolver) BucketStart() graphql.Time {
+	return graphql.Time{Time: p.point.BucketStart}
+}
+
+func (p *balancePointResolver) Inflow() float64  { return p.point.Inflow }
+func (p *balancePointResolver) Outflow() float64 { return p.point.Outflow }
+func (p *balancePointResolver) Net() float64     { return p.point.Net }
+func (p *balancePointResolver) Balance() float64 { return p.point.Balance }
+
+// **AssetHolder**
+
+type assetHolderResolver struct {
+	r      *graphQLResolver
+	holder entity.AssetHolder
+}
+
+func (h *assetHolderResolver) Wallet() *walletResolver {
+	return &walletResolver{r: h.r, id: h.holder.WalletID}
+}
+
+func (h *assetHolderResolver) Amount() float64 {
+	return h.holder.Amount
+}
+
+// **DailyStats**
+
+type dailyStatsResolver struct {
+	stats entity.AssetDailyStats
+}
+
+func (d *dailyStatsResolver) Day() graphql.Time {
+	return graphql.Time{Time: d.stats.Day}
+}
+
+func (d *dailyStatsResolver) DepositVolume() float64  { return d.stats.DepositVolume }
+func (d *dailyStatsResolver) DepositCount() int32     { return int32(d.stats.DepositCount) } //nolint:gosec // daily counts
+func (d *dailyStatsResolver) WithdrawVolume() float64 { return d.stats.WithdrawVolume }
+func (d *dailyStatsResolver) WithdrawCount() int32    { return int32(d.stats.WithdrawCount) } //nolint:gosec // daily counts
+func (d *dailyStatsResolver) TransferVolume() float64 { return d.stats.TransferVolume }
+func (d *dailyStatsResolver) TransferCount() int32    { return int32(d.stats.TransferCount) } //nolint:gosec // daily counts
+func (d *dailyStatsResolver) ActiveWallets() int32    { return int32(d.stats.ActiveWallets) } //nolint:gosec // daily counts
+func (d *dailyStatsResolver) NetFlow() float64        { return d.stats.NetFlow }
+func (d *dailyStatsResolver) TotalHoldings() float64  { return d.stats.TotalHoldings }
+
+// **Connections**
+
+type pageInfoResolver struct {
+	endCursor string
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.endCursor != ""
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	if p.endCursor == "" {
+		return nil
+	}
+	return &p.endCursor
+}
+
+type transactionConnection struct {
+	r    *graphQLResolver
+	page *entity.TransactionPage
+}
+
+func (c *transactionConnection) Edges() []*transactionEdge {
+	edges := make([]*transactionEdge, len(c.page.Transactions))
+	for i := range c.page.Transactions {
+		edges[i] = &transactionEdge{node: &transactionResolver{r: c.r, txn: c.page.Transactions[i]}}
+	}
+	return edges
+}
+
+func (c *transactionConnection) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{endCursor: c.page.NextCursor}
+}
+
+type transactionEdge struct {
+	node *transactionResolver
+}
+
+func (e *transactionEdge) Node() *transactionResolver {
+	return e.node
+}
+
+type scheduledTransferConnection struct {
+	r    *graphQLResolver
+	page *entity.ScheduledTransferPage
+}
+
+func (c *scheduledTransferConnection) Edges() []*scheduledTransferEdge {
+	edges := make([]*scheduledTransferEdge, len(c.page.Transfers))
+	for i := range c.page.Transfers {
+		edges[i] = &scheduledTransferEdge{node: &scheduledTransferResolver{r: c.r, transfer: c.page.Transfers[i]}}
+	}
+	return edges
+}
+
+func (c *scheduledTransferConnection) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{endCursor: c.page.NextCursor}
+}
+
+type scheduledTransferEdge struct {
+	node *scheduledTransferResolver
+}
+
+func (e *scheduledTransferEdge) Node() *scheduledTransferResolver {
+	return e.node
+}
+
+type assetHolderConnection struct {
+	r    *graphQLResolver
+	page *entity.HolderPage
+}
+
+func (c *assetHolderConnection) Edges() []*assetHolderEdge {
+	edges := make([]*assetHolderEdge, len(c.page.Holders))
+	for i := range c.page.Holders {
+		edges[i] = &assetHolderEdge{node: &assetHolderResolver{r: c.r, holder: c.page.Holders[i]}}
+	}
+	return edges
+}
+
+func (c *assetHolderConnection) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{endCursor: c.page.NextCursor}
+}
+
+type assetHolderEdge struct {
+	node *assetHolderResolver
+}
+
+func (e *assetHolderEdge) Node() *assetHolderResolver {
+	return e.node
+}
+
+// **Arguments**
+
+func stringArg(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// timeArg reads an optional Time, the zero time selects the default of the use case.
+func timeArg(v *graphql.Time) time.Time {
+	if v == nil {
+		return time.Time{}
+	}
+	return v.Time
+}
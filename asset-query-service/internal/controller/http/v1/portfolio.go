@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+type portfolioRoutes struct {
+	t usecase.PortfolioHandler
+	l logger.Interface
+}
+
+func newPortfolioRoutes(handler *gin.RouterGroup, t usecase.PortfolioHandler, l logger.Interface) {
+	r := &portfolioRoutes{t, l}
+
+	h := handler.Group("/wallets")
+	{
+		h.POST("/:id", r.walletMethod) // Custom methods: balances:batch
+	}
+
+	a := handler.Group("/assets")
+	{
+		a.GET("/:asset/holders", r.GetAssetHolders) // Retrieve holders of an asset
+	}
+}
+
+// **Response Structs**
+
+type BalancesBatchRequest struct {
+	WalletIDs []int    `json:"wallet_ids" binding:"required" example:"1,2,3"`
+	Assets    []string `json:"assets,omitempty" example:"BTC,ETH"` // Optional, all assets when empty
+}
+
+type BalancesBatchResponse struct {
+	Wallets []entity.WalletBalances `json:"wallets"`
+	Stale   bool                    `json:"stale,omitempty"` // The consistency token was not projected in time
+	Status  string                  `json:"status"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+type AssetHoldersResponse struct {
+	AssetName  string               `json:"asset_name"`
+	Holders    []entity.AssetHolder `json:"holders"`
+	Count      int                  `json:"count"`
+	NextCursor string               `json:"next_cursor"`     // Empty on the last page
+	Stale      bool                 `json:"stale,omitempty"` // The consistency token was not projected in time
+	Status     string               `json:"status"`
+	Error      string               `json:"error,omitempty"`
+}
+
+// **Route Handlers**
+
+// walletMethod dispatches the custom methods of the wallets collection. The
+// router cannot tell a literal "balances:batch" segment from a wallet id.
+func (r *portfolioRoutes) walletMethod(c *gin.Context) {
+	switch c.Param("id") {
+	case "balances:batch":
+		r.GetBalancesBatch(c)
+	default:
+		r.handleError(c, http.StatusNotFound, "Not found")
+	}
+}
+
+// @Summary     Retrieve balances of several wallets
+// @Description Get the balances of up to 500 wallets in one request, optionally restricted to some assets. Wallets are returned in the order of the request.
+// @ID          get-balances-batch
+// @Tags        wallets
+// @Accept      json
+// @Produce     json
+// @Param       request body BalancesBatchRequest true "Wallets and assets"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
+// @Success     200 {object} BalancesBatchResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/balances:batch [post]
+func (r *portfolioRoutes) GetBalancesBatch(c *gin.Context) {
+	var request BalancesBatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.handleError(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wallets, err := r.t.GetBalancesBatch(c.Request.Context(), request.WalletIDs, request.Assets)
+	if errors.Is(err, usecase.ErrInvalidBatch) {
+		r.handleError(c, http.StatusBadRequest, "Between 1 and 500 wallet ids are required")
+		return
+	}
+	if err != nil {
+		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve balances")
+		return
+	}
+
+	c.JSON(http.StatusOK, BalancesBatchResponse{Wallets: wallets, Stale: isStale(c), Status: "success"})
+}
+
+// @Summary     Retrieve holders of an asset
+// @Description Get a page of the wallets holding a positive balance of an asset, largest balance first. Pass next_cursor of a page as cursor to get the next one.
+// @ID          get-asset-holders
+// @Tags        assets
+// @Accept      json
+// @Produce     json
+// @Param       asset path string true "Asset Name"
+// @Param       limit query int false "Page size, at most 500" default(50)
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
+// @Success     200 {object} AssetHoldersResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /assets/{asset}/holders [get]
+func (r *portfolioRoutes) GetAssetHolders(c *gin.Context) {
+	limit, err := intQuery(c, "limit")
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pageSize := 0
+	if limit != nil {
+		pageSize = *limit
+	}
+
+	assetName := c.Param("asset")
+	page, err := r.t.GetAssetHolders(c.Request.Context(), assetName, pageSize, c.Query("cursor"))
+	if errors.Is(err, usecase.ErrInvalidCursor) {
+		r.handleError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if err != nil {
+		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve asset holders")
+		return
+	}
+
+	c.JSON(http.StatusOK, AssetHoldersResponse{
+		AssetName:  assetName,
+		Holders:    page.Holders,
+		Count:      len(page.Holders),
+		NextCursor: page.NextCursor,
+		Stale:      isStale(c),
+		Status:     "success",
+	})
+}
+
+// **Helper Function for Error Handling**
+func (r *portfolioRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
@@ -24,7 +24,19 @@ import (
 // @version     1.0
 // @host        localhost:8083
 // @BasePath    /v1
-func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.WalletQueryUseCaseHandler) {
+func NewRouter(
+	handler *gin.Engine,
+	l logger.Interface,
+	t usecase.WalletQueryUseCaseHandler,
+	s usecase.BalanceSeriesHandler,
+	p usecase.PortfolioHandler,
+	st usecase.StatsHandler,
+	sc usecase.ScheduledTransferHandler,
+	cm usecase.CommandHandler,
+	ws usecase.WalletStreamHandler,
+	c usecase.ConsistencyHandler,
+	a usecase.AlertRuleHandler,
+) {
 	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
@@ -32,7 +44,8 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.WalletQueryUse
 	handler.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:8083"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Consistency-Token", "Last-Event-ID"},
+		ExposeHeaders:    []string{"X-Consistency-Stale"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -52,7 +65,19 @@ func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.WalletQueryUse
 	// Routers
 	h := handler.Group("/v1")
 	{
-		newWalletQueryRoutes(h, t, l)
+		// Queries of the read model wait for the command of a consistency token
+		q := h.Group("", consistency(c, l))
+		{
+			newWalletQueryRoutes(q, t, l)
+			newWalletStreamRoutes(q, ws, l)
+			newBalanceSeriesRoutes(q, s, l)
+			newPortfolioRoutes(q, p, l)
+			newGraphQLRoutes(q, &graphQLResolver{t: t, series: s, portfolio: p, stats: st, scheduled: sc, l: l}, l)
+		}
+
+		newStatsRoutes(h, st, l)
+		newCommandRoutes(h, cm, l)
+		newAlertRuleRoutes(h, a, l)
 	}
 
 }
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const _dateLayout = "2006-01-02"
+
+type statsRoutes struct {
+	t usecase.StatsHandler
+	l logger.Interface
+}
+
+func newStatsRoutes(handler *gin.RouterGroup, t usecase.StatsHandler, l logger.Interface) {
+	r := &statsRoutes{t, l}
+
+	h := handler.Group("/stats")
+	{
+		h.GET("/daily", r.GetDailyStats)     // Retrieve daily aggregates per asset
+		h.GET("/summary", r.GetStatsSummary) // Retrieve aggregates per asset over a range of days
+	}
+}
+
+// **Response Structs**
+
+type DailyStatsResponse struct {
+	Days   []entity.AssetDailyStats `json:"days"`
+	Status string                   `json:"status"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+type StatsSummaryResponse struct {
+	Assets []entity.AssetStatsSummary `json:"assets"`
+	Status string                     `json:"status"`
+	Error  string                     `json:"error,omitempty"`
+}
+
+// **Route Handlers**
+
+// @Summary     Retrieve daily statistics
+// @Description Get the deposit, withdraw and transfer volumes, active wallets and total holdings of every asset per UTC day. Defaults to the last 30 days; at most 366 days.
+// @ID          get-daily-stats
+// @Tags        stats
+// @Accept      json
+// @Produce     json
+// @Param       asset query string false "Asset Name"
+// @Param       from query string false "First day, inclusive (YYYY-MM-DD)"
+// @Param       to query string false "Last day, inclusive (YYYY-MM-DD), defaults to today"
+// @Success     200 {object} DailyStatsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /stats/daily [get]
+func (r *statsRoutes) GetDailyStats(c *gin.Context) {
+	from, to, err := dateRangeQuery(c)
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	days, err := r.t.GetDailyStats(c.Request.Context(), c.Query("asset"), from, to)
+	if errors.Is(err, usecase.ErrInvalidRange) {
+		r.handleError(c, http.StatusBadRequest, "Invalid range")
+		return
+	}
+	if err != nil {
+		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve daily statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, DailyStatsResponse{Days: days, Status: "success"})
+}
+
+// @Summary     Retrieve statistics summary
+// @Description Get the volumes and distinct active wallets of every asset over a range of UTC days, with the total holdings at the end of the range. Defaults to the last 30 days; at most 366 days.
+// @ID          get-stats-summary
+// @Tags        stats
+// @Accept      json
+// @Produce     json
+// @Param       asset query string false "Asset Name"
+// @Param       from query string false "First day, inclusive (YYYY-MM-DD)"
+// @Param       to query string false "Last day, inclusive (YYYY-MM-DD), defaults to today"
+// @Success     200 {object} StatsSummaryResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /stats/summary [get]
+func (r *statsRoutes) GetStatsSummary(c *gin.Context) {
+	from, to, err := dateRangeQuery(c)
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assets, err := r.t.GetStatsSummary(c.Request.Context(), c.Query("asset"), from, to)
+	if errors.Is(err, usecase.ErrInvalidRange) {
+		r.handleError(c, http.StatusBadRequest, "Invalid range")
+		return
+	}
+	if err != nil {
+		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve statistics summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, StatsSummaryResponse{Assets: assets, Status: "success"})
+}
+
+// dateRangeQuery reads the from and to days, zero when missing.
+func dateRangeQuery(c *gin.Context) (from, to time.Time, err error) {
+	if from, err = dateQuery(c, "from"); err != nil {
+		return from, to, err
+	}
+	to, err = dateQuery(c, "to")
+	return from, to, err
+}
+
+func dateQuery(c *gin.Context, name string) (time.Time, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(_dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid %s", name)
+	}
+	return t, nil
+}
+
+// **Helper Function for Error Handling**
+func (r *statsRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
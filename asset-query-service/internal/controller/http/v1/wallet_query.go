@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
@@ -30,21 +33,27 @@ func newWalletQueryRoutes(handler *gin.RouterGroup, t usecase.WalletQueryUseCase
 
 type AssetResponse struct {
 	Assets []entity.WalletAsset `json:"assets"`
+	AsOf   *time.Time           `json:"as_of,omitempty"` // Set for point-in-time queries
+	Stale  bool                 `json:"stale,omitempty"` // The consistency token was not projected in time
 	Status string               `json:"status"`
 	Error  string               `json:"error,omitempty"`
 }
 
 type AssetBalanceResponse struct {
-	WalletID  int     `json:"wallet_id"`
-	AssetName string  `json:"asset_name"`
-	Amount    float64 `json:"amount"`
-	Status    string  `json:"status"`
-	Error     string  `json:"error,omitempty"`
+	WalletID  int        `json:"wallet_id"`
+	AssetName string     `json:"asset_name"`
+	Amount    float64    `json:"amount"`
+	AsOf      *time.Time `json:"as_of,omitempty"` // Set for point-in-time queries
+	Stale     bool       `json:"stale,omitempty"` // The consistency token was not projected in time
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
 }
 
 type TransactionHistoryResponse struct {
 	Transactions []entity.Transaction `json:"transactions"`
 	Count        int                  `json:"count"`
+	NextCursor   string               `json:"next_cursor"`     // Empty on the last page
+	Stale        bool                 `json:"stale,omitempty"` // The consistency token was not projected in time
 	Status       string               `json:"status"`
 	Error        string               `json:"error,omitempty"`
 }
@@ -52,13 +61,16 @@ type TransactionHistoryResponse struct {
 // **Route Handlers**
 
 // @Summary     Retrieve all assets of a wallet
-// @Description Get all assets for a specific wallet by its ID
+// @Description Get all assets for a specific wallet by its ID, or their balances at the as_of instant
 // @ID          get-all-assets
 // @Tags        wallets
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Wallet ID"
+// @Param       as_of query string false "Point in time of the balances (RFC 3339)"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
 // @Success     200 {object} AssetResponse
+// @Failure     400 {object} response
 // @Failure     404 {object} response
 // @Failure     500 {object} response
 // @Router      /wallets/{id}/assets [get]
@@ -69,24 +81,38 @@ func (r *walletQueryRoutes) GetAllAssets(c *gin.Context) {
 		return
 	}
 
-	assets, err := r.t.GetAllAssets(c.Request.Context(), id)
+	asOf, err := timeQuery(c, "as_of")
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var assets []entity.WalletAsset
+	if asOf != nil {
+		assets, err = r.t.GetAllAssetsAsOf(c.Request.Context(), id, *asOf)
+	} else {
+		assets, err = r.t.GetAllAssets(c.Request.Context(), id)
+	}
 	if err != nil {
 		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve assets")
 		return
 	}
 
-	c.JSON(http.StatusOK, AssetResponse{Assets: assets, Status: "success"})
+	c.JSON(http.StatusOK, AssetResponse{Assets: assets, AsOf: asOf, Stale: isStale(c), Status: "success"})
 }
 
 // @Summary     Retrieve balance of a specific asset
-// @Description Get the balance of a specific asset in a wallet
+// @Description Get the balance of a specific asset in a wallet, or its balance at the as_of instant
 // @ID          get-asset-balance
 // @Tags        wallets
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Wallet ID"
 // @Param       asset path string true "Asset Name"
+// @Param       as_of query string false "Point in time of the balance (RFC 3339)"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
 // @Success     200 {object} AssetBalanceResponse
+// @Failure     400 {object} response
 // @Failure     404 {object} response
 // @Failure     500 {object} response
 // @Router      /wallets/{id}/assets/{asset} [get]
@@ -97,8 +123,19 @@ func (r *walletQueryRoutes) GetAssetBalance(c *gin.Context) {
 		return
 	}
 
+	asOf, err := timeQuery(c, "as_of")
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	assetName := c.Param("asset")
-	asset, err := r.t.GetAssetBalance(c.Request.Context(), id, assetName)
+	var asset *entity.WalletAsset
+	if asOf != nil {
+		asset, err = r.t.GetAssetBalanceAsOf(c.Request.Context(), id, assetName, *asOf)
+	} else {
+		asset, err = r.t.GetAssetBalance(c.Request.Context(), id, assetName)
+	}
 	if err != nil {
 		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve asset balance")
 		return
@@ -108,19 +145,32 @@ func (r *walletQueryRoutes) GetAssetBalance(c *gin.Context) {
 		WalletID:  id,
 		AssetName: asset.AssetName,
 		Amount:    asset.Amount,
+		AsOf:      asOf,
+		Stale:     isStale(c),
 		Status:    "success",
 	})
 }
 
 // @Summary     Retrieve transaction history
-// @Description Get the transaction history for a wallet
+// @Description Get a page of the transaction history for a wallet. Pass next_cursor of a page as cursor to get the next one.
 // @ID          get-transaction-history
 // @Tags        wallets
 // @Accept      json
 // @Produce     json
 // @Param       id path int true "Wallet ID"
+// @Param       asset query string false "Asset Name"
+// @Param       type query string false "Transaction type" Enums(withdraw, deposit, transfer, balance_correction)
+// @Param       min_amount query number false "Minimum absolute amount"
+// @Param       max_amount query number false "Maximum absolute amount"
+// @Param       from query string false "From time, inclusive (RFC 3339)"
+// @Param       to query string false "To time, exclusive (RFC 3339)"
+// @Param       counterparty query int false "Counterparty wallet ID of transfers"
+// @Param       order query string false "Sort order by event time" Enums(desc, asc) default(desc)
+// @Param       limit query int false "Page size, at most 500" default(50)
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Param       X-Consistency-Token header string false "Consistency token of a command, waits until the command is projected"
 // @Success     200 {object} TransactionHistoryResponse
-// @Failure     404 {object} response
+// @Failure     400 {object} response
 // @Failure     500 {object} response
 // @Router      /wallets/{id}/transactions [get]
 func (r *walletQueryRoutes) GetTransactionHistory(c *gin.Context) {
@@ -130,19 +180,117 @@ func (r *walletQueryRoutes) GetTransactionHistory(c *gin.Context) {
 		return
 	}
 
-	transactions, err := r.t.GetTransactionHistory(c.Request.Context(), id, "")
+	filter, err := parseTransactionFilter(c, id)
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := r.t.GetTransactionHistory(c.Request.Context(), filter, c.Query("cursor"))
+	if errors.Is(err, usecase.ErrInvalidCursor) {
+		r.handleError(c, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
 	if err != nil {
 		r.handleError(c, http.StatusInternalServerError, "Failed to retrieve transaction history")
 		return
 	}
 
 	c.JSON(http.StatusOK, TransactionHistoryResponse{
-		Transactions: transactions,
-		Count:        len(transactions),
+		Transactions: page.Transactions,
+		Count:        len(page.Transactions),
+		NextCursor:   page.NextCursor,
+		Stale:        isStale(c),
 		Status:       "success",
 	})
 }
 
+// parseTransactionFilter reads the history filters from the query string.
+func parseTransactionFilter(c *gin.Context, walletID int) (entity.TransactionFilter, error) {
+	filter := entity.TransactionFilter{
+		WalletID:  walletID,
+		AssetName: c.Query("asset"),
+		Type:      c.Query("type"),
+	}
+
+	switch filter.Type {
+	case "", "withdraw", "deposit", "transfer", "balance_correction":
+	default:
+		return filter, errors.New("Invalid type")
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, errors.New("Invalid order")
+	}
+
+	var err error
+	if filter.MinAmount, err = floatQuery(c, "min_amount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = floatQuery(c, "max_amount"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = timeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = timeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+	if filter.Counterparty, err = intQuery(c, "counterparty"); err != nil {
+		return filter, err
+	}
+
+	limit, err := intQuery(c, "limit")
+	if err != nil {
+		return filter, err
+	}
+	if limit != nil {
+		filter.Limit = *limit
+	}
+
+	return filter, nil
+}
+
+func floatQuery(c *gin.Context, name string) (*float64, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s", name)
+	}
+	return &f, nil
+}
+
+func intQuery(c *gin.Context, name string) (*int, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s", name)
+	}
+	return &i, nil
+}
+
+func timeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s", name)
+	}
+	return &t, nil
+}
+
 // **Helper Function for Error Handling**
 func (r *walletQueryRoutes) handleError(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, gin.H{"status": "error", "message": message})
@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const _wsWriteTimeout = 10 * time.Second
+
+// _upgrader accepts WebSocket connections from the origin of the request host only.
+var _upgrader = websocket.Upgrader{}
+
+type walletStreamRoutes struct {
+	t usecase.WalletStreamHandler
+	l logger.Interface
+}
+
+func newWalletStreamRoutes(handler *gin.RouterGroup, t usecase.WalletStreamHandler, l logger.Interface) {
+	r := &walletStreamRoutes{t, l}
+
+	h := handler.Group("/wallets")
+	{
+		h.GET("/:id/stream", r.StreamWallet) // Stream balance changes and transactions
+	}
+}
+
+// streamEvent is one message of a wallet stream. ID is the projection version and the
+// stream position of the last history row it covers, "<version>-<position>", the
+// Last-Event-ID to resume after it.
+type streamEvent struct {
+	ID    string `json:"id"`
+	Event string `json:"event"` // "snapshot", "transaction" or "balance"
+	Data  any    `json:"data"`
+}
+
+type walletSnapshot struct {
+	WalletID int                  `json:"wallet_id"`
+	Assets   []entity.WalletAsset `json:"assets"`
+}
+
+// streamSender writes the events of a stream to one transport.
+type streamSender interface {
+	send(ev streamEvent) error
+	ping() error
+}
+
+// sendTo adapts a transport to the events of the use case.
+func sendTo(sender streamSender) func(entity.WalletStreamEvent) error {
+	return func(ev entity.WalletStreamEvent) error {
+		out := streamEvent{ID: formatEventID(ev.ID), Event: ev.Event}
+		switch ev.Event {
+		case "heartbeat":
+			return sender.ping()
+		case "snapshot":
+			out.Data = walletSnapshot{WalletID: ev.WalletID, Assets: ev.Assets}
+		case "transaction":
+			out.Data = ev.Transaction
+		case "balance":
+			out.Data = ev.Balance
+		}
+		return sender.send(out)
+	}
+}
+
+// @Summary     Stream balance changes and transactions of a wallet
+// @Description Server-Sent Events, or WebSocket messages of the form {"id","event","data"} when the request is a WebSocket upgrade. A new stream starts with a snapshot event of all balances, then sends a transaction and a balance event for every history row asset-query-processor projects. Event ids are "<projection version>-<position>". Pass the id of the last event received as Last-Event-ID (or last_event_id) to resume without a snapshot. A stream resumed after an id of another projection version, or open while the active version switches, gets a fresh snapshot of the active version.
+// @ID          stream-wallet
+// @Tags        wallets
+// @Produce     text/event-stream
+// @Param       id path int true "Wallet ID"
+// @Param       Last-Event-ID header string false "Id of the last event received, resumes after it"
+// @Param       last_event_id query string false "Id of the last event received, for clients that cannot set headers"
+// @Success     200 {object} streamEvent
+// @Failure     400 {object} response
+// @Router      /wallets/{id}/stream [get]
+func (r *walletStreamRoutes) StreamWallet(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		r.handleError(c, http.StatusBadRequest, "Invalid wallet ID")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	var after *entity.StreamPosition
+	if lastEventID != "" {
+		position, err := parseEventID(lastEventID)
+		if err != nil {
+			r.handleError(c, http.StatusBadRequest, "Invalid Last-Event-ID")
+			return
+		}
+		after = &position
+	}
+
+	if websocket.IsWebSocketUpgrade(c.Request) {
+		r.streamWebSocket(c, id, after)
+		return
+	}
+	r.streamSSE(c, id, after)
+}
+
+func (r *walletStreamRoutes) streamSSE(c *gin.Context, walletID int, after *entity.StreamPosition) {
+	// The write timeout of the server is meant for plain requests
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	if err := r.t.StreamWallet(c.Request.Context(), walletID, after, sendTo(&sseSender{c.Writer})); err != nil {
+		r.l.Error(err, "http - v1 - streamSSE")
+	}
+}
+
+func (r *walletStreamRoutes) streamWebSocket(c *gin.Context, walletID int, after *entity.StreamPosition) {
+	conn, err := _upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has answered the request
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Read until the client goes away, which also answers its pings and close frame
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := r.t.StreamWallet(ctx, walletID, after, sendTo(&wsSender{conn})); err != nil {
+		r.l.Error(err, "http - v1 - streamWebSocket")
+	}
+}
+
+func formatEventID(position entity.StreamPosition) string {
+	return strconv.Itoa(position.Version) + "-" + strconv.FormatInt(position.Seq, 10)
+}
+
+// parseEventID reads an event id of formatEventID. A bare position, the id of
+// streams before ids carried the version, has no version and resumes from a snapshot.
+func parseEventID(id string) (entity.StreamPosition, error) {
+	var position entity.StreamPosition
+
+	version, seq, found := strings.Cut(id, "-")
+	if !found {
+		version, seq = "0", id
+	}
+
+	var err error
+	if position.Version, err = strconv.Atoi(version); err != nil {
+		return position, err
+	}
+	if position.Seq, err = strconv.ParseInt(seq, 10, 64); err != nil {
+		return position, err
+	}
+
+	return position, nil
+}
+
+type sseSender struct {
+	w gin.ResponseWriter
+}
+
+func (s *sseSender) send(ev streamEvent) error {
+	data, err := json.Marshal(ev.Data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Event, data); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return nil
+}
+
+func (s *sseSender) ping() error {
+	if _, err := fmt.Fprint(s.w, ": ping\n\n"); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return nil
+}
+
+type wsSender struct {
+	conn *websocket.Conn
+}
+
+func (s *wsSender) send(ev streamEvent) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(_wsWriteTimeout)); err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(ev)
+}
+
+func (s *wsSender) ping() error {
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(_wsWriteTimeout))
+}
+
+// **Helper Function for Error Handling**
+func (r *walletStreamRoutes) handleError(c *gin.Context, statusCode int, message string) {
+	c.JSON(statusCode, gin.H{"status": "error", "message": message})
+}
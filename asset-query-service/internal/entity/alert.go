@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Alert rule conditions
+const (
+	AlertBalanceBelow  = "balance_below"  // A debit leaves the balance under the threshold
+	AlertMovementAbove = "movement_above" // A single debit or credit exceeds the threshold
+)
+
+// AlertRule makes asset-query-processor append an alert event to the event journal when a
+// movement of a wallet asset meets its condition, at most once per cooldown.
+type AlertRule struct {
+	ID              int64      `json:"id" example:"1"`
+	WalletID        int        `json:"wallet_id" example:"1"`
+	AssetName       string     `json:"asset_name" example:"BTC"`
+	Condition       string     `json:"condition" enums:"balance_below,movement_above" example:"balance_below"`
+	Threshold       float64    `json:"threshold" example:"0.5"`
+	CooldownSeconds int        `json:"cooldown_seconds" example:"3600"` // Least time between two alerts
+	Enabled         bool       `json:"enabled"`
+	Description     string     `json:"description"`
+	LastFiredAt     *time.Time `json:"last_fired_at,omitempty"` // Event time of the last alert
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AlertRuleRequest represents the request payload when creating or replacing an alert rule
+type AlertRuleRequest struct {
+	WalletID        int     `json:"wallet_id" binding:"required" example:"1"`
+	AssetName       string  `json:"asset_name" binding:"required" example:"BTC"`
+	Condition       string  `json:"condition" binding:"required" enums:"balance_below,movement_above" example:"balance_below"`
+	Threshold       float64 `json:"threshold" example:"0.5"`
+	CooldownSeconds int     `json:"cooldown_seconds,omitempty" example:"3600"` // Optional, every triggering movement alerts without it
+	Enabled         *bool   `json:"enabled,omitempty"`                         // Optional, defaults to true
+	Description     string  `json:"description,omitempty" example:"Hot wallet BTC float"`
+}
+
+// AlertRuleFilter selects the alert rules to list.
+type AlertRuleFilter struct {
+	WalletID  int    // Optional
+	AssetName string // Optional
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// BalancePoint is the activity and closing balance of a wallet asset in one UTC hour or day.
+type BalancePoint struct {
+	BucketStart time.Time `json:"bucket_start" db:"bucket_start"` // Start of the hour or day
+	Inflow      float64   `json:"inflow" db:"inflow"`             // Sum of the credits
+	Outflow     float64   `json:"outflow" db:"outflow"`           // Sum of the debits, positive
+	Net         float64   `json:"net" db:"net"`                   // Inflow minus outflow
+	Balance     float64   `json:"balance" db:"closing_balance"`   // Balance at the end of the bucket
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// States of a command in the read model.
+const (
+	CommandApplied   = "applied"   // Its events are projected
+	CommandScheduled = "scheduled" // A transfer waiting for its execute time
+	CommandPending   = "pending"   // Nothing is projected: in flight, rejected or unknown
+)
+
+// CommandStatus is how far the read model has projected a command of asset-management-service.
+type CommandStatus struct {
+	CommandID         string             `json:"command_id"`
+	State             string             `json:"state"`                        // "applied", "scheduled" or "pending"
+	AppliedAt         *time.Time         `json:"applied_at,omitempty"`         // When its first event was projected
+	ScheduledTransfer *ScheduledTransfer `json:"scheduled_transfer,omitempty"` // Set on a deferred transfer
+	Transactions      []Transaction      `json:"transactions"`                 // History rows of its events
+}
@@ -0,0 +1,25 @@
+package entity
+
+// WalletBalances are the balances of the assets of one wallet.
+type WalletBalances struct {
+	WalletID int           `json:"wallet_id"`
+	Assets   []WalletAsset `json:"assets"`
+}
+
+// AssetHolder is a wallet holding a positive balance of an asset.
+type AssetHolder struct {
+	WalletID int     `json:"wallet_id" db:"wallet_id"`
+	Amount   float64 `json:"amount" db:"amount"`
+}
+
+// HolderCursor is the ordering key of the last holder of a page.
+type HolderCursor struct {
+	Amount   float64 `json:"a"`
+	WalletID int     `json:"w"`
+}
+
+// HolderPage is a page of the holders of an asset, largest balance first.
+type HolderPage struct {
+	Holders    []AssetHolder
+	NextCursor string // Empty on the last page
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// ScheduledTransfer is a transfer deferred to its execute time.
+type ScheduledTransfer struct {
+	CommandID    string     `json:"command_id" db:"command_id"`
+	FromWalletID int        `json:"from_wallet_id" db:"from_wallet_id"`
+	ToWalletID   int        `json:"to_wallet_id" db:"to_wallet_id"`
+	AssetName    string     `json:"asset_name" db:"asset_name"`
+	Amount       float64    `json:"amount" db:"amount"`
+	ExecuteTime  time.Time  `json:"execute_time" db:"execute_time"`
+	Status       string     `json:"status" db:"status"` // "scheduled" or "executed"
+	ScheduledAt  time.Time  `json:"scheduled_at" db:"scheduled_at"`
+	ExecutedAt   *time.Time `json:"executed_at,omitempty" db:"executed_at"`
+}
+
+// ScheduledTransferFilter selects a page of the scheduled transfers sent or received by a wallet.
+type ScheduledTransferFilter struct {
+	WalletID int
+	Status   string // Optional
+	Limit    int
+	After    *ScheduledTransferCursor // Optional, position of the last row of the previous page
+}
+
+// ScheduledTransferCursor is the ordering key of the last scheduled transfer of a page.
+type ScheduledTransferCursor struct {
+	ExecuteTime time.Time `json:"t"`
+	CommandID   string    `json:"c"`
+}
+
+// ScheduledTransferPage is a page of scheduled transfers, earliest execute time first.
+type ScheduledTransferPage struct {
+	Transfers  []ScheduledTransfer
+	NextCursor string // Empty on the last page
+}
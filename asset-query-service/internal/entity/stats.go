@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// AssetDailyStats are the platform-wide aggregates of an asset in one UTC day.
+type AssetDailyStats struct {
+	Day            time.Time `json:"day" db:"day"`
+	AssetName      string    `json:"asset_name" db:"asset_name"`
+	DepositVolume  float64   `json:"deposit_volume" db:"deposit_volume"`
+	DepositCount   int       `json:"deposit_count" db:"deposit_count"`
+	WithdrawVolume float64   `json:"withdraw_volume" db:"withdraw_volume"` // Positive
+	WithdrawCount  int       `json:"withdraw_count" db:"withdraw_count"`
+	TransferVolume float64   `json:"transfer_volume" db:"transfer_volume"`
+	TransferCount  int       `json:"transfer_count" db:"transfer_count"`
+	ActiveWallets  int       `json:"active_wallets" db:"active_wallets"` // Wallets with a deposit, withdraw or transfer
+	NetFlow        float64   `json:"net_flow" db:"net_flow"`             // Change of the total holdings
+	TotalHoldings  float64   `json:"total_holdings" db:"total_holdings"` // Sum of all balances at the end of the day
+}
+
+// AssetStatsSummary are the aggregates of an asset over a range of days.
+type AssetStatsSummary struct {
+	AssetName      string  `json:"asset_name"`
+	DepositVolume  float64 `json:"deposit_volume"`
+	DepositCount   int     `json:"deposit_count"`
+	WithdrawVolume float64 `json:"withdraw_volume"`
+	WithdrawCount  int     `json:"withdraw_count"`
+	TransferVolume float64 `json:"transfer_volume"`
+	TransferCount  int     `json:"transfer_count"`
+	ActiveWallets  int     `json:"active_wallets"` // Distinct wallets active on any day of the range
+	NetFlow        float64 `json:"net_flow"`
+	TotalHoldings  float64 `json:"total_holdings"` // Sum of all balances at the end of the range
+}
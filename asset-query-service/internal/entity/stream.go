@@ -0,0 +1,20 @@
+package entity
+
+// StreamPosition is the place of a history row in the stream of its wallet. Stream
+// positions are numbered per projection version, so a position only means something
+// together with its version.
+type StreamPosition struct {
+	Version int
+	Seq     int64
+}
+
+// WalletStreamEvent is one event of a wallet stream. ID is the stream position of the
+// last history row it covers, a stream resumed after ID continues with the next event.
+type WalletStreamEvent struct {
+	ID          StreamPosition
+	WalletID    int
+	Event       string        // "snapshot", "transaction", "balance" or "heartbeat"
+	Assets      []WalletAsset // All balances of the wallet, set on a snapshot
+	Transaction *Transaction  // Set on a transaction
+	Balance     *WalletAsset  // Balance after the transaction, set on a balance
+}
@@ -2,13 +2,44 @@ package entity
 
 import "time"
 
-// Transaction represents a wallet transaction (e.g., withdraw, deposit, transfer).
+// Transaction represents the effect of a wallet event (withdraw, deposit, transfer) on one wallet asset.
 type Transaction struct {
-	ID             int       `json:"id" db:"transaction_id"`                           // Unique transaction ID
-	WalletID       int       `json:"wallet_id" db:"wallet_id"`                         // Wallet associated with the transaction
-	TargetWalletID *int      `json:"target_wallet_id,omitempty" db:"target_wallet_id"` // For transfer transactions
-	Type           string    `json:"type" db:"type"`                                   // "withdraw", "deposit", or "transfer"
-	AssetName      string    `json:"asset_name" db:"asset_name"`
-	Amount         float64   `json:"amount" db:"amount"`         // Transaction amount
-	CreatedAt      time.Time `json:"created_at" db:"created_at"` // Timestamp when the transaction occurred
+	ID                   int64     `json:"id" db:"transaction_id"`                                       // Unique transaction ID
+	EventID              string    `json:"event_id" db:"event_id"`                                       // Event the transaction was projected from
+	WalletID             int       `json:"wallet_id" db:"wallet_id"`                                     // Wallet associated with the transaction
+	CounterpartyWalletID *int      `json:"counterparty_wallet_id,omitempty" db:"counterparty_wallet_id"` // The other wallet of a transfer
+	Type                 string    `json:"type" db:"type"`                                               // "withdraw", "deposit", "transfer" or "balance_correction"
+	AssetName            string    `json:"asset_name" db:"asset_name"`
+	Amount               float64   `json:"amount" db:"amount"`               // Signed, negative for debits
+	BalanceAfter         float64   `json:"balance_after" db:"balance_after"` // Balance of the wallet asset after the transaction
+	EventTime            time.Time `json:"event_time" db:"event_time"`       // Timestamp when the transaction occurred
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`       // Timestamp when the transaction was projected
+	StreamSeq            int64     `json:"-" db:"stream_seq"`                // Position in the stream of the wallet, set on streamed rows
+}
+
+// TransactionFilter selects a page of a wallet's transaction history.
+type TransactionFilter struct {
+	WalletID     int
+	AssetName    string     // Optional
+	Type         string     // Optional
+	MinAmount    *float64   // Optional, compared with the absolute amount
+	MaxAmount    *float64   // Optional, compared with the absolute amount
+	From         *time.Time // Optional, inclusive
+	To           *time.Time // Optional, exclusive
+	Counterparty *int       // Optional
+	Ascending    bool       // Oldest first, newest first by default
+	Limit        int
+	After        *TransactionCursor // Optional, position of the last row of the previous page
+}
+
+// TransactionCursor is the ordering key of the last transaction of a page.
+type TransactionCursor struct {
+	EventTime time.Time `json:"t"`
+	ID        int64     `json:"id"`
+}
+
+// TransactionPage is a page of a transaction history.
+type TransactionPage struct {
+	Transactions []Transaction
+	NextCursor   string // Empty on the last page
 }
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const (
+	_maxAlertCooldown    = 30 * 24 * 60 * 60 // Seconds
+	_maxAlertDescription = 255
+	_maxAssetNameLength  = 50
+)
+
+var (
+	// ErrAlertRuleNotFound is returned for an alert rule that does not exist.
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	// ErrInvalidAlertRule is returned for an alert rule that cannot be stored.
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+)
+
+// AlertRuleUseCase manages the alert rules asset-query-processor evaluates after every balance change.
+type AlertRuleUseCase struct {
+	repo AlertRuleRepositoryHandler
+	log  logger.Interface
+}
+
+// NewAlertRuleUseCase creates a new instance of AlertRuleUseCase
+func NewAlertRuleUseCase(r AlertRuleRepositoryHandler, log logger.Interface) *AlertRuleUseCase {
+	return &AlertRuleUseCase{repo: r, log: log}
+}
+
+// CreateAlertRule validates and stores a new alert rule
+func (uc *AlertRuleUseCase) CreateAlertRule(ctx context.Context, req entity.AlertRuleRequest) (*entity.AlertRule, error) {
+	if err := validateAlertRule(req); err != nil {
+		return nil, fmt.Errorf("AlertRuleUseCase - CreateAlertRule: %w", err)
+	}
+
+	rule, err := uc.repo.CreateAlertRule(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleUseCase - CreateAlertRule - uc.repo.CreateAlertRule: %w", err)
+	}
+	return rule, nil
+}
+
+// GetAlertRule retrieves an alert rule by its id
+func (uc *AlertRuleUseCase) GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	rule, err := uc.repo.GetAlertRule(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleUseCase - GetAlertRule - uc.repo.GetAlertRule: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+// ListAlertRules retrieves the alert rules matching a filter, oldest first
+func (uc *AlertRuleUseCase) ListAlertRules(ctx context.Context, filter entity.AlertRuleFilter) ([]entity.AlertRule, error) {
+	rules, err := uc.repo.ListAlertRules(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleUseCase - ListAlertRules - uc.repo.ListAlertRules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateAlertRule validates and replaces an alert rule. The rule keeps the time it last
+// fired, so its cooldown carries over.
+func (uc *AlertRuleUseCase) UpdateAlertRule(ctx context.Context, id int64, req entity.AlertRuleRequest) (*entity.AlertRule, error) {
+	if err := validateAlertRule(req); err != nil {
+		return nil, fmt.Errorf("AlertRuleUseCase - UpdateAlertRule: %w", err)
+	}
+
+	rule, err := uc.repo.UpdateAlertRule(ctx, id, req)
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleUseCase - UpdateAlertRule - uc.repo.UpdateAlertRule: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule. Alerts it fired before are still published.
+func (uc *AlertRuleUseCase) DeleteAlertRule(ctx context.Context, id int64) error {
+	deleted, err := uc.repo.DeleteAlertRule(ctx, id)
+	if err != nil {
+		return fmt.Errorf("AlertRuleUseCase - DeleteAlertRule - uc.repo.DeleteAlertRule: %w", err)
+	}
+	if !deleted {
+		return ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+func validateAlertRule(req entity.AlertRuleRequest) error {
+	switch {
+	case req.WalletID <= 0:
+		return fmt.Errorf("%w: wallet_id must be positive", ErrInvalidAlertRule)
+	case req.AssetName == "" || len(req.AssetName) > _maxAssetNameLength:
+		return fmt.Errorf("%w: asset_name must have 1 to %d characters", ErrInvalidAlertRule, _maxAssetNameLength)
+	case req.Condition != entity.AlertBalanceBelow && req.Condition != entity.AlertMovementAbove:
+		return fmt.Errorf("%w: condition must be %q or %q", ErrInvalidAlertRule, entity.AlertBalanceBelow, entity.AlertMovementAbove)
+	case req.Threshold <= 0:
+		return fmt.Errorf("%w: threshold must be positive", ErrInvalidAlertRule)
+	case req.CooldownSeconds < 0 || req.CooldownSeconds > _maxAlertCooldown:
+		return fmt.Errorf("%w: cooldown_seconds must be between 0 and %d", ErrInvalidAlertRule, _maxAlertCooldown)
+	case len(req.Description) > _maxAlertDescription:
+		return fmt.Errorf("%w: description must have at most %d characters", ErrInvalidAlertRule, _maxAlertDescription)
+	}
+	return nil
+}
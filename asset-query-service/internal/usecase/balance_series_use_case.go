@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+)
+
+const _maxSeriesPoints = 1000
+
+var (
+	// ErrInvalidInterval is returned for a series interval other than hour or day.
+	ErrInvalidInterval = errors.New("invalid interval")
+
+	// ErrInvalidRange is returned for a series range that is empty or has too many points.
+	ErrInvalidRange = errors.New("invalid range")
+)
+
+// _seriesIntervals are the bucket sizes and the default range of a series.
+var _seriesIntervals = map[string]struct {
+	step         time.Duration
+	defaultRange time.Duration
+}{
+	"hour": {step: time.Hour, defaultRange: 48 * time.Hour},
+	"day":  {step: 24 * time.Hour, defaultRange: 30 * 24 * time.Hour},
+}
+
+// BalanceSeriesUseCase reads the hourly and daily balance series of wallet assets.
+type BalanceSeriesUseCase struct {
+	repo BalanceSeriesRepositoryHandler
+}
+
+// NewBalanceSeriesUseCase creates a new instance of BalanceSeriesUseCase
+func NewBalanceSeriesUseCase(r BalanceSeriesRepositoryHandler) *BalanceSeriesUseCase {
+	return &BalanceSeriesUseCase{repo: r}
+}
+
+// GetBalanceSeries retrieves one point per UTC hour or day in [from, to), up to now.
+// Buckets without activity carry the previous closing balance forward. A zero to means
+// now and a zero from means the default range of the interval before to.
+func (uc *BalanceSeriesUseCase) GetBalanceSeries(ctx context.Context, walletID int, assetName, interval string, from, to time.Time) ([]entity.BalancePoint, error) {
+	spec, ok := _seriesIntervals[interval]
+	if !ok {
+		return nil, ErrInvalidInterval
+	}
+
+	now := time.Now().UTC()
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-spec.defaultRange)
+	}
+
+	from = from.UTC().Truncate(spec.step)
+	if !to.After(from) || to.Sub(from) > _maxSeriesPoints*spec.step {
+		return nil, ErrInvalidRange
+	}
+
+	buckets, err := uc.repo.GetBalanceSeries(ctx, walletID, assetName, interval, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("BalanceSeriesUseCase - GetBalanceSeries - uc.repo.GetBalanceSeries: %w", err)
+	}
+
+	balance, err := uc.repo.GetBalanceBefore(ctx, walletID, assetName, interval, from)
+	if err != nil {
+		return nil, fmt.Errorf("BalanceSeriesUseCase - GetBalanceSeries - uc.repo.GetBalanceBefore: %w", err)
+	}
+
+	points := make([]entity.BalancePoint, 0, int(to.Sub(from)/spec.step)+1)
+	for start := from; start.Before(to); start = start.Add(spec.step) {
+		if len(buckets) > 0 && buckets[0].BucketStart.Equal(start) {
+			balance = buckets[0].Balance
+			points = append(points, buckets[0])
+			buckets = buckets[1:]
+			continue
+		}
+		points = append(points, entity.BalancePoint{BucketStart: start, Balance: balance})
+	}
+
+	return points, nil
+}
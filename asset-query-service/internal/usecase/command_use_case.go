@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+)
+
+// CommandUseCase reports how far the read model has projected a command.
+type CommandUseCase struct {
+	repo CommandRepositoryHandler
+}
+
+// NewCommandUseCase creates a new instance of CommandUseCase
+func NewCommandUseCase(r CommandRepositoryHandler) *CommandUseCase {
+	return &CommandUseCase{repo: r}
+}
+
+// GetCommandStatus retrieves how far the read model has projected a command. The read model
+// only sees events, a command that is still queued, was rejected or is unknown is pending.
+func (uc *CommandUseCase) GetCommandStatus(ctx context.Context, commandID string) (*entity.CommandStatus, error) {
+	command, err := uc.repo.GetCommand(ctx, commandID)
+	if err != nil {
+		return nil, fmt.Errorf("CommandUseCase - GetCommandStatus - uc.repo.GetCommand: %w", err)
+	}
+
+	// The transfer_scheduled event of a deferred transfer is applied before the transfer runs
+	switch {
+	case command.ScheduledTransfer != nil && command.ScheduledTransfer.Status == "scheduled":
+		command.State = entity.CommandScheduled
+	case command.AppliedAt != nil:
+		command.State = entity.CommandApplied
+	default:
+		command.State = entity.CommandPending
+	}
+
+	return command, nil
+}
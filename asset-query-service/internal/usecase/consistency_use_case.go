@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const _awaitPollInterval = 50 * time.Millisecond
+
+// ConsistencyUseCase lets a query wait for the command a client has just sent.
+type ConsistencyUseCase struct {
+	repo ConsistencyRepositoryHandler
+	wait time.Duration
+}
+
+// NewConsistencyUseCase creates a new instance of ConsistencyUseCase, wait bounds the
+// wait for a consistency token
+func NewConsistencyUseCase(r ConsistencyRepositoryHandler, wait time.Duration) *ConsistencyUseCase {
+	return &ConsistencyUseCase{repo: r, wait: wait}
+}
+
+// AwaitCommand polls until the command of a consistency token is projected. The token is
+// the command id returned by asset-management-service. It reports false when the wait ran out.
+func (uc *ConsistencyUseCase) AwaitCommand(ctx context.Context, token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.wait)
+	defer cancel()
+
+	ticker := time.NewTicker(_awaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := uc.repo.IsCommandApplied(ctx, token)
+		if ctx.Err() != nil {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("ConsistencyUseCase - AwaitCommand - uc.repo.IsCommandApplied: %w", err)
+		}
+		if applied {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
 )
@@ -18,8 +19,17 @@ type (
 		// Retrieves the balance of a specific asset in a wallet
 		GetAssetBalance(ctx context.Context, walletID int, assetName string) (*entity.WalletAsset, error)
 
-		// Retrieves the transaction history for a specific wallet and asset
-		GetTransactionHistory(ctx context.Context, walletID int, assetName string) ([]entity.Transaction, error)
+		// Retrieves all assets and their balances for a specific wallet ID as of a point in time
+		GetAllAssetsAsOf(ctx context.Context, walletID int, asOf time.Time) ([]entity.WalletAsset, error)
+
+		// Retrieves the balance of a specific asset in a wallet as of a point in time
+		GetAssetBalanceAsOf(ctx context.Context, walletID int, assetName string, asOf time.Time) (*entity.WalletAsset, error)
+
+		// Retrieves a page of the transaction history of a wallet, cursor is the next_cursor of the previous page
+		GetTransactionHistory(ctx context.Context, filter entity.TransactionFilter, cursor string) (*entity.TransactionPage, error)
+
+		// Retrieves the first page of the transaction history of several wallets, keyed by wallet
+		GetTransactionHistoryBatch(ctx context.Context, walletIDs []int, limit int) (map[int]*entity.TransactionPage, error)
 	}
 
 	// WalletQueryRepositoryHandler defines the methods for querying wallet data.
@@ -30,13 +40,166 @@ type (
 		// GetWalletAsset retrieves the amount of a specific asset for a wallet.
 		GetWalletAsset(ctx context.Context, walletID int, assetName string) (float64, error)
 
+		// GetAssetsByWalletIDAsOf retrieves the balances of a wallet's assets as of a point in time.
+		GetAssetsByWalletIDAsOf(ctx context.Context, walletID int, asOf time.Time) ([]entity.WalletAsset, error)
+
+		// GetWalletAssetAsOf retrieves the amount of a specific asset for a wallet as of a point in time.
+		GetWalletAssetAsOf(ctx context.Context, walletID int, assetName string, asOf time.Time) (float64, error)
+
 		// UpdateWalletAsset updates the amount of a specific asset for a wallet.
 		UpdateWalletAsset(ctx context.Context, walletID int, assetName string, amount float64) error
 
 		// InsertOrUpdateWalletAsset inserts or updates a wallet asset entry.
 		InsertOrUpdateWalletAsset(ctx context.Context, walletID int, assetName string, amount float64) error
 
-		// GetTransactionHistory retrieves transaction history for a wallet.
-		GetTransactionHistory(ctx context.Context, walletID int) ([]entity.Transaction, error)
+		// GetTransactionHistory retrieves a page of transaction history for a wallet.
+		GetTransactionHistory(ctx context.Context, filter entity.TransactionFilter) ([]entity.Transaction, error)
+
+		// GetTransactionHistoryBatch retrieves the last limit transactions of several wallets, ordered by wallet and newest first.
+		GetTransactionHistoryBatch(ctx context.Context, walletIDs []int, limit int) ([]entity.Transaction, error)
+	}
+
+	// BalanceSeriesHandler defines the interface for the balance series of wallet assets
+	BalanceSeriesHandler interface {
+		// Retrieves the balance series of a wallet asset in [from, to), one point per hour or day
+		GetBalanceSeries(ctx context.Context, walletID int, assetName, interval string, from, to time.Time) ([]entity.BalancePoint, error)
+	}
+
+	// BalanceSeriesRepositoryHandler defines the methods for reading the balance series projection.
+	BalanceSeriesRepositoryHandler interface {
+		// GetBalanceSeries retrieves the buckets of a wallet asset with activity in [from, to).
+		GetBalanceSeries(ctx context.Context, walletID int, assetName, interval string, from, to time.Time) ([]entity.BalancePoint, error)
+
+		// GetBalanceBefore retrieves the closing balance of the last bucket before a point in time.
+		GetBalanceBefore(ctx context.Context, walletID int, assetName, interval string, before time.Time) (float64, error)
+	}
+
+	// PortfolioHandler defines the interface for the balances across wallets
+	PortfolioHandler interface {
+		// Retrieves the balances of several wallets, restricted to assetNames when it is not empty
+		GetBalancesBatch(ctx context.Context, walletIDs []int, assetNames []string) ([]entity.WalletBalances, error)
+
+		// Retrieves a page of the holders of an asset, largest balance first
+		GetAssetHolders(ctx context.Context, assetName string, limit int, cursor string) (*entity.HolderPage, error)
+	}
+
+	// PortfolioRepositoryHandler defines the methods for reading the balances across wallets.
+	PortfolioRepositoryHandler interface {
+		// GetAssetsByWalletIDs retrieves the balances of several wallets, restricted to assetNames when it is not empty.
+		GetAssetsByWalletIDs(ctx context.Context, walletIDs []int, assetNames []string) ([]entity.WalletAsset, error)
+
+		// GetAssetHolders retrieves a page of the wallets holding a positive balance of an asset.
+		GetAssetHolders(ctx context.Context, assetName string, after *entity.HolderCursor, limit int) ([]entity.AssetHolder, error)
+	}
+
+	// StatsHandler defines the interface for the platform-wide analytics
+	StatsHandler interface {
+		// Retrieves the platform-wide aggregates per asset of the UTC days in [from, to]
+		GetDailyStats(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetDailyStats, error)
+
+		// Retrieves the platform-wide aggregates per asset over the UTC days in [from, to]
+		GetStatsSummary(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetStatsSummary, error)
+	}
+
+	// StatsRepositoryHandler defines the methods for reading the daily analytics projection.
+	StatsRepositoryHandler interface {
+		// GetDailyStats retrieves the daily aggregates of the days in [from, to], restricted to assetName when it is not empty.
+		GetDailyStats(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetDailyStats, error)
+
+		// GetStatsSummary retrieves the aggregates of the days in [from, to] per asset, restricted to assetName when it is not empty.
+		GetStatsSummary(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetStatsSummary, error)
+	}
+
+	// ScheduledTransferHandler defines the interface for the scheduled transfers of wallets
+	ScheduledTransferHandler interface {
+		// Retrieves a page of the scheduled transfers sent or received by a wallet, earliest first
+		GetScheduledTransfers(ctx context.Context, filter entity.ScheduledTransferFilter, cursor string) (*entity.ScheduledTransferPage, error)
+
+		// Retrieves the first page of the scheduled transfers of several wallets, keyed by wallet
+		GetScheduledTransfersBatch(ctx context.Context, walletIDs []int, status string, limit int) (map[int]*entity.ScheduledTransferPage, error)
+	}
+
+	// ScheduledTransferRepositoryHandler defines the methods for reading the scheduled transfers projection.
+	ScheduledTransferRepositoryHandler interface {
+		// GetScheduledTransfers retrieves a page of the scheduled transfers sent or received by a wallet.
+		GetScheduledTransfers(ctx context.Context, filter entity.ScheduledTransferFilter) ([]entity.ScheduledTransfer, error)
+
+		// GetScheduledTransfersBatch retrieves the first limit scheduled transfers of several wallets, keyed by wallet.
+		GetScheduledTransfersBatch(ctx context.Context, walletIDs []int, status string, limit int) (map[int][]entity.ScheduledTransfer, error)
+	}
+
+	// CommandHandler defines the interface for the projection status of commands
+	CommandHandler interface {
+		// Retrieves how far the read model has projected a command
+		GetCommandStatus(ctx context.Context, commandID string) (*entity.CommandStatus, error)
+	}
+
+	// CommandRepositoryHandler defines the methods for reading what the read model holds of a command.
+	CommandRepositoryHandler interface {
+		// GetCommand retrieves the applied events, scheduled transfer and history rows of a command.
+		GetCommand(ctx context.Context, commandID string) (*entity.CommandStatus, error)
+	}
+
+	// WalletStreamHandler defines the interface for the live streams of wallets
+	WalletStreamHandler interface {
+		// Streams the balance changes and transactions of a wallet, after the history row after
+		// or from a snapshot, until ctx is done or send fails
+		StreamWallet(ctx context.Context, walletID int, after *entity.StreamPosition, send func(entity.WalletStreamEvent) error) error
+	}
+
+	// WalletStreamRepositoryHandler defines the methods for reading the history rows a wallet stream sends.
+	WalletStreamRepositoryHandler interface {
+		// ListenTransactions calls notify with the wallet id of every history row projected, until ctx is done.
+		ListenTransactions(ctx context.Context, notify func(walletID int)) error
+
+		// Version returns the active projection version.
+		Version() int
+
+		// GetSnapshot retrieves the balances of a wallet and the stream position of its last history row, read together.
+		GetSnapshot(ctx context.Context, walletID int) (entity.StreamPosition, []entity.WalletAsset, error)
+
+		// GetTransactionsAfter retrieves the history rows of a wallet after the stream position after, in commit order,
+		// from the tables of the version of after.
+		GetTransactionsAfter(ctx context.Context, walletID int, after entity.StreamPosition, limit int) ([]entity.Transaction, error)
+	}
+
+	// ConsistencyHandler defines the interface for read-your-writes
+	ConsistencyHandler interface {
+		// Waits until the command of a consistency token is projected, reports false when
+		// it was not projected within the consistency wait
+		AwaitCommand(ctx context.Context, token string) (bool, error)
+	}
+
+	// ConsistencyRepositoryHandler defines the methods for following projected commands.
+	ConsistencyRepositoryHandler interface {
+		// IsCommandApplied reports whether the events of a command have been projected.
+		IsCommandApplied(ctx context.Context, commandID string) (bool, error)
+	}
+
+	// AlertRuleHandler defines the interface for managing alert rules
+	AlertRuleHandler interface {
+		CreateAlertRule(ctx context.Context, req entity.AlertRuleRequest) (*entity.AlertRule, error)           // Create an alert rule
+		GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error)                                 // Retrieve an alert rule
+		ListAlertRules(ctx context.Context, filter entity.AlertRuleFilter) ([]entity.AlertRule, error)         // Retrieve the alert rules matching a filter
+		UpdateAlertRule(ctx context.Context, id int64, req entity.AlertRuleRequest) (*entity.AlertRule, error) // Replace an alert rule
+		DeleteAlertRule(ctx context.Context, id int64) error                                                   // Delete an alert rule
+	}
+
+	// AlertRuleRepositoryHandler defines the methods for storing alert rules.
+	AlertRuleRepositoryHandler interface {
+		// CreateAlertRule stores a new alert rule.
+		CreateAlertRule(ctx context.Context, req entity.AlertRuleRequest) (*entity.AlertRule, error)
+
+		// GetAlertRule retrieves an alert rule, nil when it does not exist.
+		GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error)
+
+		// ListAlertRules retrieves the alert rules matching a filter, ordered by id.
+		ListAlertRules(ctx context.Context, filter entity.AlertRuleFilter) ([]entity.AlertRule, error)
+
+		// UpdateAlertRule replaces an alert rule, nil when it does not exist.
+		UpdateAlertRule(ctx context.Context, id int64, req entity.AlertRuleRequest) (*entity.AlertRule, error)
+
+		// DeleteAlertRule deletes an alert rule and reports whether it existed.
+		DeleteAlertRule(ctx context.Context, id int64) (bool, error)
 	}
 )
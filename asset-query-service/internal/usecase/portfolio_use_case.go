@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+)
+
+const _maxBatchWallets = 500
+
+// ErrInvalidBatch is returned for a batch without wallets or with too many of them.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// PortfolioUseCase reads the balances of several wallets and the holders of an asset.
+type PortfolioUseCase struct {
+	repo PortfolioRepositoryHandler
+}
+
+// NewPortfolioUseCase creates a new instance of PortfolioUseCase
+func NewPortfolioUseCase(r PortfolioRepositoryHandler) *PortfolioUseCase {
+	return &PortfolioUseCase{repo: r}
+}
+
+// GetBalancesBatch retrieves the balances of several wallets, restricted to assetNames when
+// it is not empty. Wallets are returned once each in the order of the request, a wallet
+// without balances has no assets.
+func (uc *PortfolioUseCase) GetBalancesBatch(ctx context.Context, walletIDs []int, assetNames []string) ([]entity.WalletBalances, error) {
+	if len(walletIDs) == 0 || len(walletIDs) > _maxBatchWallets {
+		return nil, ErrInvalidBatch
+	}
+
+	balances := make([]entity.WalletBalances, 0, len(walletIDs))
+	index := make(map[int]int, len(walletIDs))
+	for _, id := range walletIDs {
+		if _, ok := index[id]; ok {
+			continue
+		}
+		index[id] = len(balances)
+		balances = append(balances, entity.WalletBalances{WalletID: id, Assets: make([]entity.WalletAsset, 0)})
+	}
+
+	ids := make([]int, len(balances))
+	for i, b := range balances {
+		ids[i] = b.WalletID
+	}
+
+	assets, err := uc.repo.GetAssetsByWalletIDs(ctx, ids, assetNames)
+	if err != nil {
+		return nil, fmt.Errorf("PortfolioUseCase - GetBalancesBatch - uc.repo.GetAssetsByWalletIDs: %w", err)
+	}
+
+	for _, asset := range assets {
+		i := index[asset.WalletID]
+		balances[i].Assets = append(balances[i].Assets, asset)
+	}
+
+	return balances, nil
+}
+
+// GetAssetHolders retrieves a page of the holders of an asset, largest balance first
+func (uc *PortfolioUseCase) GetAssetHolders(ctx context.Context, assetName string, limit int, cursor string) (*entity.HolderPage, error) {
+	limit = historyLimit(limit)
+
+	var after *entity.HolderCursor
+	if cursor != "" {
+		after = &entity.HolderCursor{}
+		if err := decodeCursor(cursor, assetName, after); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	// One extra row tells whether there is a next page
+	holders, err := uc.repo.GetAssetHolders(ctx, assetName, after, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("PortfolioUseCase - GetAssetHolders - uc.repo.GetAssetHolders: %w", err)
+	}
+
+	page := &entity.HolderPage{Holders: holders}
+	if len(holders) > limit {
+		page.Holders = holders[:limit]
+		last := page.Holders[limit-1]
+		page.NextCursor = encodeCursor(assetName, entity.HolderCursor{Amount: last.Amount, WalletID: last.WalletID})
+	}
+
+	return page, nil
+}
@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const _alertRuleColumns = "id, wallet_id, asset_name, condition, threshold, cooldown_seconds, enabled, description, last_fired_at, created_at, updated_at"
+
+// AlertRuleRepo stores the alert rules asset-query-processor evaluates. The rules are not
+// part of a projection version, every version reads the same table.
+type AlertRuleRepo struct {
+	*postgres.Postgres
+}
+
+// NewAlertRuleRepo creates a new instance of AlertRuleRepo
+func NewAlertRuleRepo(pg *postgres.Postgres) *AlertRuleRepo {
+	return &AlertRuleRepo{pg}
+}
+
+// CreateAlertRule stores a new alert rule
+func (r *AlertRuleRepo) CreateAlertRule(ctx context.Context, req entity.AlertRuleRequest) (*entity.AlertRule, error) {
+	sql, args, err := r.Builder.
+		Insert("alert_rules").
+		Columns("wallet_id", "asset_name", "condition", "threshold", "cooldown_seconds", "enabled", "description").
+		Values(req.WalletID, req.AssetName, req.Condition, req.Threshold, req.CooldownSeconds, enabled(req), req.Description).
+		Suffix("RETURNING " + _alertRuleColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - CreateAlertRule - Builder: %w", err)
+	}
+
+	rule, err := scanAlertRule(r.Pool.QueryRow(ctx, sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - CreateAlertRule - QueryRow: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetAlertRule retrieves an alert rule, nil when it does not exist
+func (r *AlertRuleRepo) GetAlertRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	sql, args, err := r.Builder.
+		Select(_alertRuleColumns).
+		From("alert_rules").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - GetAlertRule - Builder: %w", err)
+	}
+
+	rule, err := scanAlertRule(r.Pool.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - GetAlertRule - QueryRow: %w", err)
+	}
+
+	return rule, nil
+}
+
+// ListAlertRules retrieves the alert rules matching a filter, ordered by id
+func (r *AlertRuleRepo) ListAlertRules(ctx context.Context, filter entity.AlertRuleFilter) ([]entity.AlertRule, error) {
+	builder := r.Builder.
+		Select(_alertRuleColumns).
+		From("alert_rules")
+
+	if filter.WalletID != 0 {
+		builder = builder.Where("wallet_id = ?", filter.WalletID)
+	}
+	if filter.AssetName != "" {
+		builder = builder.Where("asset_name = ?", filter.AssetName)
+	}
+
+	sql, args, err := builder.OrderBy("id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - ListAlertRules - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - ListAlertRules - Query: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]entity.AlertRule, 0)
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("AlertRuleRepo - ListAlertRules - Scan: %w", err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - ListAlertRules - Rows: %w", err)
+	}
+
+	return rules, nil
+}
+
+// UpdateAlertRule replaces an alert rule, nil when it does not exist. The time it last fired
+// is kept.
+func (r *AlertRuleRepo) UpdateAlertRule(ctx context.Context, id int64, req entity.AlertRuleRequest) (*entity.AlertRule, error) {
+	sql := fmt.Sprintf(`
+	UPDATE alert_rules
+	SET wallet_id = $2, asset_name = $3, condition = $4, threshold = $5, cooldown_seconds = $6,
+		enabled = $7, description = $8, updated_at = NOW()
+	WHERE id = $1
+	RETURNING %s
+	`, _alertRuleColumns)
+
+	rule, err := scanAlertRule(r.Pool.QueryRow(ctx, sql, id, req.WalletID, req.AssetName, req.Condition, req.Threshold,
+		req.CooldownSeconds, enabled(req), req.Description))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("AlertRuleRepo - UpdateAlertRule - QueryRow: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule and reports whether it existed
+func (r *AlertRuleRepo) DeleteAlertRule(ctx context.Context, id int64) (bool, error) {
+	sql, args, err := r.Builder.
+		Delete("alert_rules").
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("AlertRuleRepo - DeleteAlertRule - Builder: %w", err)
+	}
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("AlertRuleRepo - DeleteAlertRule - Exec: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// enabled is the enabled flag of a request, rules are enabled unless told otherwise.
+func enabled(req entity.AlertRuleRequest) bool {
+	return req.Enabled == nil || *req.Enabled
+}
+
+func scanAlertRule(row pgx.Row) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+	err := row.Scan(&rule.ID, &rule.WalletID, &rule.AssetName, &rule.Condition, &rule.Threshold, &rule.CooldownSeconds,
+		&rule.Enabled, &rule.Description, &rule.LastFiredAt, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// BalanceSeriesRepo reads the balance series projection of the active version.
+type BalanceSeriesRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewBalanceSeriesRepo creates a new instance of BalanceSeriesRepo reading the tables of versions
+func NewBalanceSeriesRepo(pg *postgres.Postgres, versions *ProjectionVersion) *BalanceSeriesRepo {
+	return &BalanceSeriesRepo{pg, versions}
+}
+
+// GetBalanceSeries retrieves the buckets of a wallet asset with activity in [from, to), oldest first
+func (r *BalanceSeriesRepo) GetBalanceSeries(ctx context.Context, walletID int, assetName, interval string, from, to time.Time) ([]entity.BalancePoint, error) {
+	sql, args, err := r.Builder.
+		Select("bucket_start, inflow, outflow, net, closing_balance").
+		From(r.table("wallet_balance_series")).
+		Where("wallet_id = ? AND asset_name = ? AND granularity = ?", walletID, assetName, interval).
+		Where("bucket_start >= ? AND bucket_start < ?", from, to).
+		OrderBy("bucket_start").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("BalanceSeriesRepo - GetBalanceSeries - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("BalanceSeriesRepo - GetBalanceSeries - Query: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]entity.BalancePoint, 0)
+	for rows.Next() {
+		var point entity.BalancePoint
+		err = rows.Scan(&point.BucketStart, &point.Inflow, &point.Outflow, &point.Net, &point.Balance)
+		if err != nil {
+			return nil, fmt.Errorf("BalanceSeriesRepo - GetBalanceSeries - Scan: %w", err)
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("BalanceSeriesRepo - GetBalanceSeries - Rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetBalanceBefore retrieves the closing balance of the last bucket before a point in time,
+// zero when the wallet asset had no activity before it
+func (r *BalanceSeriesRepo) GetBalanceBefore(ctx context.Context, walletID int, assetName, interval string, before time.Time) (float64, error) {
+	var balance float64
+
+	sql, args, err := r.Builder.
+		Select().
+		Column(`COALESCE((
+			SELECT closing_balance FROM `+r.table("wallet_balance_series")+`
+			WHERE wallet_id = ? AND asset_name = ? AND granularity = ? AND bucket_start < ?
+			ORDER BY bucket_start DESC
+			LIMIT 1
+		), 0)`, walletID, assetName, interval, before).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("BalanceSeriesRepo - GetBalanceBefore - Builder: %w", err)
+	}
+
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("BalanceSeriesRepo - GetBalanceBefore - QueryRow: %w", err)
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// CommandRepo reads the applied events, scheduled transfers and history rows of a command.
+type CommandRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewCommandRepo creates a new instance of CommandRepo reading the tables of versions
+func NewCommandRepo(pg *postgres.Postgres, versions *ProjectionVersion) *CommandRepo {
+	return &CommandRepo{pg, versions}
+}
+
+// GetCommand retrieves what the read model holds of a command: when its first event was
+// applied, its scheduled transfer and the history rows of its events. State is left empty.
+func (r *CommandRepo) GetCommand(ctx context.Context, commandID string) (*entity.CommandStatus, error) {
+	command := &entity.CommandStatus{CommandID: commandID}
+
+	sql := fmt.Sprintf(`SELECT MIN(applied_at) FROM %s WHERE command_id = $1`, r.table("applied_events"))
+	if err := r.Pool.QueryRow(ctx, sql, commandID).Scan(&command.AppliedAt); err != nil {
+		return nil, fmt.Errorf("CommandRepo - GetCommand - applied_events: %w", err)
+	}
+
+	sql, args, err := r.Builder.
+		Select(_scheduledTransferColumns).
+		From(r.table("scheduled_transfers")).
+		Where("command_id = ?", commandID).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("CommandRepo - GetCommand - Builder: %w", err)
+	}
+
+	var transfer entity.ScheduledTransfer
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&transfer.CommandID, &transfer.FromWalletID, &transfer.ToWalletID,
+		&transfer.AssetName, &transfer.Amount, &transfer.ExecuteTime, &transfer.Status, &transfer.ScheduledAt, &transfer.ExecutedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, fmt.Errorf("CommandRepo - GetCommand - scheduled_transfers: %w", err)
+	default:
+		command.ScheduledTransfer = &transfer
+	}
+
+	sql, args, err = r.Builder.
+		Select("transaction_id, event_id, wallet_id, counterparty_wallet_id, type, asset_name, amount, balance_after, event_time, created_at").
+		From(r.table("wallet_transactions")).
+		Where(fmt.Sprintf("event_id IN (SELECT event_id FROM %s WHERE command_id = ?)", r.table("applied_events")), commandID).
+		OrderBy("transaction_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("CommandRepo - GetCommand - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("CommandRepo - GetCommand - Query: %w", err)
+	}
+	defer rows.Close()
+
+	command.Transactions = make([]entity.Transaction, 0)
+	for rows.Next() {
+		var txn entity.Transaction
+		err = rows.Scan(&txn.ID, &txn.EventID, &txn.WalletID, &txn.CounterpartyWalletID, &txn.Type, &txn.AssetName,
+			&txn.Amount, &txn.BalanceAfter, &txn.EventTime, &txn.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("CommandRepo - GetCommand - Scan: %w", err)
+		}
+		command.Transactions = append(command.Transactions, txn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("CommandRepo - GetCommand - Rows: %w", err)
+	}
+
+	return command, nil
+}
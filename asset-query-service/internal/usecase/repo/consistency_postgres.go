@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// ConsistencyRepo follows the commands projected into the active version.
+type ConsistencyRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewConsistencyRepo creates a new instance of ConsistencyRepo reading the tables of versions
+func NewConsistencyRepo(pg *postgres.Postgres, versions *ProjectionVersion) *ConsistencyRepo {
+	return &ConsistencyRepo{pg, versions}
+}
+
+// IsCommandApplied reports whether the events of a command have been projected into the active version.
+// A deferred transfer is applied once it ran, its transfer_scheduled event does not count.
+func (r *ConsistencyRepo) IsCommandApplied(ctx context.Context, commandID string) (bool, error) {
+	var applied bool
+
+	sql := fmt.Sprintf(`
+	SELECT EXISTS (SELECT 1 FROM %s WHERE command_id = $1)
+	   AND NOT EXISTS (SELECT 1 FROM %s WHERE command_id = $1 AND status = 'scheduled')
+	`, r.table("applied_events"), r.table("scheduled_transfers"))
+	if err := r.Pool.QueryRow(ctx, sql, commandID).Scan(&applied); err != nil {
+		return false, fmt.Errorf("ConsistencyRepo - IsCommandApplied - QueryRow: %w", err)
+	}
+
+	return applied, nil
+}
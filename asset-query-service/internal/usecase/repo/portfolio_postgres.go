@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// PortfolioRepo reads the balances across wallets of the active version.
+type PortfolioRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewPortfolioRepo creates a new instance of PortfolioRepo reading the tables of versions
+func NewPortfolioRepo(pg *postgres.Postgres, versions *ProjectionVersion) *PortfolioRepo {
+	return &PortfolioRepo{pg, versions}
+}
+
+// GetAssetsByWalletIDs retrieves the balances of several wallets in one query, ordered by
+// wallet and asset. assetNames restricts the assets when it is not empty.
+func (r *PortfolioRepo) GetAssetsByWalletIDs(ctx context.Context, walletIDs []int, assetNames []string) ([]entity.WalletAsset, error) {
+	builder := r.Builder.
+		Select("wallet_id, asset_name, amount").
+		From(r.table("wallet_assets")).
+		Where("wallet_id = ANY(?)", walletIDs)
+
+	if len(assetNames) > 0 {
+		builder = builder.Where("asset_name = ANY(?)", assetNames)
+	}
+
+	sql, args, err := builder.OrderBy("wallet_id", "asset_name").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PortfolioRepo - GetAssetsByWalletIDs - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PortfolioRepo - GetAssetsByWalletIDs - Query: %w", err)
+	}
+	defer rows.Close()
+
+	assets := make([]entity.WalletAsset, 0)
+	for rows.Next() {
+		var asset entity.WalletAsset
+		err = rows.Scan(&asset.WalletID, &asset.AssetName, &asset.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("PortfolioRepo - GetAssetsByWalletIDs - Scan: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PortfolioRepo - GetAssetsByWalletIDs - Rows: %w", err)
+	}
+
+	return assets, nil
+}
+
+// GetAssetHolders retrieves a page of the wallets holding a positive balance of an asset.
+// Rows are ordered by amount descending and wallet id, the page starts after after.
+func (r *PortfolioRepo) GetAssetHolders(ctx context.Context, assetName string, after *entity.HolderCursor, limit int) ([]entity.AssetHolder, error) {
+	builder := r.Builder.
+		Select("wallet_id, amount").
+		From(r.table("wallet_assets")).
+		Where("asset_name = ? AND amount > 0", assetName)
+
+	if after != nil {
+		builder = builder.Where("(amount < ? OR (amount = ? AND wallet_id > ?))", after.Amount, after.Amount, after.WalletID)
+	}
+
+	sql, args, err := builder.
+		OrderBy("amount DESC", "wallet_id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PortfolioRepo - GetAssetHolders - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PortfolioRepo - GetAssetHolders - Query: %w", err)
+	}
+	defer rows.Close()
+
+	holders := make([]entity.AssetHolder, 0)
+	for rows.Next() {
+		var holder entity.AssetHolder
+		err = rows.Scan(&holder.WalletID, &holder.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("PortfolioRepo - GetAssetHolders - Scan: %w", err)
+		}
+		holders = append(holders, holder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PortfolioRepo - GetAssetHolders - Rows: %w", err)
+	}
+
+	return holders, nil
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// ProjectionVersion is the projection version activated in asset-query-processor.
+// The repositories of the read model share it and read the tables of that version.
+type ProjectionVersion struct {
+	pg      *postgres.Postgres
+	version atomic.Int32 // Active projection version, see Follow
+}
+
+// NewProjectionVersion creates a new instance of ProjectionVersion at version 1
+func NewProjectionVersion(pg *postgres.Postgres) *ProjectionVersion {
+	v := &ProjectionVersion{pg: pg}
+	v.version.Store(1)
+	return v
+}
+
+// Follow reads the active projection version, then refreshes it every interval
+// until ctx is done. A version switch in asset-query-processor is picked up
+// within interval. When a refresh fails the last known version is kept.
+func (v *ProjectionVersion) Follow(ctx context.Context, interval time.Duration) error {
+	err := v.refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = v.refresh(ctx)
+			}
+		}
+	}()
+
+	return err
+}
+
+// Version returns the projection version queries read.
+func (v *ProjectionVersion) Version() int {
+	return int(v.version.Load())
+}
+
+func (v *ProjectionVersion) refresh(ctx context.Context) error {
+	var version int32
+
+	err := v.pg.Pool.QueryRow(ctx, `SELECT version FROM projection_versions WHERE status = 'active'`).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("ProjectionVersion - refresh - QueryRow: %w", err)
+	}
+
+	v.version.Store(version)
+	return nil
+}
+
+// table names a read model table of the active version. Version 1 owns the
+// unsuffixed tables, version N the tables suffixed with _vN.
+func (v *ProjectionVersion) table(name string) string {
+	return versionTable(name, v.Version())
+}
+
+// versionTable names a read model table of version, for reads that must not
+// switch versions halfway.
+func versionTable(name string, version int) string {
+	if version > 1 {
+		return fmt.Sprintf("%s_v%d", name, version)
+	}
+	return name
+}
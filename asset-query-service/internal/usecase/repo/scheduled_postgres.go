@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// ScheduledTransferRepo reads the scheduled transfers projection of the active version.
+type ScheduledTransferRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewScheduledTransferRepo creates a new instance of ScheduledTransferRepo reading the tables of versions
+func NewScheduledTransferRepo(pg *postgres.Postgres, versions *ProjectionVersion) *ScheduledTransferRepo {
+	return &ScheduledTransferRepo{pg, versions}
+}
+
+const _scheduledTransferColumns = "command_id, from_wallet_id, to_wallet_id, asset_name, amount, execute_time, status, scheduled_at, executed_at"
+
+// GetScheduledTransfers retrieves a page of the scheduled transfers sent or received by a wallet.
+// Rows are ordered by (execute_time, command_id), the page starts after filter.After.
+func (r *ScheduledTransferRepo) GetScheduledTransfers(ctx context.Context, filter entity.ScheduledTransferFilter) ([]entity.ScheduledTransfer, error) {
+	builder := r.Builder.
+		Select(_scheduledTransferColumns).
+		From(r.table("scheduled_transfers")).
+		Where("(from_wallet_id = ? OR to_wallet_id = ?)", filter.WalletID, filter.WalletID)
+
+	if filter.Status != "" {
+		builder = builder.Where("status = ?", filter.Status)
+	}
+	if filter.After != nil {
+		builder = builder.Where("(execute_time, command_id) > (?, ?)", filter.After.ExecuteTime, filter.After.CommandID)
+	}
+
+	sql, args, err := builder.
+		OrderBy("execute_time", "command_id").
+		Limit(uint64(filter.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfers - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfers - Query: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := make([]entity.ScheduledTransfer, 0)
+	for rows.Next() {
+		var transfer entity.ScheduledTransfer
+		err = rows.Scan(&transfer.CommandID, &transfer.FromWalletID, &transfer.ToWalletID, &transfer.AssetName,
+			&transfer.Amount, &transfer.ExecuteTime, &transfer.Status, &transfer.ScheduledAt, &transfer.ExecutedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfers - Scan: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfers - Rows: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// GetScheduledTransfersBatch retrieves the first limit scheduled transfers of several wallets in
+// one query, keyed by wallet. status restricts the transfers when it is not empty.
+func (r *ScheduledTransferRepo) GetScheduledTransfersBatch(ctx context.Context, walletIDs []int, status string, limit int) (map[int][]entity.ScheduledTransfer, error) {
+	sql := fmt.Sprintf(`
+	SELECT w.id, s.command_id, s.from_wallet_id, s.to_wallet_id, s.asset_name, s.amount,
+		s.execute_time, s.status, s.scheduled_at, s.executed_at
+	FROM unnest($1::int[]) AS w(id)
+	CROSS JOIN LATERAL (
+		SELECT %s FROM %s
+		WHERE (from_wallet_id = w.id OR to_wallet_id = w.id) AND ($2 = '' OR status = $2)
+		ORDER BY execute_time, command_id
+		LIMIT $3
+	) s
+	ORDER BY w.id, s.execute_time, s.command_id
+	`, _scheduledTransferColumns, r.table("scheduled_transfers"))
+
+	rows, err := r.Pool.Query(ctx, sql, walletIDs, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfersBatch - Query: %w", err)
+	}
+	defer rows.Close()
+
+	transfers := make(map[int][]entity.ScheduledTransfer, len(walletIDs))
+	for rows.Next() {
+		var (
+			walletID int
+			transfer entity.ScheduledTransfer
+		)
+		err = rows.Scan(&walletID, &transfer.CommandID, &transfer.FromWalletID, &transfer.ToWalletID, &transfer.AssetName,
+			&transfer.Amount, &transfer.ExecuteTime, &transfer.Status, &transfer.ScheduledAt, &transfer.ExecutedAt)
+		if err != nil {
+			return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfersBatch - Scan: %w", err)
+		}
+		transfers[walletID] = append(transfers[walletID], transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ScheduledTransferRepo - GetScheduledTransfersBatch - Rows: %w", err)
+	}
+
+	return transfers, nil
+}
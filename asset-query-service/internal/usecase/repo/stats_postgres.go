@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// StatsRepo reads the daily analytics projection of the active version.
+type StatsRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewStatsRepo creates a new instance of StatsRepo reading the tables of versions
+func NewStatsRepo(pg *postgres.Postgres, versions *ProjectionVersion) *StatsRepo {
+	return &StatsRepo{pg, versions}
+}
+
+// GetDailyStats retrieves the daily aggregates of the days in [from, to], by day and asset.
+// assetName restricts the asset when it is not empty.
+func (r *StatsRepo) GetDailyStats(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetDailyStats, error) {
+	builder := r.Builder.
+		Select("day, asset_name, deposit_volume, deposit_count, withdraw_volume, withdraw_count, "+
+			"transfer_volume, transfer_count, active_wallets, net_flow, total_holdings").
+		From(r.table("asset_daily_stats")).
+		Where("day >= ? AND day <= ?", from, to)
+
+	if assetName != "" {
+		builder = builder.Where("asset_name = ?", assetName)
+	}
+
+	sql, args, err := builder.OrderBy("day", "asset_name").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("StatsRepo - GetDailyStats - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("StatsRepo - GetDailyStats - Query: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]entity.AssetDailyStats, 0)
+	for rows.Next() {
+		var s entity.AssetDailyStats
+		err = rows.Scan(&s.Day, &s.AssetName, &s.DepositVolume, &s.DepositCount, &s.WithdrawVolume, &s.WithdrawCount,
+			&s.TransferVolume, &s.TransferCount, &s.ActiveWallets, &s.NetFlow, &s.TotalHoldings)
+		if err != nil {
+			return nil, fmt.Errorf("StatsRepo - GetDailyStats - Scan: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("StatsRepo - GetDailyStats - Rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetStatsSummary retrieves the aggregates of the days in [from, to] per asset. Every asset
+// with holdings by the end of the range is listed, also without activity in the range.
+// assetName restricts the asset when it is not empty.
+func (r *StatsRepo) GetStatsSummary(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetStatsSummary, error) {
+	stats, active := r.table("asset_daily_stats"), r.table("asset_daily_active_wallets")
+
+	sql := fmt.Sprintf(`
+	SELECT a.asset_name,
+		COALESCE(p.deposit_volume, 0), COALESCE(p.deposit_count, 0),
+		COALESCE(p.withdraw_volume, 0), COALESCE(p.withdraw_count, 0),
+		COALESCE(p.transfer_volume, 0), COALESCE(p.transfer_count, 0),
+		(SELECT COUNT(DISTINCT w.wallet_id) FROM %[2]s w WHERE w.asset_name = a.asset_name AND w.day >= $1 AND w.day <= $2),
+		COALESCE(p.net_flow, 0),
+		h.total_holdings
+	FROM (SELECT DISTINCT asset_name FROM %[1]s WHERE day <= $2 AND ($3 = '' OR asset_name = $3)) a
+	CROSS JOIN LATERAL (
+		SELECT total_holdings FROM %[1]s WHERE asset_name = a.asset_name AND day <= $2 ORDER BY day DESC LIMIT 1
+	) h
+	CROSS JOIN LATERAL (
+		SELECT SUM(deposit_volume) AS deposit_volume, SUM(deposit_count) AS deposit_count,
+			SUM(withdraw_volume) AS withdraw_volume, SUM(withdraw_count) AS withdraw_count,
+			SUM(transfer_volume) AS transfer_volume, SUM(transfer_count) AS transfer_count,
+			SUM(net_flow) AS net_flow
+		FROM %[1]s WHERE asset_name = a.asset_name AND day >= $1 AND day <= $2
+	) p
+	ORDER BY a.asset_name
+	`, stats, active)
+
+	rows, err := r.Pool.Query(ctx, sql, from, to, assetName)
+	if err != nil {
+		return nil, fmt.Errorf("StatsRepo - GetStatsSummary - Query: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make([]entity.AssetStatsSummary, 0)
+	for rows.Next() {
+		var s entity.AssetStatsSummary
+		err = rows.Scan(&s.AssetName, &s.DepositVolume, &s.DepositCount, &s.WithdrawVolume, &s.WithdrawCount,
+			&s.TransferVolume, &s.TransferCount, &s.ActiveWallets, &s.NetFlow, &s.TotalHoldings)
+		if err != nil {
+			return nil, fmt.Errorf("StatsRepo - GetStatsSummary - Scan: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("StatsRepo - GetStatsSummary - Rows: %w", err)
+	}
+
+	return summaries, nil
+}
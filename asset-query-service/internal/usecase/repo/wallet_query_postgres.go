@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
@@ -10,18 +11,19 @@ import (
 
 type WalletQueryRepo struct {
 	*postgres.Postgres
+	*ProjectionVersion
 }
 
-// NewWalletQueryRepo creates a new instance of WalletQueryRepo
-func NewWalletQueryRepo(pg *postgres.Postgres) *WalletQueryRepo {
-	return &WalletQueryRepo{pg}
+// NewWalletQueryRepo creates a new instance of WalletQueryRepo reading the tables of versions
+func NewWalletQueryRepo(pg *postgres.Postgres, versions *ProjectionVersion) *WalletQueryRepo {
+	return &WalletQueryRepo{pg, versions}
 }
 
 // GetAssetsByWalletID retrieves all assets and their amounts for a specific wallet ID
 func (r *WalletQueryRepo) GetAssetsByWalletID(ctx context.Context, walletID int) ([]entity.WalletAsset, error) {
 	sql, _, err := r.Builder.
 		Select("asset_name, amount").
-		From("wallet_assets").
+		From(r.table("wallet_assets")).
 		Where("wallet_id = ?", walletID).
 		ToSql()
 	if err != nil {
@@ -53,7 +55,7 @@ func (r *WalletQueryRepo) GetWalletAsset(ctx context.Context, walletID int, asse
 
 	sql, _, err := r.Builder.
 		Select("amount").
-		From("wallet_assets").
+		From(r.table("wallet_assets")).
 		Where("wallet_id = ? AND asset_name = ?", walletID, assetName).
 		ToSql()
 	if err != nil {
@@ -68,10 +70,72 @@ func (r *WalletQueryRepo) GetWalletAsset(ctx context.Context, walletID int, asse
 	return amount, nil
 }
 
+// GetAssetsByWalletIDAsOf retrieves the balances of a wallet's assets as of a point in time.
+// Each balance is the sum of the signed amounts with an event time at or before asOf. The running
+// balance_after of a row follows the order the events were projected in, which a late event breaks.
+func (r *WalletQueryRepo) GetAssetsByWalletIDAsOf(ctx context.Context, walletID int, asOf time.Time) ([]entity.WalletAsset, error) {
+	sql, args, err := r.Builder.
+		Select("asset_name, SUM(amount), MAX(event_time)").
+		From(r.table("wallet_transactions")).
+		Where("wallet_id = ? AND event_time <= ?", walletID, asOf).
+		GroupBy("asset_name").
+		OrderBy("asset_name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("WalletQueryRepo - GetAssetsByWalletIDAsOf - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("WalletQueryRepo - GetAssetsByWalletIDAsOf - Query: %w", err)
+	}
+	defer rows.Close()
+
+	assets := make([]entity.WalletAsset, 0)
+	for rows.Next() {
+		asset := entity.WalletAsset{WalletID: walletID}
+		err = rows.Scan(&asset.AssetName, &asset.Amount, &asset.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("WalletQueryRepo - GetAssetsByWalletIDAsOf - Scan: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("WalletQueryRepo - GetAssetsByWalletIDAsOf - Rows: %w", err)
+	}
+
+	return assets, nil
+}
+
+// GetWalletAssetAsOf retrieves the balance of a wallet asset as of a point in time.
+// The balance is the sum of the signed amounts at or before asOf, zero without any.
+func (r *WalletQueryRepo) GetWalletAssetAsOf(ctx context.Context, walletID int, assetName string, asOf time.Time) (float64, error) {
+	var amount float64
+
+	sql, args, err := r.Builder.
+		Select().
+		Column(`COALESCE((
+			SELECT SUM(amount) FROM `+r.table("wallet_transactions")+`
+			WHERE wallet_id = ? AND asset_name = ? AND event_time <= ?
+		), 0)`, walletID, assetName, asOf).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("WalletQueryRepo - GetWalletAssetAsOf - Builder: %w", err)
+	}
+
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&amount)
+	if err != nil {
+		return 0, fmt.Errorf("WalletQueryRepo - GetWalletAssetAsOf - QueryRow: %w", err)
+	}
+
+	return amount, nil
+}
+
 // UpdateWalletAsset updates the amount of a specific asset for a wallet
 func (r *WalletQueryRepo) UpdateWalletAsset(ctx context.Context, walletID int, assetName string, amount float64) error {
 	sql, _, err := r.Builder.
-		Update("wallet_assets").
+		Update(r.table("wallet_assets")).
 		Set("amount", amount).
 		Where("wallet_id = ? AND asset_name = ?", walletID, assetName).
 		ToSql()
@@ -90,7 +154,7 @@ func (r *WalletQueryRepo) UpdateWalletAsset(ctx context.Context, walletID int, a
 // InsertOrUpdateWalletAsset inserts or updates a wallet asset entry
 func (r *WalletQueryRepo) InsertOrUpdateWalletAsset(ctx context.Context, walletID int, assetName string, amount float64) error {
 	sql, _, err := r.Builder.
-		Insert("wallet_assets").
+		Insert(r.table("wallet_assets")).
 		Columns("wallet_id", "asset_name", "amount").
 		Values(walletID, assetName, amount).
 		Suffix("ON CONFLICT (wallet_id, asset_name) DO UPDATE SET amount = EXCLUDED.amount").
@@ -107,19 +171,53 @@ func (r *WalletQueryRepo) InsertOrUpdateWalletAsset(ctx context.Context, walletI
 	return nil
 }
 
-// GetTransactionHistory retrieves transaction history for a wallet
-func (r *WalletQueryRepo) GetTransactionHistory(ctx context.Context, walletID int) ([]entity.Transaction, error) {
-	sql, _, err := r.Builder.
-		Select("transaction_id, wallet_id, asset_name, type, amount, created_at").
-		From("wallet_transactions").
-		Where("wallet_id = ?", walletID).
-		OrderBy("created_at DESC").
+// GetTransactionHistory retrieves a page of a wallet's transaction history.
+// Rows are ordered by (event_time, transaction_id), the page starts after filter.After.
+func (r *WalletQueryRepo) GetTransactionHistory(ctx context.Context, filter entity.TransactionFilter) ([]entity.Transaction, error) {
+	order, cmp := "DESC", "<"
+	if filter.Ascending {
+		order, cmp = "ASC", ">"
+	}
+
+	builder := r.Builder.
+		Select("transaction_id, event_id, wallet_id, counterparty_wallet_id, type, asset_name, amount, balance_after, event_time, created_at").
+		From(r.table("wallet_transactions")).
+		Where("wallet_id = ?", filter.WalletID)
+
+	if filter.AssetName != "" {
+		builder = builder.Where("asset_name = ?", filter.AssetName)
+	}
+	if filter.Type != "" {
+		builder = builder.Where("type = ?", filter.Type)
+	}
+	if filter.MinAmount != nil {
+		builder = builder.Where("ABS(amount) >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		builder = builder.Where("ABS(amount) <= ?", *filter.MaxAmount)
+	}
+	if filter.From != nil {
+		builder = builder.Where("event_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		builder = builder.Where("event_time < ?", *filter.To)
+	}
+	if filter.Counterparty != nil {
+		builder = builder.Where("counterparty_wallet_id = ?", *filter.Counterparty)
+	}
+	if filter.After != nil {
+		builder = builder.Where("(event_time, transaction_id) "+cmp+" (?, ?)", filter.After.EventTime, filter.After.ID)
+	}
+
+	sql, args, err := builder.
+		OrderBy("event_time "+order, "transaction_id "+order).
+		Limit(uint64(filter.Limit)).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistory - Builder: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, sql, walletID)
+	rows, err := r.Pool.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistory - Query: %w", err)
 	}
@@ -128,12 +226,57 @@ func (r *WalletQueryRepo) GetTransactionHistory(ctx context.Context, walletID in
 	transactions := make([]entity.Transaction, 0)
 	for rows.Next() {
 		var txn entity.Transaction
-		err = rows.Scan(&txn.ID, &txn.WalletID, &txn.AssetName, &txn.Type, &txn.Amount, &txn.CreatedAt)
+		err = rows.Scan(&txn.ID, &txn.EventID, &txn.WalletID, &txn.CounterpartyWalletID, &txn.Type, &txn.AssetName,
+			&txn.Amount, &txn.BalanceAfter, &txn.EventTime, &txn.CreatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistory - Scan: %w", err)
 		}
 		transactions = append(transactions, txn)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistory - Rows: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionHistoryBatch retrieves the last limit transactions of several wallets in one query,
+// ordered by wallet and newest first.
+func (r *WalletQueryRepo) GetTransactionHistoryBatch(ctx context.Context, walletIDs []int, limit int) ([]entity.Transaction, error) {
+	sql := fmt.Sprintf(`
+	SELECT t.transaction_id, t.event_id, t.wallet_id, t.counterparty_wallet_id, t.type, t.asset_name,
+		t.amount, t.balance_after, t.event_time, t.created_at
+	FROM unnest($1::int[]) AS w(id)
+	CROSS JOIN LATERAL (
+		SELECT * FROM %s
+		WHERE wallet_id = w.id
+		ORDER BY event_time DESC, transaction_id DESC
+		LIMIT $2
+	) t
+	ORDER BY w.id, t.event_time DESC, t.transaction_id DESC
+	`, r.table("wallet_transactions"))
+
+	rows, err := r.Pool.Query(ctx, sql, walletIDs, limit)
+	if err != nil {
+		return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistoryBatch - Query: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]entity.Transaction, 0)
+	for rows.Next() {
+		var txn entity.Transaction
+		err = rows.Scan(&txn.ID, &txn.EventID, &txn.WalletID, &txn.CounterpartyWalletID, &txn.Type, &txn.AssetName,
+			&txn.Amount, &txn.BalanceAfter, &txn.EventTime, &txn.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistoryBatch - Scan: %w", err)
+		}
+		transactions = append(transactions, txn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("WalletQueryRepo - GetTransactionHistoryBatch - Rows: %w", err)
+	}
+
 	return transactions, nil
 }
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// _listenTimeout bounds a wait for a notification, so a switch of the active
+// projection version moves the listener to the history table of the new version.
+const _listenTimeout = 5 * time.Second
+
+// WalletStreamRepo reads the history rows and notifications of the active version for wallet streams.
+type WalletStreamRepo struct {
+	*postgres.Postgres
+	*ProjectionVersion
+}
+
+// NewWalletStreamRepo creates a new instance of WalletStreamRepo reading the tables of versions
+func NewWalletStreamRepo(pg *postgres.Postgres, versions *ProjectionVersion) *WalletStreamRepo {
+	return &WalletStreamRepo{pg, versions}
+}
+
+// ListenTransactions calls notify with the wallet id of every history row asset-query-processor
+// commits to the active projection version, until ctx is done or the connection fails.
+func (r *WalletStreamRepo) ListenTransactions(ctx context.Context, notify func(walletID int)) error {
+	conn, err := r.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("WalletStreamRepo - ListenTransactions - Acquire: %w", err)
+	}
+	defer conn.Release()
+
+	channel := ""
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "UNLISTEN *")
+	}()
+
+	for ctx.Err() == nil {
+		if table := r.table("wallet_transactions"); table != channel {
+			if _, err := conn.Exec(ctx, "UNLISTEN *"); err != nil {
+				return fmt.Errorf("WalletStreamRepo - ListenTransactions - UNLISTEN: %w", err)
+			}
+			if _, err := conn.Exec(ctx, "LISTEN "+table); err != nil {
+				return fmt.Errorf("WalletStreamRepo - ListenTransactions - LISTEN: %w", err)
+			}
+			channel = table
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, _listenTimeout)
+		n, err := conn.Conn().WaitForNotification(waitCtx)
+		cancel()
+		if err != nil {
+			if waitCtx.Err() != nil {
+				continue
+			}
+			return fmt.Errorf("WalletStreamRepo - ListenTransactions - WaitForNotification: %w", err)
+		}
+
+		if walletID, err := strconv.Atoi(n.Payload); err == nil && n.Channel == channel {
+			notify(walletID)
+		}
+	}
+
+	return nil
+}
+
+// GetTransactionsAfter retrieves the history rows of a wallet after the stream position after,
+// in stream order, from the history table of the version of after. Stream positions are drawn
+// in commit order, so a row committed later cannot show up behind one already read.
+func (r *WalletStreamRepo) GetTransactionsAfter(ctx context.Context, walletID int, after entity.StreamPosition, limit int) ([]entity.Transaction, error) {
+	sql, args, err := r.Builder.
+		Select("transaction_id, event_id, wallet_id, counterparty_wallet_id, type, asset_name, amount, balance_after, event_time, created_at, stream_seq").
+		From(versionTable("wallet_transactions", after.Version)).
+		Where("wallet_id = ? AND stream_seq > ?", walletID, after.Seq).
+		OrderBy("stream_seq").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("WalletStreamRepo - GetTransactionsAfter - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("WalletStreamRepo - GetTransactionsAfter - Query: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]entity.Transaction, 0)
+	for rows.Next() {
+		var txn entity.Transaction
+		err = rows.Scan(&txn.ID, &txn.EventID, &txn.WalletID, &txn.CounterpartyWalletID, &txn.Type, &txn.AssetName,
+			&txn.Amount, &txn.BalanceAfter, &txn.EventTime, &txn.CreatedAt, &txn.StreamSeq)
+		if err != nil {
+			return nil, fmt.Errorf("WalletStreamRepo - GetTransactionsAfter - Scan: %w", err)
+		}
+		transactions = append(transactions, txn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("WalletStreamRepo - GetTransactionsAfter - Rows: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetSnapshot retrieves the balances of a wallet and the stream position of its last history row,
+// zero without history, in one snapshot of the tables of the active version.
+func (r *WalletStreamRepo) GetSnapshot(ctx context.Context, walletID int) (entity.StreamPosition, []entity.WalletAsset, error) {
+	position := entity.StreamPosition{Version: r.Version()}
+
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return entity.StreamPosition{}, nil, fmt.Errorf("WalletStreamRepo - GetSnapshot - BeginTx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	sql := fmt.Sprintf(`SELECT COALESCE(MAX(stream_seq), 0) FROM %s WHERE wallet_id = $1`, versionTable("wallet_transactions", position.Version))
+	if err := tx.QueryRow(ctx, sql, walletID).Scan(&position.Seq); err != nil {
+		return entity.StreamPosition{}, nil, fmt.Errorf("WalletStreamRepo - GetSnapshot - QueryRow: %w", err)
+	}
+
+	sql = fmt.Sprintf(`SELECT asset_name, amount FROM %s WHERE wallet_id = $1 ORDER BY asset_name`, versionTable("wallet_assets", position.Version))
+	rows, err := tx.Query(ctx, sql, walletID)
+	if err != nil {
+		return entity.StreamPosition{}, nil, fmt.Errorf("WalletStreamRepo - GetSnapshot - Query: %w", err)
+	}
+	defer rows.Close()
+
+	assets := make([]entity.WalletAsset, 0)
+	for rows.Next() {
+		asset := entity.WalletAsset{WalletID: walletID}
+		if err := rows.Scan(&asset.AssetName, &asset.Amount); err != nil {
+			return entity.StreamPosition{}, nil, fmt.Errorf("WalletStreamRepo - GetSnapshot - Scan: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entity.StreamPosition{}, nil, fmt.Errorf("WalletStreamRepo - GetSnapshot - Rows: %w", err)
+	}
+
+	return position, assets, nil
+}
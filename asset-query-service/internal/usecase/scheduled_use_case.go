@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+)
+
+// ScheduledTransferUseCase reads the deferred transfers waiting for their execute time and their outcome.
+type ScheduledTransferUseCase struct {
+	repo ScheduledTransferRepositoryHandler
+}
+
+// NewScheduledTransferUseCase creates a new instance of ScheduledTransferUseCase
+func NewScheduledTransferUseCase(r ScheduledTransferRepositoryHandler) *ScheduledTransferUseCase {
+	return &ScheduledTransferUseCase{repo: r}
+}
+
+// GetScheduledTransfers retrieves a page of the scheduled transfers sent or received by a
+// wallet, earliest execute time first
+func (uc *ScheduledTransferUseCase) GetScheduledTransfers(ctx context.Context, filter entity.ScheduledTransferFilter, cursor string) (*entity.ScheduledTransferPage, error) {
+	filter.Limit = historyLimit(filter.Limit)
+
+	if cursor != "" {
+		after := &entity.ScheduledTransferCursor{}
+		if err := decodeCursor(cursor, scheduledTransferQuery(filter), after); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = after
+	}
+
+	// One extra row tells whether there is a next page
+	pageSize := filter.Limit
+	filter.Limit++
+
+	transfers, err := uc.repo.GetScheduledTransfers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduledTransferUseCase - GetScheduledTransfers - uc.repo.GetScheduledTransfers: %w", err)
+	}
+
+	return scheduledTransferPage(transfers, pageSize, scheduledTransferQuery(filter)), nil
+}
+
+// GetScheduledTransfersBatch retrieves the first page of the scheduled transfers of several
+// wallets, keyed by wallet. Every wallet of the batch has a page.
+func (uc *ScheduledTransferUseCase) GetScheduledTransfersBatch(ctx context.Context, walletIDs []int, status string, limit int) (map[int]*entity.ScheduledTransferPage, error) {
+	if len(walletIDs) == 0 || len(walletIDs) > _maxBatchWallets {
+		return nil, ErrInvalidBatch
+	}
+	limit = historyLimit(limit)
+
+	// One extra row per wallet tells whether there is a next page
+	transfers, err := uc.repo.GetScheduledTransfersBatch(ctx, walletIDs, status, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("ScheduledTransferUseCase - GetScheduledTransfersBatch - uc.repo.GetScheduledTransfersBatch: %w", err)
+	}
+
+	pages := make(map[int]*entity.ScheduledTransferPage, len(walletIDs))
+	for _, id := range walletIDs {
+		pages[id] = scheduledTransferPage(transfers[id], limit, entity.ScheduledTransferFilter{WalletID: id, Status: status})
+	}
+
+	return pages, nil
+}
+
+// scheduledTransferQuery is the part of a filter a page token is bound to.
+func scheduledTransferQuery(filter entity.ScheduledTransferFilter) entity.ScheduledTransferFilter {
+	filter.Limit = 0
+	filter.After = nil
+	return filter
+}
+
+// scheduledTransferPage cuts a page of pageSize transfers, its next page token is bound to query.
+func scheduledTransferPage(transfers []entity.ScheduledTransfer, pageSize int, query entity.ScheduledTransferFilter) *entity.ScheduledTransferPage {
+	if transfers == nil {
+		transfers = make([]entity.ScheduledTransfer, 0)
+	}
+
+	page := &entity.ScheduledTransferPage{Transfers: transfers}
+	if len(transfers) > pageSize {
+		page.Transfers = transfers[:pageSize]
+		last := page.Transfers[pageSize-1]
+		page.NextCursor = encodeCursor(query, entity.ScheduledTransferCursor{ExecuteTime: last.ExecuteTime, CommandID: last.CommandID})
+	}
+
+	return page
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+)
+
+const (
+	_day             = 24 * time.Hour
+	_defaultStatDays = 30
+	_maxStatDays     = 366
+)
+
+// StatsUseCase reads the platform-wide analytics per asset.
+type StatsUseCase struct {
+	repo StatsRepositoryHandler
+}
+
+// NewStatsUseCase creates a new instance of StatsUseCase
+func NewStatsUseCase(r StatsRepositoryHandler) *StatsUseCase {
+	return &StatsUseCase{repo: r}
+}
+
+// GetDailyStats retrieves the platform-wide aggregates per asset of the UTC days in
+// [from, to]. A zero to means today and a zero from means 30 days up to to.
+func (uc *StatsUseCase) GetDailyStats(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetDailyStats, error) {
+	from, to, err := statsRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.repo.GetDailyStats(ctx, assetName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("StatsUseCase - GetDailyStats - uc.repo.GetDailyStats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetStatsSummary retrieves the platform-wide aggregates per asset over the UTC days in
+// [from, to], with the same defaults as GetDailyStats.
+func (uc *StatsUseCase) GetStatsSummary(ctx context.Context, assetName string, from, to time.Time) ([]entity.AssetStatsSummary, error) {
+	from, to, err := statsRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := uc.repo.GetStatsSummary(ctx, assetName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("StatsUseCase - GetStatsSummary - uc.repo.GetStatsSummary: %w", err)
+	}
+	return summaries, nil
+}
+
+// statsRange applies the defaults to a range of days and checks its length.
+func statsRange(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	to = to.UTC().Truncate(_day)
+
+	if from.IsZero() {
+		from = to.Add(-(_defaultStatDays - 1) * _day)
+	}
+	from = from.UTC().Truncate(_day)
+
+	if from.After(to) || to.Sub(from) >= _maxStatDays*_day {
+		return from, to, ErrInvalidRange
+	}
+	return from, to, nil
+}
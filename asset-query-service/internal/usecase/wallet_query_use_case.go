@@ -2,12 +2,26 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
 	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
 )
 
+const (
+	_defaultHistoryLimit = 50
+	_maxHistoryLimit     = 500
+)
+
+// ErrInvalidCursor is returned for a page token that was not issued for the same query.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 // WalletQueryUseCase implements business logic for wallet asset operations
 type WalletQueryUseCase struct {
 	repo WalletQueryRepositoryHandler
@@ -41,22 +55,145 @@ func (uc *WalletQueryUseCase) GetAssetBalance(ctx context.Context, walletID int,
 	}, nil
 }
 
-// GetTransactionHistory retrieves the transaction history for a given wallet and asset
-func (uc *WalletQueryUseCase) GetTransactionHistory(ctx context.Context, walletID int, assetName string) ([]entity.Transaction, error) {
-	transactions, err := uc.repo.GetTransactionHistory(ctx, walletID)
+// GetAllAssetsAsOf retrieves all assets for a given wallet ID as of a point in time
+func (uc *WalletQueryUseCase) GetAllAssetsAsOf(ctx context.Context, walletID int, asOf time.Time) ([]entity.WalletAsset, error) {
+	assets, err := uc.repo.GetAssetsByWalletIDAsOf(ctx, walletID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("WalletQueryUseCase - GetAllAssetsAsOf - uc.repo.GetAssetsByWalletIDAsOf: %w", err)
+	}
+	return assets, nil
+}
+
+// GetAssetBalanceAsOf retrieves the balance of a specific asset in a wallet as of a point in time
+func (uc *WalletQueryUseCase) GetAssetBalanceAsOf(ctx context.Context, walletID int, assetName string, asOf time.Time) (*entity.WalletAsset, error) {
+	amount, err := uc.repo.GetWalletAssetAsOf(ctx, walletID, assetName, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("WalletQueryUseCase - GetAssetBalanceAsOf - uc.repo.GetWalletAssetAsOf: %w", err)
+	}
+	return &entity.WalletAsset{
+		WalletID:  walletID,
+		AssetName: assetName,
+		Amount:    amount,
+	}, nil
+}
+
+// GetTransactionHistory retrieves a page of the transaction history of a wallet
+func (uc *WalletQueryUseCase) GetTransactionHistory(ctx context.Context, filter entity.TransactionFilter, cursor string) (*entity.TransactionPage, error) {
+	filter.Limit = historyLimit(filter.Limit)
+
+	if cursor != "" {
+		after := &entity.TransactionCursor{}
+		if err := decodeCursor(cursor, transactionQuery(filter), after); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = after
+	}
+
+	// One extra row tells whether there is a next page
+	pageSize := filter.Limit
+	filter.Limit++
+
+	transactions, err := uc.repo.GetTransactionHistory(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("WalletQueryUseCase - GetTransactionHistory - uc.repo.GetTransactionHistory: %w", err)
 	}
-	return filterTransactionsByAsset(transactions, assetName), nil
+
+	page := &entity.TransactionPage{Transactions: transactions}
+	if len(transactions) > pageSize {
+		page.Transactions = transactions[:pageSize]
+		last := page.Transactions[pageSize-1]
+		page.NextCursor = encodeCursor(transactionQuery(filter), entity.TransactionCursor{EventTime: last.EventTime, ID: last.ID})
+	}
+
+	return page, nil
 }
 
-// Helper function to filter transactions by asset name
-func filterTransactionsByAsset(transactions []entity.Transaction, assetName string) []entity.Transaction {
-	filtered := make([]entity.Transaction, 0)
+// GetTransactionHistoryBatch retrieves the first page of the transaction history of several
+// wallets, newest first and keyed by wallet. Every wallet of the batch has a page.
+func (uc *WalletQueryUseCase) GetTransactionHistoryBatch(ctx context.Context, walletIDs []int, limit int) (map[int]*entity.TransactionPage, error) {
+	if len(walletIDs) == 0 || len(walletIDs) > _maxBatchWallets {
+		return nil, ErrInvalidBatch
+	}
+	limit = historyLimit(limit)
+
+	// One extra row per wallet tells whether there is a next page
+	transactions, err := uc.repo.GetTransactionHistoryBatch(ctx, walletIDs, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("WalletQueryUseCase - GetTransactionHistoryBatch - uc.repo.GetTransactionHistoryBatch: %w", err)
+	}
+
+	pages := make(map[int]*entity.TransactionPage, len(walletIDs))
+	for _, id := range walletIDs {
+		pages[id] = &entity.TransactionPage{Transactions: make([]entity.Transaction, 0)}
+	}
 	for _, txn := range transactions {
-		if txn.AssetName == assetName {
-			filtered = append(filtered, txn)
+		page := pages[txn.WalletID]
+		if len(page.Transactions) == limit {
+			last := page.Transactions[limit-1]
+			page.NextCursor = encodeCursor(entity.TransactionFilter{WalletID: txn.WalletID}, entity.TransactionCursor{EventTime: last.EventTime, ID: last.ID})
+			continue
 		}
+		page.Transactions = append(page.Transactions, txn)
+	}
+
+	return pages, nil
+}
+
+// historyLimit applies the default and the maximum to a page size.
+func historyLimit(limit int) int {
+	if limit <= 0 {
+		return _defaultHistoryLimit
+	}
+	if limit > _maxHistoryLimit {
+		return _maxHistoryLimit
+	}
+	return limit
+}
+
+// transactionQuery is the part of a filter a page token is bound to, the page size may change
+// from page to page.
+func transactionQuery(filter entity.TransactionFilter) entity.TransactionFilter {
+	filter.Limit = 0
+	filter.After = nil
+	return filter
+}
+
+// pageToken is the content of a page token, the ordering key of the last row of a page and a
+// hash of the query the page was read for.
+type pageToken struct {
+	Key   json.RawMessage `json:"k"`
+	Query string          `json:"q"`
+}
+
+// encodeCursor makes an opaque page token of an ordering key, only valid for query.
+func encodeCursor(query, cursor any) string {
+	key, _ := json.Marshal(cursor)                                     //nolint:errchkjson // plain struct
+	b, _ := json.Marshal(pageToken{Key: key, Query: queryHash(query)}) //nolint:errchkjson // plain struct
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor reads the ordering key of a page token into cursor. It fails for a token that
+// was issued for another query.
+func decodeCursor(token string, query, cursor any) error {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
 	}
-	return filtered
+
+	var t pageToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	if t.Query != queryHash(query) {
+		return ErrInvalidCursor
+	}
+
+	return json.Unmarshal(t.Key, cursor)
+}
+
+// queryHash identifies the filters of a paged query.
+func queryHash(query any) string {
+	b, _ := json.Marshal(query) //nolint:errchkjson // plain struct
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
 }
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	at := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	minAmount := 10.0
+	query := entity.TransactionFilter{WalletID: 1, AssetName: "BTC", MinAmount: &minAmount}
+	key := entity.TransactionCursor{EventTime: at, ID: 42}
+	token := encodeCursor(query, key)
+
+	otherAmount := 20.0
+	tests := []struct {
+		name    string
+		token   string
+		query   entity.TransactionFilter
+		wantErr bool
+	}{
+		{"same query", token, query, false},
+		{"same query with another page size", token, func() entity.TransactionFilter { q := query; q.Limit = 10; return transactionQuery(q) }(), false},
+		{"other wallet", token, entity.TransactionFilter{WalletID: 2, AssetName: "BTC", MinAmount: &minAmount}, true},
+		{"other asset", token, entity.TransactionFilter{WalletID: 1, AssetName: "ETH", MinAmount: &minAmount}, true},
+		{"other amount", token, entity.TransactionFilter{WalletID: 1, AssetName: "BTC", MinAmount: &otherAmount}, true},
+		{"other order", token, entity.TransactionFilter{WalletID: 1, AssetName: "BTC", MinAmount: &minAmount, Ascending: true}, true},
+		{"not base64", "not a token!", query, true},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("{")), query, true},
+		{"empty hash", base64.RawURLEncoding.EncodeToString([]byte(`{"k":{"id":42}}`)), query, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got entity.TransactionCursor
+			err := decodeCursor(tt.token, tt.query, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeCursor = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !got.EventTime.Equal(key.EventTime) || got.ID != key.ID {
+				t.Fatalf("decodeCursor = %+v, want %+v", got, key)
+			}
+		})
+	}
+}
+
+func TestQueryHash(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		a, b     entity.TransactionFilter
+		wantSame bool
+	}{
+		{"equal filters", entity.TransactionFilter{WalletID: 1, Type: "deposit"}, entity.TransactionFilter{WalletID: 1, Type: "deposit"}, true},
+		{"equal pointed values", entity.TransactionFilter{WalletID: 1, From: &from}, entity.TransactionFilter{WalletID: 1, From: func() *time.Time { f := from; return &f }()}, true},
+		{"other type", entity.TransactionFilter{WalletID: 1, Type: "deposit"}, entity.TransactionFilter{WalletID: 1, Type: "withdraw"}, false},
+		{"from set", entity.TransactionFilter{WalletID: 1}, entity.TransactionFilter{WalletID: 1, From: &from}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := queryHash(tt.a) == queryHash(tt.b); same != tt.wantSame {
+				t.Fatalf("queryHash equal = %t, want %t", same, tt.wantSame)
+			}
+		})
+	}
+}
+
+// historyRepo serves a fixed transaction history, newest first.
+type historyRepo struct {
+	WalletQueryRepositoryHandler
+	transactions []entity.Transaction
+}
+
+func (r *historyRepo) GetTransactionHistory(_ context.Context, filter entity.TransactionFilter) ([]entity.Transaction, error) {
+	var page []entity.Transaction
+	for _, txn := range r.transactions {
+		if filter.After != nil && txn.ID >= filter.After.ID {
+			continue
+		}
+		if len(page) == filter.Limit {
+			break
+		}
+		page = append(page, txn)
+	}
+	return page, nil
+}
+
+func TestGetTransactionHistoryPages(t *testing.T) {
+	repo := &historyRepo{}
+	for id := int64(5); id > 0; id-- {
+		repo.transactions = append(repo.transactions, entity.Transaction{ID: id, WalletID: 1})
+	}
+	uc := NewWalletQueryUseCase(repo, nil)
+	filter := entity.TransactionFilter{WalletID: 1, Limit: 2}
+
+	tests := []struct {
+		name    string
+		filter  entity.TransactionFilter
+		wantIDs []int64
+		wantErr error
+	}{
+		{"first page", filter, []int64{5, 4}, nil},
+		{"second page", filter, []int64{3, 2}, nil},
+		{"last page", filter, []int64{1}, nil},
+		{"cursor of another query", entity.TransactionFilter{WalletID: 1, Type: "deposit", Limit: 2}, nil, ErrInvalidCursor},
+	}
+
+	cursor, last := "", ""
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr != nil {
+				if _, err := uc.GetTransactionHistory(context.Background(), tt.filter, last); !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetTransactionHistory error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			page, err := uc.GetTransactionHistory(context.Background(), tt.filter, cursor)
+			if err != nil {
+				t.Fatalf("GetTransactionHistory: %v", err)
+			}
+			if len(page.Transactions) != len(tt.wantIDs) {
+				t.Fatalf("got %d transactions, want %v", len(page.Transactions), tt.wantIDs)
+			}
+			for i, txn := range page.Transactions {
+				if txn.ID != tt.wantIDs[i] {
+					t.Fatalf("transaction %d has id %d, want %d", i, txn.ID, tt.wantIDs[i])
+				}
+			}
+			if cursor != "" {
+				last = cursor
+			}
+			cursor = page.NextCursor
+		})
+	}
+
+	if cursor != "" {
+		t.Fatalf("last page has next cursor %q, want none", cursor)
+	}
+}
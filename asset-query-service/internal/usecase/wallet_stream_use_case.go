@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/asset-query-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const (
+	_streamBatch     = 500
+	_streamHeartbeat = 15 * time.Second
+	_listenRetry     = time.Second
+)
+
+// WalletStreamUseCase streams the balance changes and transactions of wallets as asset-query-processor projects them.
+type WalletStreamUseCase struct {
+	repo WalletStreamRepositoryHandler
+	log  logger.Interface
+	hub  *walletHub
+}
+
+// NewWalletStreamUseCase creates a new instance of WalletStreamUseCase
+func NewWalletStreamUseCase(r WalletStreamRepositoryHandler, log logger.Interface) *WalletStreamUseCase {
+	return &WalletStreamUseCase{repo: r, log: log, hub: newWalletHub()}
+}
+
+// walletHub fans the history notifications of asset-query-processor out to the
+// streams of each wallet. A signal means "new rows may exist", so signals that
+// arrive while a stream is busy are coalesced into one.
+type walletHub struct {
+	mu      sync.Mutex
+	streams map[int]map[chan struct{}]struct{}
+}
+
+func newWalletHub() *walletHub {
+	return &walletHub{streams: make(map[int]map[chan struct{}]struct{})}
+}
+
+func (h *walletHub) subscribe(walletID int) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.streams[walletID] == nil {
+		h.streams[walletID] = make(map[chan struct{}]struct{})
+	}
+	h.streams[walletID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.streams[walletID], ch)
+		if len(h.streams[walletID]) == 0 {
+			delete(h.streams, walletID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *walletHub) notify(walletID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.streams[walletID] {
+		signal(ch)
+	}
+}
+
+func (h *walletHub) notifyAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, streams := range h.streams {
+		for ch := range streams {
+			signal(ch)
+		}
+	}
+}
+
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// ListenTransactions forwards the history notifications to the wallet streams until ctx is
+// done. The listener reconnects after a failure, and every stream then catches up, since
+// notifications sent in the meantime are lost.
+func (uc *WalletStreamUseCase) ListenTransactions(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := uc.repo.ListenTransactions(ctx, uc.hub.notify)
+		if err == nil {
+			return
+		}
+		uc.log.Error(fmt.Errorf("WalletStreamUseCase - ListenTransactions - uc.repo.ListenTransactions: %w", err))
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(_listenRetry):
+		}
+		uc.hub.notifyAll()
+	}
+}
+
+// StreamWallet sends a snapshot of the balances of a wallet unless it resumes after the stream
+// position after, then a transaction and a balance event for every row projected after it, until ctx
+// is done or send fails. Stream positions are numbered per projection version: a stream resumed after
+// a position of another version, or open while the active version switches, starts over from a
+// snapshot of the active version. A heartbeat is sent every 15 seconds of silence, transports that
+// keep their connections alive on their own may drop it.
+func (uc *WalletStreamUseCase) StreamWallet(ctx context.Context, walletID int, after *entity.StreamPosition, send func(entity.WalletStreamEvent) error) error {
+	updates, unsubscribe := uc.hub.subscribe(walletID)
+	defer unsubscribe()
+
+	var last entity.StreamPosition
+	if after != nil {
+		last = *after
+	}
+
+	heartbeat := time.NewTicker(_streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		if last.Version != uc.repo.Version() {
+			position, assets, err := uc.repo.GetSnapshot(ctx, walletID)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("WalletStreamUseCase - StreamWallet - uc.repo.GetSnapshot: %w", err)
+			}
+
+			last = position
+			if err := send(entity.WalletStreamEvent{ID: last, WalletID: walletID, Event: "snapshot", Assets: assets}); err != nil {
+				return nil
+			}
+		}
+
+		for {
+			transactions, err := uc.repo.GetTransactionsAfter(ctx, walletID, last, _streamBatch)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("WalletStreamUseCase - StreamWallet - uc.repo.GetTransactionsAfter: %w", err)
+			}
+			if len(transactions) == 0 {
+				break
+			}
+
+			for i := range transactions {
+				last.Seq = transactions[i].StreamSeq
+				if err := sendTransaction(send, last, &transactions[i]); err != nil {
+					return nil
+				}
+			}
+		}
+
+		// A heartbeat also catches up with notifications lost while the listener reconnected,
+		// and with a switch of the active version
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-updates:
+		case <-heartbeat.C:
+			if err := send(entity.WalletStreamEvent{ID: last, WalletID: walletID, Event: "heartbeat"}); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+func sendTransaction(send func(entity.WalletStreamEvent) error, id entity.StreamPosition, txn *entity.Transaction) error {
+	if err := send(entity.WalletStreamEvent{ID: id, WalletID: txn.WalletID, Event: "transaction", Transaction: txn}); err != nil {
+		return err
+	}
+
+	balance := &entity.WalletAsset{WalletID: txn.WalletID, AssetName: txn.AssetName, Amount: txn.BalanceAfter, UpdatedAt: txn.EventTime}
+	return send(entity.WalletStreamEvent{ID: id, WalletID: txn.WalletID, Event: "balance", Balance: balance})
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/journal"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/producer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging/pgqueue"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+// archiveSummary is the result of an export or import.
+type archiveSummary struct {
+	Dir     string `json:"dir"`
+	Topic   string `json:"topic"`
+	Files   int    `json:"files"`
+	Records int64  `json:"records"`
+	Skipped int64  `json:"skipped,omitempty"`
+}
+
+func journalExport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	dir := fs.String("dir", "", "directory to write the archive to, must not hold one")
+	from := fs.String("from", "", "only events written at or after this time (RFC 3339)")
+	period := fs.Duration("period", time.Hour, "time span of a file")
+	topic := fs.String("topic", e.profile.EventTopic, "topic to export")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errors.New("-dir is required")
+	}
+
+	since, err := parseTime("-from", *from)
+	if err != nil {
+		return err
+	}
+
+	source, closeSource, err := e.journalSource(*topic, since)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+
+	w, err := journal.NewWriter(*dir, *topic, journal.Period(*period))
+	if err != nil {
+		return err
+	}
+	if err := source.Subscribe(ctx, w.Handler()); err != nil {
+		w.Abort()
+		return err
+	}
+	if ctx.Err() != nil {
+		w.Abort()
+		return fmt.Errorf("export of %s interrupted, %s has no manifest", *topic, *dir)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	archive, err := journal.Open(*dir)
+	if err != nil {
+		return err
+	}
+	return printSummary(e, archiveSummary{Dir: *dir, Topic: *topic, Files: len(archive.Manifest.Files), Records: w.Records()})
+}
+
+func journalImport(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	dir := fs.String("dir", "", "directory of the archive")
+	topic := fs.String("topic", "", "topic to publish to, the exported topic by default")
+	skip := fs.Int64("skip", 0, "skip the first N records, to resume an interrupted import")
+	verifyOnly := fs.Bool("verify", false, "only check the files against the manifest")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errors.New("-dir is required")
+	}
+
+	archive, err := journal.Open(*dir)
+	if err != nil {
+		return err
+	}
+	if err := archive.Verify(); err != nil {
+		return err
+	}
+
+	summary := archiveSummary{Dir: *dir, Topic: archive.Manifest.Topic, Files: len(archive.Manifest.Files)}
+	if *topic != "" {
+		summary.Topic = *topic
+	}
+	if *verifyOnly {
+		summary.Records = archive.Manifest.Records
+		return printSummary(e, summary)
+	}
+
+	target, closeTarget, err := e.journalTarget()
+	if err != nil {
+		return err
+	}
+	defer closeTarget()
+
+	var seen int64
+	err = archive.Replay(ctx, summary.Topic, func(ctx context.Context, msg messaging.Message) error {
+		seen++
+		if seen <= *skip {
+			summary.Skipped++
+			return nil
+		}
+		if err := target.Publish(ctx, msg); err != nil {
+			return err
+		}
+		summary.Records++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%d records published, resume with -skip %d: %w", summary.Records, summary.Skipped+summary.Records, err)
+	}
+
+	return printSummary(e, summary)
+}
+
+func printSummary(e *env, s archiveSummary) error {
+	return e.out.print(s, []string{"DIR", "TOPIC", "FILES", "RECORDS", "SKIPPED"}, [][]string{{
+		s.Dir, s.Topic, strconv.Itoa(s.Files), strconv.FormatInt(s.Records, 10), strconv.FormatInt(s.Skipped, 10),
+	}})
+}
+
+// journalSource reads topic from since, or from its beginning, to its current
+// end on the messaging driver of the profile.
+func (e *env) journalSource(topic string, since time.Time) (messaging.Subscriber, func(), error) {
+	switch e.profile.MessagingDriver {
+	case "kafka":
+		opts := []consumer.ReaderOption{consumer.FromBeginning(), consumer.UntilEnd()}
+		if !since.IsZero() {
+			opts = append(opts, consumer.FromTime(since))
+		}
+		reader, err := consumer.NewReader(e.profile.KafkaBroker, topic, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return reader, reader.Close, nil
+	case "postgres":
+		queue, closeQueue, err := e.queue()
+		if err != nil {
+			return nil, nil, err
+		}
+		return queue.Reader(topic, since), closeQueue, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown messaging driver %q", e.profile.MessagingDriver)
+	}
+}
+
+// journalTarget publishes on the messaging driver of the profile.
+func (e *env) journalTarget() (messaging.Publisher, func(), error) {
+	switch e.profile.MessagingDriver {
+	case "kafka":
+		p, err := producer.NewKafkaProducer(e.profile.KafkaBroker)
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, p.Close, nil
+	case "postgres":
+		queue, closeQueue, err := e.queue()
+		if err != nil {
+			return nil, nil, err
+		}
+		return queue.Publisher(), closeQueue, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown messaging driver %q", e.profile.MessagingDriver)
+	}
+}
+
+func (e *env) queue() (*pgqueue.Queue, func(), error) {
+	if e.profile.MessagingPGURL == "" {
+		return nil, nil, errors.New("the postgres messaging driver needs messaging_pg_url")
+	}
+
+	pg, err := postgres.New(e.profile.MessagingPGURL, postgres.ConnAttempts(1))
+	if err != nil {
+		return nil, nil, err
+	}
+	queue, err := pgqueue.New(pg)
+	if err != nil {
+		pg.Close()
+		return nil, nil, err
+	}
+	return queue, pg.Close, nil
+}
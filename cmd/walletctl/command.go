@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/client"
+)
+
+const _statusPollInterval = 500 * time.Millisecond
+
+// commandFlags are the flags every asset command takes.
+type commandFlags struct {
+	asset  *string
+	amount *float64
+	key    *string
+	wait   *time.Duration
+}
+
+func newCommandFlags(fs *flag.FlagSet) commandFlags {
+	return commandFlags{
+		asset:  fs.String("asset", "", "asset name, e.g. BTC"),
+		amount: fs.Float64("amount", 0, "amount, positive"),
+		key:    fs.String("key", "", "idempotency key, send the command again with the same key to apply it once"),
+		wait:   fs.Duration("wait", 0, "wait up to this long for the command to be projected and show its status"),
+	}
+}
+
+func (f commandFlags) validate() error {
+	if *f.asset == "" || *f.amount <= 0 {
+		return errors.New("-asset and a positive -amount are required")
+	}
+	return nil
+}
+
+// context sets the idempotency key of the command.
+func (f commandFlags) context(ctx context.Context) context.Context {
+	if *f.key != "" {
+		return client.WithIdempotencyKey(ctx, *f.key)
+	}
+	return ctx
+}
+
+func deposit(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	walletID := fs.Int("wallet", 0, "wallet id")
+	flags := newCommandFlags(fs)
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := flags.validate(); err != nil {
+		return err
+	}
+
+	token, err := e.assets().Deposit(flags.context(ctx), *walletID, *flags.asset, *flags.amount)
+	if err != nil {
+		return err
+	}
+	return showCommand(ctx, e, token, *flags.wait)
+}
+
+func withdraw(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	walletID := fs.Int("wallet", 0, "wallet id")
+	flags := newCommandFlags(fs)
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := flags.validate(); err != nil {
+		return err
+	}
+
+	token, err := e.assets().Withdraw(flags.context(ctx), *walletID, *flags.asset, *flags.amount)
+	if err != nil {
+		return err
+	}
+	return showCommand(ctx, e, token, *flags.wait)
+}
+
+func transfer(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	from := fs.Int("from", 0, "sender wallet id")
+	to := fs.Int("to", 0, "receiver wallet id")
+	at := fs.String("at", "", "execute time (RFC 3339), now by default")
+	flags := newCommandFlags(fs)
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := flags.validate(); err != nil {
+		return err
+	}
+
+	var executeTime time.Time
+	if *at != "" {
+		var err error
+		if executeTime, err = time.Parse(time.RFC3339, *at); err != nil {
+			return fmt.Errorf("invalid -at %q, use RFC 3339", *at)
+		}
+	}
+
+	token, err := e.assets().Transfer(flags.context(ctx), *from, *to, *flags.asset, *flags.amount, executeTime)
+	if err != nil {
+		return err
+	}
+	return showCommand(ctx, e, token, *flags.wait)
+}
+
+func commandStatus(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	wait := fs.Duration("wait", 0, "wait up to this long while the command is pending")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	status, err := awaitCommand(ctx, e.queries(), args[0], *wait)
+	if err != nil {
+		return err
+	}
+	return printStatus(e, status)
+}
+
+// showCommand prints the consistency token of an accepted command, or its
+// status once projected when wait is set.
+func showCommand(ctx context.Context, e *env, token string, wait time.Duration) error {
+	if wait <= 0 {
+		return e.out.print(map[string]string{"command_id": token}, []string{"COMMAND_ID"}, [][]string{{token}})
+	}
+
+	status, err := awaitCommand(ctx, e.queries(), token, wait)
+	if err != nil {
+		return err
+	}
+	return printStatus(e, status)
+}
+
+// awaitCommand polls the status of a command until it is no longer pending or wait runs out.
+func awaitCommand(ctx context.Context, q *client.QueryClient, token string, wait time.Duration) (*client.CommandStatus, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		status, err := q.GetCommandStatus(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if status.State != client.CommandPending || time.Now().Add(_statusPollInterval).After(deadline) {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, nil
+		case <-time.After(_statusPollInterval):
+		}
+	}
+}
+
+func printStatus(e *env, status *client.CommandStatus) error {
+	if e.out.json {
+		return e.out.print(status, nil, nil)
+	}
+
+	executeTime := "-"
+	if t := status.ScheduledTransfer; t != nil {
+		executeTime = formatTime(t.ExecuteTime)
+	}
+	err := e.out.print(status, []string{"COMMAND_ID", "STATE", "APPLIED", "EXECUTE_TIME"},
+		[][]string{{status.CommandID, status.State, formatOptionalTime(status.AppliedAt), executeTime}})
+	if err != nil || len(status.Transactions) == 0 {
+		return err
+	}
+
+	rows := make([][]string, 0, len(status.Transactions))
+	for _, txn := range status.Transactions {
+		rows = append(rows, []string{formatTime(txn.EventTime), strconv.Itoa(txn.WalletID), txn.Type, txn.AssetName,
+			formatFloat(txn.Amount), formatFloat(txn.BalanceAfter)})
+	}
+
+	fmt.Fprintln(e.out.w)
+	return e.out.print(status, []string{"TIME", "WALLET", "TYPE", "ASSET", "AMOUNT", "BALANCE"}, rows)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+)
+
+// errStop ends a tail once enough events are printed.
+var errStop = errors.New("stop")
+
+// journalEvent is an event of the event journal, as written by asset-processor.
+type journalEvent struct {
+	EventID           string    `json:"event_id"`
+	CommandID         string    `json:"command_id,omitempty"`
+	WalletID          int       `json:"wallet_id"`
+	TargetWalletID    int       `json:"target_wallet_id,omitempty"`
+	AssetName         string    `json:"asset_name"`
+	Type              string    `json:"type"`
+	Amount            float64   `json:"amount"`
+	Timestamp         int64     `json:"timestamp"`
+	ExecuteTime       int64     `json:"execute_time,omitempty"`
+	ProjectionVersion int       `json:"projection_version,omitempty"`
+	Partition         int32     `json:"partition"`
+	Offset            int64     `json:"offset"`
+	Written           time.Time `json:"written"` // Timestamp of the Kafka message
+}
+
+// eventFilter selects the events a tail prints, zero fields match every event.
+type eventFilter struct {
+	walletID  int
+	assetName string
+	eventType string
+	commandID string
+}
+
+func (f eventFilter) match(ev journalEvent) bool {
+	return (f.walletID == 0 || ev.WalletID == f.walletID || ev.TargetWalletID == f.walletID) &&
+		(f.assetName == "" || ev.AssetName == f.assetName) &&
+		(f.eventType == "" || ev.Type == f.eventType) &&
+		(f.commandID == "" || ev.CommandID == f.commandID)
+}
+
+func tail(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	fromBeginning := fs.Bool("from-beginning", false, "start at the oldest event")
+	since := fs.Duration("since", 0, "start at the events written this long ago, e.g. 1h")
+	untilEnd := fs.Bool("until-end", false, "stop at the end of the journal instead of following it")
+	count := fs.Int("n", 0, "stop after this many events")
+	var filter eventFilter
+	fs.IntVar(&filter.walletID, "wallet", 0, "only events of this wallet, as sender or receiver")
+	fs.StringVar(&filter.assetName, "asset", "", "only events of this asset")
+	fs.StringVar(&filter.eventType, "type", "", "only events of this type")
+	fs.StringVar(&filter.commandID, "command", "", "only events of this command")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var opts []consumer.ReaderOption
+	switch {
+	case *fromBeginning && *since > 0:
+		return errors.New("-from-beginning and -since exclude each other")
+	case *fromBeginning:
+		opts = append(opts, consumer.FromBeginning())
+	case *since > 0:
+		opts = append(opts, consumer.FromTime(time.Now().Add(-*since)))
+	}
+	if *untilEnd {
+		opts = append(opts, consumer.UntilEnd())
+	}
+
+	reader, err := consumer.NewReader(e.profile.KafkaBroker, e.profile.EventTopic, opts...)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if !e.out.json {
+		fmt.Fprintln(e.out.w, strings.Join(_tailHeader, "  "))
+	}
+
+	printed := 0
+	err = reader.Subscribe(ctx, func(ctx context.Context, msg messaging.Message) error {
+		ev, err := decodeEvent(msg)
+		if err != nil {
+			fmt.Fprintf(e.out.w, "%d:%d  undecodable event: %s\n", msg.Partition, msg.Offset, err)
+			return nil
+		}
+		if !filter.match(ev) {
+			return nil
+		}
+
+		if err := e.out.line(ev, tailFields(ev)...); err != nil {
+			return err
+		}
+
+		printed++
+		if *count > 0 && printed >= *count {
+			return errStop
+		}
+		return nil
+	})
+	if errors.Is(err, errStop) {
+		return nil
+	}
+	return err
+}
+
+var _tailHeader = []string{
+	fmt.Sprintf("%-25s", "TIME"), fmt.Sprintf("%-9s", "OFFSET"), fmt.Sprintf("%-18s", "TYPE"),
+	fmt.Sprintf("%-7s", "WALLET"), fmt.Sprintf("%-7s", "TARGET"), fmt.Sprintf("%-6s", "ASSET"),
+	fmt.Sprintf("%12s", "AMOUNT"), "COMMAND_ID",
+}
+
+func tailFields(ev journalEvent) []string {
+	target := "-"
+	if ev.TargetWalletID != 0 {
+		target = strconv.Itoa(ev.TargetWalletID)
+	}
+	return []string{
+		fmt.Sprintf("%-25s", formatTime(time.Unix(ev.Timestamp, 0))),
+		fmt.Sprintf("%-9s", fmt.Sprintf("%d:%d", ev.Partition, ev.Offset)),
+		fmt.Sprintf("%-18s", ev.Type),
+		fmt.Sprintf("%-7d", ev.WalletID),
+		fmt.Sprintf("%-7s", target),
+		fmt.Sprintf("%-6s", ev.AssetName),
+		fmt.Sprintf("%12s", formatFloat(ev.Amount)),
+		ev.CommandID,
+	}
+}
+
+// decodeEvent reads the event of a journal message. Producers write the JSON
+// event either as is or as a base64 JSON string.
+func decodeEvent(msg messaging.Message) (journalEvent, error) {
+	value := msg.Value
+	if len(value) > 0 && value[0] == '"' {
+		var err error
+		if value, err = base64.StdEncoding.DecodeString(strings.Trim(string(value), "\"")); err != nil {
+			return journalEvent{}, err
+		}
+	}
+
+	var ev journalEvent
+	if err := json.Unmarshal(value, &ev); err != nil {
+		return journalEvent{}, err
+	}
+	ev.Partition, ev.Offset, ev.Written = msg.Partition, msg.Offset, msg.Timestamp
+	return ev, nil
+}
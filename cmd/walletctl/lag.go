@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"sort"
+	"strconv"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+)
+
+func lag(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	group := fs.String("group", "", "consumer group, the consumer_groups of the profile by default")
+	topic := fs.String("topic", "", "topic the group consumes, required with -group")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	groups := e.profile.ConsumerGroups
+	if *group != "" {
+		if *topic == "" {
+			return errors.New("-group needs -topic")
+		}
+		groups = map[string]string{*group: *topic}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ctx, cancel := context.WithTimeout(ctx, e.profile.Timeout)
+	defer cancel()
+
+	lags := make([]admin.PartitionLag, 0)
+	var rows [][]string
+	for _, name := range names {
+		partitions, err := admin.ConsumerLag(ctx, e.profile.KafkaBroker, name, groups[name])
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for _, p := range partitions {
+			committed := "-"
+			if p.Committed >= 0 {
+				committed = strconv.FormatInt(p.Committed, 10)
+			}
+			rows = append(rows, []string{p.Group, p.Topic, strconv.Itoa(int(p.Partition)), committed,
+				strconv.FormatInt(p.End, 10), strconv.FormatInt(p.Lag, 10)})
+			total += p.Lag
+		}
+		rows = append(rows, []string{name, groups[name], "total", "", "", strconv.FormatInt(total, 10)})
+		lags = append(lags, partitions...)
+	}
+
+	return e.out.print(lags, []string{"GROUP", "TOPIC", "PARTITION", "COMMITTED", "END", "LAG"}, rows)
+}
@@ -0,0 +1,189 @@
+// Command walletctl is the operator CLI of the wallet platform. It creates and
+// inspects wallets, sends asset commands and follows them, reads balances and
+// history, tails the event journal and shows the lag of the consumer groups. It
+// also exports the event journal to files and imports them into another cluster.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/client"
+)
+
+// command is a subcommand of walletctl.
+type command struct {
+	name    string
+	args    string // Arguments and flags, for the usage
+	summary string
+	run     func(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error
+}
+
+var _commands = []command{
+	{"wallet create", "-address ADDRESS -network NETWORK", "Create a wallet", walletCreate},
+	{"wallet get", "WALLET_ID", "Show a wallet", walletGet},
+	{"wallet delete", "WALLET_ID", "Delete a wallet", walletDelete},
+	{"deposit", "-wallet ID -asset ASSET -amount AMOUNT [-key KEY] [-wait DURATION]", "Deposit into a wallet", deposit},
+	{"withdraw", "-wallet ID -asset ASSET -amount AMOUNT [-key KEY] [-wait DURATION]", "Withdraw from a wallet", withdraw},
+	{"transfer", "-from ID -to ID -asset ASSET -amount AMOUNT [-at TIME] [-key KEY] [-wait DURATION]", "Transfer between wallets", transfer},
+	{"status", "COMMAND_ID [-wait DURATION]", "Show how far a command is projected", commandStatus},
+	{"balances", "WALLET_ID... [-asset ASSET] [-as-of TIME] [-consistency TOKEN]", "Show the balances of wallets", balances},
+	{"history", "WALLET_ID [-asset ASSET] [-type TYPE] [-from TIME] [-to TIME] [-asc] [-limit N] [-cursor CURSOR] [-all]", "Show the transaction history of a wallet", history},
+	{"tail", "[-from-beginning | -since DURATION] [-until-end] [-wallet ID] [-asset ASSET] [-type TYPE] [-command ID] [-n COUNT]", "Follow the event journal", tail},
+	{"lag", "[-group GROUP -topic TOPIC]", "Show the lag of the consumer groups", lag},
+	{"journal export", "-dir DIR [-from TIME] [-period DURATION] [-topic TOPIC]", "Export the event journal to NDJSON files", journalExport},
+	{"journal import", "-dir DIR [-topic TOPIC] [-skip N] [-verify]", "Publish an exported journal in its original order", journalImport},
+}
+
+// env is what the subcommands run with.
+type env struct {
+	profile *Profile
+	out     *printer
+}
+
+func (e *env) options() []client.Option {
+	return []client.Option{
+		client.Timeout(e.profile.Timeout),
+		client.MaxRetries(e.profile.Retries),
+		client.UserAgent("walletctl"),
+	}
+}
+
+func (e *env) wallets() *client.WalletClient {
+	return client.NewWalletClient(e.profile.WalletURL, e.options()...)
+}
+
+func (e *env) assets() *client.AssetClient {
+	return client.NewAssetClient(e.profile.AssetURL, e.options()...)
+}
+
+func (e *env) queries() *client.QueryClient {
+	return client.NewQueryClient(e.profile.QueryURL, e.options()...)
+}
+
+func main() {
+	fs := flag.NewFlagSet("walletctl", flag.ContinueOnError)
+	profileName := fs.String("profile", os.Getenv("WALLETCTL_PROFILE"), "profile to read from the walletctl directory of the user config dir")
+	profilePath := fs.String("config", "", "path of the profile file, instead of -profile")
+	output := fs.String("o", "", "output format, table or json; the profile output by default")
+	fs.Usage = usage(fs)
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	cmd, args, ok := lookup(fs.Args())
+	if !ok {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	profile, err := loadProfile(*profilePath, *profileName)
+	if err != nil {
+		fatal(err)
+	}
+	if *output != "" {
+		profile.Output = *output
+	}
+
+	out, err := newPrinter(os.Stdout, profile.Output)
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd.run(ctx, &env{profile: profile, out: out}, cmd.flagSet(), args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fatal(err)
+	}
+}
+
+// lookup finds the subcommand named by the first one or two arguments.
+func lookup(args []string) (command, []string, bool) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for _, cmd := range _commands {
+			if cmd.name == name {
+				return cmd, args[n:], true
+			}
+		}
+	}
+	return command{}, nil, false
+}
+
+func usage(fs *flag.FlagSet) func() {
+	return func() {
+		w := fs.Output()
+		fmt.Fprintf(w, "Usage: walletctl [-profile NAME | -config FILE] [-o table|json] COMMAND\n\nCommands:\n")
+
+		cmds := append([]command(nil), _commands...)
+		sort.SliceStable(cmds, func(i, j int) bool { return cmds[i].name < cmds[j].name })
+		for _, cmd := range cmds {
+			fmt.Fprintf(w, "  %-14s %s\n  %-14s   %s\n", cmd.name, cmd.summary, "", cmd.args)
+		}
+
+		fmt.Fprintf(w, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+}
+
+// parse reads the flags of a subcommand, which may come before, between or
+// after its arguments. It checks there are at least min and at most max
+// arguments, max < 0 allows any number.
+func parse(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		fs.Usage()
+		return nil, flag.ErrHelp
+	}
+	return positional, nil
+}
+
+// flagSet returns an empty flag set of the subcommand, which defines its flags.
+func (cmd command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: walletctl %s %s\n", cmd.name, cmd.args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func parseID(name, value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return id, nil
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "walletctl: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printer writes results as a table, or as indented JSON.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case "table":
+		return &printer{w: w}, nil
+	case "json":
+		return &printer{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, use table or json", format)
+	}
+}
+
+// print writes v as JSON, or header and rows as a table.
+func (p *printer) print(v any, header []string, rows [][]string) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// line writes v as one line of JSON, or the fields separated by two spaces,
+// for results that are streamed.
+func (p *printer) line(v any, fields ...string) error {
+	if p.json {
+		return json.NewEncoder(p.w).Encode(v)
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(fields, "  "))
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
+
+func formatOptionalInt(i *int) string {
+	if i == nil {
+		return "-"
+	}
+	return strconv.Itoa(*i)
+}
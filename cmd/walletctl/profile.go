@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+const _defaultProfile = "default"
+
+// Profile holds the endpoints of one environment. It is read from
+// <user config dir>/walletctl/<profile>.yml, environment variables override it.
+type Profile struct {
+	WalletURL       string            `yaml:"wallet_url"      env:"WALLETCTL_WALLET_URL"      env-default:"http://localhost:8081"`
+	AssetURL        string            `yaml:"asset_url"       env:"WALLETCTL_ASSET_URL"       env-default:"http://localhost:8082"`
+	QueryURL        string            `yaml:"query_url"       env:"WALLETCTL_QUERY_URL"       env-default:"http://localhost:8083"`
+	KafkaBroker     string            `yaml:"kafka_broker"    env:"WALLETCTL_KAFKA_BROKER"    env-default:"localhost:9094"`
+	EventTopic      string            `yaml:"event_topic"     env:"WALLETCTL_EVENT_TOPIC"     env-default:"event-journal"`
+	MessagingDriver string            `yaml:"messaging_driver"  env:"WALLETCTL_MESSAGING_DRIVER"  env-default:"kafka"` // Of journal export and import, "kafka" or "postgres"
+	MessagingPGURL  string            `yaml:"messaging_pg_url"  env:"WALLETCTL_MESSAGING_PG_URL"`
+	ConsumerGroups  map[string]string `yaml:"consumer_groups" env:"WALLETCTL_CONSUMER_GROUPS" env-default:"asset-processor-group:command-queue,asset-query-processor-group:event-journal"` // Topic of each group lag reports
+	Timeout         time.Duration     `yaml:"timeout"         env:"WALLETCTL_TIMEOUT"         env-default:"10s"`                                                                           // Of every HTTP request and Kafka lookup
+	Retries         int               `yaml:"retries"         env:"WALLETCTL_RETRIES"         env-default:"3"`
+	Output          string            `yaml:"output"          env:"WALLETCTL_OUTPUT"          env-default:"table"` // "table" or "json"
+}
+
+// loadProfile reads the profile file at path, or of the named profile. The
+// default profile may be missing, walletctl then runs on the defaults.
+func loadProfile(path, name string) (*Profile, error) {
+	p := &Profile{}
+
+	if path == "" {
+		if name == "" {
+			name = _defaultProfile
+		}
+
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", name, err)
+		}
+		path = filepath.Join(dir, "walletctl", name+".yml")
+
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) && name == _defaultProfile {
+			if err := cleanenv.ReadEnv(p); err != nil {
+				return nil, fmt.Errorf("profile %s: %w", name, err)
+			}
+			return p, nil
+		}
+	}
+
+	if err := cleanenv.ReadConfig(path, p); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", path, err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/client"
+)
+
+func balances(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	asset := fs.String("asset", "", "only this asset")
+	asOf := fs.String("as-of", "", "balances at this time (RFC 3339), single wallet only")
+	consistency := fs.String("consistency", "", "consistency token of a command the balances must include")
+	args, err := parse(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	walletIDs := make([]int, len(args))
+	for i, arg := range args {
+		if walletIDs[i], err = parseID("wallet id", arg); err != nil {
+			return err
+		}
+	}
+
+	at, err := parseTime("-as-of", *asOf)
+	if err != nil {
+		return err
+	}
+	if !at.IsZero() && len(walletIDs) > 1 {
+		return fmt.Errorf("-as-of takes a single wallet")
+	}
+
+	if *consistency != "" {
+		ctx = client.WithConsistencyToken(ctx, *consistency)
+	}
+
+	q := e.queries()
+	var wallets []client.WalletBalances
+	if len(walletIDs) == 1 {
+		result, err := q.GetAssets(ctx, walletIDs[0], at)
+		if err != nil {
+			return err
+		}
+		if result.Stale {
+			fmt.Fprintln(os.Stderr, "walletctl: stale, the command is not projected yet")
+		}
+		wallets = []client.WalletBalances{{WalletID: walletIDs[0], Assets: result.Assets}}
+	} else {
+		var assets []string
+		if *asset != "" {
+			assets = []string{*asset}
+		}
+		if wallets, err = q.GetBalancesBatch(ctx, walletIDs, assets); err != nil {
+			return err
+		}
+	}
+
+	var rows [][]string
+	for i := range wallets {
+		filtered := wallets[i].Assets[:0]
+		for _, a := range wallets[i].Assets {
+			if *asset != "" && a.AssetName != *asset {
+				continue
+			}
+			filtered = append(filtered, a)
+			rows = append(rows, []string{strconv.Itoa(wallets[i].WalletID), a.AssetName, formatFloat(a.Amount)})
+		}
+		wallets[i].Assets = filtered
+	}
+
+	return e.out.print(wallets, []string{"WALLET", "ASSET", "AMOUNT"}, rows)
+}
+
+func history(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	asset := fs.String("asset", "", "only this asset")
+	typ := fs.String("type", "", "only this type: withdraw, deposit, transfer or balance_correction")
+	from := fs.String("from", "", "first time, inclusive (RFC 3339)")
+	to := fs.String("to", "", "last time, exclusive (RFC 3339)")
+	asc := fs.Bool("asc", false, "oldest first")
+	limit := fs.Int("limit", 50, "transactions per page")
+	cursor := fs.String("cursor", "", "next cursor of the previous page")
+	all := fs.Bool("all", false, "read every page")
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	filter := client.TransactionFilter{AssetName: *asset, Type: *typ, Ascending: *asc, Limit: *limit}
+	if filter.WalletID, err = parseID("wallet id", args[0]); err != nil {
+		return err
+	}
+	if filter.From, err = parseTime("-from", *from); err != nil {
+		return err
+	}
+	if filter.To, err = parseTime("-to", *to); err != nil {
+		return err
+	}
+
+	q := e.queries()
+	var page client.TransactionPage
+	if *all {
+		it := q.Transactions(ctx, filter)
+		for it.Next() {
+			page.Transactions = append(page.Transactions, it.Value())
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+	} else {
+		result, err := q.GetTransactions(ctx, filter, *cursor)
+		if err != nil {
+			return err
+		}
+		page = *result
+	}
+
+	rows := make([][]string, 0, len(page.Transactions))
+	for _, txn := range page.Transactions {
+		rows = append(rows, []string{strconv.FormatInt(txn.ID, 10), formatTime(txn.EventTime), txn.Type, txn.AssetName,
+			formatFloat(txn.Amount), formatFloat(txn.BalanceAfter), formatOptionalInt(txn.CounterpartyWalletID)})
+	}
+
+	if err := e.out.print(page, []string{"ID", "TIME", "TYPE", "ASSET", "AMOUNT", "BALANCE", "COUNTERPARTY"}, rows); err != nil {
+		return err
+	}
+	if page.NextCursor != "" && !e.out.json {
+		fmt.Fprintf(os.Stderr, "walletctl: more transactions, pass -cursor %s\n", page.NextCursor)
+	}
+	return nil
+}
+
+func parseTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, use RFC 3339", name, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strconv"
+)
+
+func walletCreate(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	address := fs.String("address", "", "wallet address")
+	network := fs.String("network", "", "network of the wallet, e.g. bitcoin")
+	if _, err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if *address == "" || *network == "" {
+		return errors.New("-address and -network are required")
+	}
+
+	id, err := e.wallets().CreateWallet(ctx, *address, *network)
+	if err != nil {
+		return err
+	}
+
+	return e.out.print(map[string]int{"id": id}, []string{"ID"}, [][]string{{strconv.Itoa(id)}})
+}
+
+func walletGet(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("wallet id", args[0])
+	if err != nil {
+		return err
+	}
+
+	w, err := e.wallets().GetWallet(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return e.out.print(w, []string{"ID", "ADDRESS", "NETWORK", "STATUS", "CREATED"},
+		[][]string{{strconv.Itoa(w.ID), w.Address, w.Network, w.Status, formatTime(w.CreatedAt)}})
+}
+
+func walletDelete(ctx context.Context, e *env, fs *flag.FlagSet, args []string) error {
+	args, err := parse(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID("wallet id", args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := e.wallets().DeleteWallet(ctx, id); err != nil {
+		return err
+	}
+
+	return e.out.print(map[string]any{"id": id, "deleted": true}, []string{"ID", "DELETED"},
+		[][]string{{strconv.Itoa(id), "true"}})
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type (
+	// Config -.
+	Config struct {
+		App       `yaml:"app"`
+		Log       `yaml:"logger"`
+		Kafka     `yaml:"kafka"`
+		Messaging `yaml:"messaging"`
+		Archive   `yaml:"archive"`
+		S3        `yaml:"s3"`
+	}
+
+	// App -.
+	App struct {
+		Name    string `env-required:"true" yaml:"name"    env:"APP_NAME"`
+		Version string `env-required:"true" yaml:"version" env:"APP_VERSION"`
+	}
+
+	// Log -.
+	Log struct {
+		Level string `env-required:"true" yaml:"log_level"   env:"LOG_LEVEL"`
+	}
+
+	Kafka struct {
+		KAFKA_BROKER       string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
+		EVENT_TOPIC        string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
+		REPLICATION_FACTOR int    `env-required:"true"  yaml:"REPLICATION_FACTOR"  env:"KAFKA_REPLICATION_FACTOR"`
+	}
+
+	// Messaging -.
+	Messaging struct {
+		DRIVER string `env-required:"true"  yaml:"DRIVER"  env:"MESSAGING_DRIVER"` // "kafka", "postgres" is rejected since it cannot resume from the archive
+	}
+
+	// Archive -.
+	Archive struct {
+		STORE         string        `env-required:"true"  yaml:"STORE"  env:"ARCHIVE_STORE"` // "local" or "s3"
+		LOCAL_DIR     string        `yaml:"LOCAL_DIR"  env:"ARCHIVE_LOCAL_DIR"`              // Root of the "local" store
+		PREFIX        string        `yaml:"PREFIX"  env:"ARCHIVE_PREFIX"`                    // Key prefix of every object
+		ROLL_SIZE_MB  int           `env-required:"true"  yaml:"ROLL_SIZE_MB"  env:"ARCHIVE_ROLL_SIZE_MB"`
+		ROLL_INTERVAL time.Duration `env-required:"true"  yaml:"ROLL_INTERVAL"  env:"ARCHIVE_ROLL_INTERVAL"`
+	}
+
+	// S3 -.
+	S3 struct {
+		ENDPOINT   string `yaml:"ENDPOINT"  env:"S3_ENDPOINT"` // host:port
+		BUCKET     string `yaml:"BUCKET"  env:"S3_BUCKET"`
+		REGION     string `yaml:"REGION"  env:"S3_REGION"`
+		ACCESS_KEY string `env:"S3_ACCESS_KEY"` // AWS environment variables or the instance role when empty
+		SECRET_KEY string `env:"S3_SECRET_KEY"`
+		INSECURE   bool   `yaml:"INSECURE"  env:"S3_INSECURE"` // Plain HTTP, e.g. for a local MinIO
+	}
+)
+
+func NewConfig() (*Config, error) {
+	cfg := &Config{}
+
+	err := cleanenv.ReadConfig("./config/config.yml", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
+	err = cleanenv.ReadEnv(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
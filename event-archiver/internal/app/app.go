@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/controller"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/usecase/repo"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+)
+
+const (
+	_consumerGroupID   = "event-archiver-group"
+	_rollCheckInterval = time.Second
+	_flushTimeout      = time.Minute
+)
+
+// Event Archiver (Consumes the event journal and writes Parquet files)
+func Run(cfg *config.Config) {
+	l := logger.New(cfg.Log.Level)
+
+	// Kafka topics
+	if cfg.Messaging.DRIVER == "kafka" {
+		if err := admin.Ensure(cfg.Kafka.KAFKA_BROKER, Topics(cfg)); err != nil {
+			l.Fatal(fmt.Errorf("app - Run - admin.Ensure: %w", err))
+		}
+	}
+
+	// Create context for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle system signals for shutdown
+	go handleShutdown(cancel, l)
+
+	// Object store
+	store, err := newStore(ctx, cfg)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newStore: %w", err))
+	}
+
+	subscriber, err := newSubscriber(cfg)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newSubscriber: %w", err))
+	}
+
+	// Use case
+	archiver := usecase.NewArchiver(
+		repo.NewArchiveRepo(store, cfg.Archive.PREFIX),
+		cfg.Archive.ROLL_SIZE_MB<<20,
+		cfg.Archive.ROLL_INTERVAL,
+		l,
+	)
+
+	// Resume from the commits of the archive, not from the group offsets
+	resumer, ok := subscriber.(messaging.Resumer)
+	if !ok {
+		l.Fatal(fmt.Errorf("app - Run - %s: %w", cfg.Messaging.DRIVER, errNoResume))
+	}
+	if err := resumer.ResumeFrom(archiver.Resume); err != nil {
+		l.Fatal(fmt.Errorf("app - Run - ResumeFrom: %w", err))
+	}
+
+	go rollDue(ctx, archiver, l)
+
+	eventConsumer := controller.NewEventConsumer(subscriber, archiver, l)
+
+	// Start consuming events
+	l.Info("Starting Event Archiver on %s...", cfg.Kafka.EVENT_TOPIC)
+	eventConsumer.Start(ctx)
+
+	// Commit what is buffered before the subscriber leaves its group
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), _flushTimeout)
+	defer flushCancel()
+	if err := archiver.Flush(flushCtx); err != nil {
+		l.Error(fmt.Errorf("app - Run - Flush: %w", err))
+	}
+	eventConsumer.Close()
+
+	l.Info("Archiver stopped")
+}
+
+// rollDue rolls the partitions whose roll interval elapsed until ctx is done.
+func rollDue(ctx context.Context, archiver usecase.EventArchiver, l logger.Interface) {
+	ticker := time.NewTicker(_rollCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := archiver.RollDue(ctx); err != nil {
+				l.Error(fmt.Errorf("app - rollDue: %w", err))
+			}
+		}
+	}
+}
+
+// handleShutdown gracefully handles shutdown signals.
+func handleShutdown(cancel context.CancelFunc, log logger.Interface) {
+	// Capture system signals
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	<-sigChan
+	log.Info("Shutdown signal received")
+
+	// Cancel context, the consumer loop closes its resources on return
+	cancel()
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+)
+
+// errNoResume is returned for drivers that cannot start partitions at the offsets of
+// the archive. Their group offsets move past events while they are still buffered, so
+// the events buffered at a crash would never be archived.
+var errNoResume = errors.New("the postgres messaging driver cannot resume from the archive, use kafka")
+
+// newSubscriber creates the event-journal subscriber of the configured driver.
+func newSubscriber(cfg *config.Config) (messaging.Subscriber, error) {
+	switch cfg.Messaging.DRIVER {
+	case "kafka":
+		// Events of aborted asset-processor transactions are skipped
+		kafkaConsumer, err := consumer.NewKafkaConsumer(cfg.Kafka.KAFKA_BROKER, _consumerGroupID, cfg.Kafka.EVENT_TOPIC, consumer.WithReadCommitted())
+		if err != nil {
+			return nil, fmt.Errorf("consumer.NewKafkaConsumer: %w", err)
+		}
+		return kafkaConsumer, nil
+	case "postgres":
+		return nil, errNoResume
+	default:
+		return nil, fmt.Errorf("unknown messaging driver: %s", cfg.Messaging.DRIVER)
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/objectstore"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/objectstore/local"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/objectstore/s3"
+)
+
+// newStore creates the object store the archive is written to.
+func newStore(ctx context.Context, cfg *config.Config) (objectstore.Store, error) {
+	switch cfg.Archive.STORE {
+	case "local":
+		store, err := local.New(cfg.Archive.LOCAL_DIR)
+		if err != nil {
+			return nil, fmt.Errorf("local.New: %w", err)
+		}
+		return store, nil
+	case "s3":
+		opts := []s3.Option{s3.Region(cfg.S3.REGION)}
+		if cfg.S3.ACCESS_KEY != "" {
+			opts = append(opts, s3.Credentials(cfg.S3.ACCESS_KEY, cfg.S3.SECRET_KEY))
+		}
+		if cfg.S3.INSECURE {
+			opts = append(opts, s3.Insecure())
+		}
+
+		store, err := s3.New(ctx, cfg.S3.ENDPOINT, cfg.S3.BUCKET, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("s3.New: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown archive store: %s", cfg.Archive.STORE)
+	}
+}
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+)
+
+// Topics declares the Kafka topics the service depends on.
+func Topics(cfg *config.Config) []admin.TopicSpec {
+	return []admin.TopicSpec{
+		{
+			// The event journal is the source of truth, events are never deleted
+			Name:              cfg.Kafka.EVENT_TOPIC,
+			Partitions:        6,
+			ReplicationFactor: cfg.Kafka.REPLICATION_FACTOR,
+			RetentionMs:       admin.RetentionForever,
+			CleanupPolicy:     admin.CleanupDelete,
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+)
+
+const _retryBackoff = time.Second
+
+type EventConsumer struct {
+	reader   messaging.Subscriber
+	archiver usecase.EventArchiver
+	log      logger.Interface
+}
+
+func NewEventConsumer(reader messaging.Subscriber, archiver usecase.EventArchiver, log logger.Interface) *EventConsumer {
+	return &EventConsumer{reader, archiver, log}
+}
+
+// Start consuming events until ctx is done. After a failed roll the events are
+// consumed again after a short backoff, the archiver skips those it buffered.
+func (c *EventConsumer) Start(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := c.reader.Subscribe(ctx, func(ctx context.Context, msg messaging.Message) error {
+			pos := entity.Position{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset}
+			return c.archiver.Archive(ctx, pos, msg.Timestamp, msg.Value)
+		})
+		if err == nil {
+			return
+		}
+
+		c.log.Error(err, "EventConsumer - Start - archive failed")
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(_retryBackoff):
+		}
+	}
+}
+
+// Close the subscriber
+func (c *EventConsumer) Close() {
+	c.reader.Close()
+}
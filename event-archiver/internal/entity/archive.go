@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Position is the place of an event in the event journal.
+type Position struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// ArchiveFile is a Parquet file holding the events of one date and type.
+type ArchiveFile struct {
+	Key  string `json:"key"`
+	Date string `json:"date"` // UTC date of the events, YYYY-MM-DD
+	Type string `json:"type"`
+	Rows int    `json:"rows"`
+	Size int64  `json:"size"`
+}
+
+// ArchiveCommit lists the files written for the offsets FirstOffset through
+// LastOffset of a partition. Only files of a commit are part of the archive.
+type ArchiveCommit struct {
+	Topic       string        `json:"topic"`
+	Partition   int32         `json:"partition"`
+	FirstOffset int64         `json:"first_offset"`
+	LastOffset  int64         `json:"last_offset"`
+	Files       []ArchiveFile `json:"files"`
+	CommittedAt time.Time     `json:"committed_at,omitempty"` // Zero while pending
+}
+
+// NextOffset is the offset to archive after the commit.
+func (c ArchiveCommit) NextOffset() int64 {
+	return c.LastOffset + 1
+}
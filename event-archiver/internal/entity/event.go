@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/events"
+)
+
+// ArchivedEvent is a row of the Parquet files. Zero optional fields are stored
+// as nulls.
+type ArchivedEvent struct {
+	EventID           string    `parquet:"event_id"`
+	CommandID         string    `parquet:"command_id,optional"`
+	WalletID          int64     `parquet:"wallet_id"`
+	TargetWalletID    int64     `parquet:"target_wallet_id,optional"`
+	AssetName         string    `parquet:"asset_name,dict"`
+	Type              string    `parquet:"type,dict"`
+	Amount            float64   `parquet:"amount"`
+	EventTime         time.Time `parquet:"event_time,timestamp(millisecond)"`
+	ExecuteTime       int64     `parquet:"execute_time,optional,timestamp(millisecond)"` // Unix milliseconds
+	ProjectionVersion int32     `parquet:"projection_version,optional"`
+	Metadata          string    `parquet:"metadata,optional"`
+	Partition         int32     `parquet:"kafka_partition"`
+	Offset            int64     `parquet:"kafka_offset"`
+	PublishedAt       time.Time `parquet:"published_at,timestamp(millisecond)"` // Timestamp of the journal message
+}
+
+// NewArchivedEvent builds the row of an event read at pos. Events without a
+// timestamp take the one of their message.
+func NewArchivedEvent(event events.WalletEvent, pos Position, publishedAt time.Time) ArchivedEvent {
+	row := ArchivedEvent{
+		EventID:           event.EventID,
+		CommandID:         event.CommandID,
+		WalletID:          int64(event.WalletID),
+		TargetWalletID:    int64(event.TargetWalletID),
+		AssetName:         event.AssetName,
+		Type:              event.Type,
+		Amount:            event.Amount,
+		EventTime:         time.Unix(event.Timestamp, 0).UTC(),
+		ProjectionVersion: int32(event.ProjectionVersion),
+		Metadata:          event.Metadata,
+		Partition:         pos.Partition,
+		Offset:            pos.Offset,
+		PublishedAt:       publishedAt.UTC(),
+	}
+	if event.Timestamp == 0 {
+		row.EventTime = row.PublishedAt
+	}
+	if event.ExecuteTime != 0 {
+		row.ExecuteTime = time.Unix(event.ExecuteTime, 0).UnixMilli()
+	}
+	return row
+}
@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/events"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// archiver buffers the events of every partition and rolls a partition into
+// one Parquet file per date and event type. A roll is committed in the order
+// pending commit, files, commit, so a roll either lands completely in the
+// archive or is deleted by the next Resume. The last commit holds the next
+// offset of its partition, the consumer resumes from it.
+type archiver struct {
+	repo         ArchiveRepository
+	log          logger.Interface
+	rollSize     int
+	rollInterval time.Duration
+
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionBuffer
+}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// fileKey groups the events of a roll into files.
+type fileKey struct {
+	date      string
+	eventType string
+}
+
+// partitionBuffer holds the events of a partition that are not committed yet.
+type partitionBuffer struct {
+	next   int64 // Offsets below it are committed or buffered
+	first  int64 // Offset of the first buffered event
+	last   int64
+	opened time.Time // When the first event was buffered
+	size   int       // Bytes of the buffered messages
+	rows   int
+	files  map[fileKey][]entity.ArchivedEvent
+}
+
+func NewArchiver(r ArchiveRepository, rollSize int, rollInterval time.Duration, l logger.Interface) EventArchiver {
+	return &archiver{
+		repo:         r,
+		log:          l,
+		rollSize:     rollSize,
+		rollInterval: rollInterval,
+		partitions:   make(map[partitionKey]*partitionBuffer),
+	}
+}
+
+// Archive buffers an event. Messages below the next offset of the partition are
+// delivered again after a failure or a rebalance, they are skipped. Messages
+// that are not events are logged and skipped.
+func (a *archiver) Archive(ctx context.Context, pos entity.Position, publishedAt time.Time, value []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := partitionKey{topic: pos.Topic, partition: pos.Partition}
+	buf := a.partitions[key]
+	if buf == nil {
+		// Drivers that do not resume from the archive
+		var err error
+		if buf, err = a.recover(ctx, key); err != nil {
+			return err
+		}
+	}
+	if pos.Offset < buf.next {
+		return nil
+	}
+	buf.next = pos.Offset + 1
+
+	payload, err := events.DecodePayload(value)
+	if err != nil {
+		a.log.Error("Archiver - Archive - %s[%d]@%d: base64 decode error: %s", pos.Topic, pos.Partition, pos.Offset, err)
+		return nil
+	}
+	var event events.WalletEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		a.log.Error("Archiver - Archive - %s[%d]@%d: JSON unmarshal error: %s", pos.Topic, pos.Partition, pos.Offset, err)
+		return nil
+	}
+	if event.EventID == "" {
+		a.log.Error("Archiver - Archive - %s[%d]@%d: event without event_id", pos.Topic, pos.Partition, pos.Offset)
+		return nil
+	}
+
+	row := entity.NewArchivedEvent(event, pos, publishedAt)
+	if buf.rows == 0 {
+		buf.first, buf.opened = pos.Offset, time.Now()
+	}
+	fk := fileKey{date: row.EventTime.Format(time.DateOnly), eventType: row.Type}
+	buf.files[fk] = append(buf.files[fk], row)
+	buf.last = pos.Offset
+	buf.size += len(value)
+	buf.rows++
+
+	if buf.size < a.rollSize {
+		return nil
+	}
+	return a.roll(ctx, key, buf)
+}
+
+// Resume completes or deletes the pending commit of a partition. A partition
+// still buffered here resumes after its buffer, unless another instance has
+// committed past it meanwhile. A partition without a commit is archived from
+// its first offset.
+func (a *archiver) Resume(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	buf, err := a.recover(ctx, partitionKey{topic: topic, partition: partition})
+	if err != nil {
+		return 0, false, err
+	}
+	return buf.next, true, nil
+}
+
+// RollDue -.
+func (a *archiver) RollDue(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, buf := range a.partitions {
+		if buf.rows == 0 || time.Since(buf.opened) < a.rollInterval {
+			continue
+		}
+		if err := a.roll(ctx, key, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush -.
+func (a *archiver) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, buf := range a.partitions {
+		if buf.rows == 0 {
+			continue
+		}
+		if err := a.roll(ctx, key, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recover brings the archive of a partition to its last commit and returns the
+// buffer to continue with. The caller holds a.mu.
+func (a *archiver) recover(ctx context.Context, key partitionKey) (*partitionBuffer, error) {
+	last, err := a.repo.LastCommit(ctx, key.topic, key.partition)
+	if err != nil {
+		return nil, fmt.Errorf("Archiver - recover - LastCommit: %w", err)
+	}
+
+	pending, err := a.repo.GetPending(ctx, key.topic, key.partition)
+	if err != nil {
+		return nil, fmt.Errorf("Archiver - recover - GetPending: %w", err)
+	}
+	if pending != nil {
+		// The commit object is written before latest.json
+		committed, err := a.repo.GetCommit(ctx, key.topic, key.partition, pending.NextOffset())
+		if err != nil {
+			return nil, fmt.Errorf("Archiver - recover - GetCommit: %w", err)
+		}
+
+		if committed != nil {
+			if err := a.repo.PutCommit(ctx, *committed); err != nil {
+				return nil, fmt.Errorf("Archiver - recover - PutCommit: %w", err)
+			}
+			last = committed
+		} else if err := a.repo.DeleteFiles(ctx, pending.Files); err != nil {
+			return nil, fmt.Errorf("Archiver - recover - DeleteFiles: %w", err)
+		}
+
+		if err := a.repo.DeletePending(ctx, key.topic, key.partition); err != nil {
+			return nil, fmt.Errorf("Archiver - recover - DeletePending: %w", err)
+		}
+	}
+
+	next := int64(0)
+	if last != nil {
+		next = last.NextOffset()
+	}
+
+	if buf := a.partitions[key]; buf != nil && (buf.rows == 0 || buf.first >= next) && buf.next >= next {
+		return buf, nil
+	}
+
+	buf := &partitionBuffer{next: next, files: make(map[fileKey][]entity.ArchivedEvent)}
+	a.partitions[key] = buf
+	return buf, nil
+}
+
+// roll commits the buffer of a partition. A failed roll keeps the buffer, the
+// files it wrote are deleted by the next roll. The caller holds a.mu.
+func (a *archiver) roll(ctx context.Context, key partitionKey, buf *partitionBuffer) error {
+	last, err := a.repo.LastCommit(ctx, key.topic, key.partition)
+	if err != nil {
+		return fmt.Errorf("Archiver - roll - LastCommit: %w", err)
+	}
+	if last != nil && last.NextOffset() > buf.first {
+		a.log.Warn("Archiver - roll - %s[%d] was archived up to %d by another instance, dropping %d buffered events",
+			key.topic, key.partition, last.NextOffset()-1, buf.rows)
+		delete(a.partitions, key)
+		return nil
+	}
+
+	pending, err := a.repo.GetPending(ctx, key.topic, key.partition)
+	if err != nil {
+		return fmt.Errorf("Archiver - roll - GetPending: %w", err)
+	}
+	if pending != nil {
+		if err := a.repo.DeleteFiles(ctx, pending.Files); err != nil {
+			return fmt.Errorf("Archiver - roll - DeleteFiles: %w", err)
+		}
+	}
+
+	fileKeys := make([]fileKey, 0, len(buf.files))
+	for fk := range buf.files {
+		fileKeys = append(fileKeys, fk)
+	}
+	sort.Slice(fileKeys, func(i, j int) bool {
+		if fileKeys[i].date != fileKeys[j].date {
+			return fileKeys[i].date < fileKeys[j].date
+		}
+		return fileKeys[i].eventType < fileKeys[j].eventType
+	})
+
+	commit := entity.ArchiveCommit{Topic: key.topic, Partition: key.partition, FirstOffset: buf.first, LastOffset: buf.last}
+	first := entity.Position{Topic: key.topic, Partition: key.partition, Offset: buf.first}
+	for _, fk := range fileKeys {
+		commit.Files = append(commit.Files, entity.ArchiveFile{
+			Key:  a.repo.FileKey(first, buf.last, fk.date, fk.eventType),
+			Date: fk.date,
+			Type: fk.eventType,
+			Rows: len(buf.files[fk]),
+		})
+	}
+
+	if err := a.repo.PutPending(ctx, commit); err != nil {
+		return fmt.Errorf("Archiver - roll - PutPending: %w", err)
+	}
+	for i, fk := range fileKeys {
+		size, err := a.repo.WriteFile(ctx, commit.Files[i].Key, buf.files[fk])
+		if err != nil {
+			return fmt.Errorf("Archiver - roll - WriteFile: %w", err)
+		}
+		commit.Files[i].Size = size
+	}
+
+	commit.CommittedAt = time.Now().UTC()
+	if err := a.repo.PutCommit(ctx, commit); err != nil {
+		return fmt.Errorf("Archiver - roll - PutCommit: %w", err)
+	}
+	if err := a.repo.DeletePending(ctx, key.topic, key.partition); err != nil {
+		return fmt.Errorf("Archiver - roll - DeletePending: %w", err)
+	}
+
+	a.log.Info("Archived %d events of %s[%d] at offsets %d-%d into %d files",
+		buf.rows, key.topic, key.partition, buf.first, buf.last, len(commit.Files))
+
+	buf.files = make(map[fileKey][]entity.ArchivedEvent)
+	buf.size, buf.rows = 0, 0
+	return nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/entity"
+)
+
+type (
+	// EventArchiver writes the event journal into Parquet files.
+	EventArchiver interface {
+		// Archive buffers the event of a journal message, rolling its partition when the buffer is full
+		Archive(ctx context.Context, pos entity.Position, publishedAt time.Time, value []byte) error
+
+		// Resume recovers the archive of a partition and returns the next offset to archive
+		Resume(ctx context.Context, topic string, partition int32) (int64, bool, error)
+
+		// RollDue rolls the partitions whose oldest buffered event is older than the roll interval
+		RollDue(ctx context.Context) error
+
+		// Flush rolls every partition, on shutdown
+		Flush(ctx context.Context) error
+	}
+
+	// ArchiveRepository stores the files and commits of the archive.
+	ArchiveRepository interface {
+		// FileKey names the file of a date and event type for the offsets first through last of a partition
+		FileKey(pos entity.Position, last int64, date, eventType string) string
+
+		// WriteFile writes rows as a Parquet file and returns its size
+		WriteFile(ctx context.Context, key string, rows []entity.ArchivedEvent) (int64, error)
+
+		// DeleteFiles removes files of a commit that was not completed
+		DeleteFiles(ctx context.Context, files []entity.ArchiveFile) error
+
+		// GetPending retrieves the commit being written for a partition, nil if there is none
+		GetPending(ctx context.Context, topic string, partition int32) (*entity.ArchiveCommit, error)
+
+		// PutPending records the files of a commit before they are written
+		PutPending(ctx context.Context, commit entity.ArchiveCommit) error
+
+		// DeletePending removes the pending commit of a partition
+		DeletePending(ctx context.Context, topic string, partition int32) error
+
+		// GetCommit retrieves the commit of a partition that ends before nextOffset, nil if there is none
+		GetCommit(ctx context.Context, topic string, partition int32, nextOffset int64) (*entity.ArchiveCommit, error)
+
+		// PutCommit stores a commit and makes it the last one of its partition
+		PutCommit(ctx context.Context, commit entity.ArchiveCommit) error
+
+		// LastCommit retrieves the last commit of a partition, nil if there is none
+		LastCommit(ctx context.Context, topic string, partition int32) (*entity.ArchiveCommit, error)
+	}
+)
@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/event-archiver/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/objectstore"
+	"github.com/parquet-go/parquet-go"
+)
+
+// ArchiveRepo keeps the archive in an object store:
+//
+//	events/date=YYYY-MM-DD/type=TYPE/TOPIC-pPARTITION-FIRST-LAST.parquet
+//	_manifest/TOPIC/partition=PARTITION/NEXT.json    a commit, NEXT is its next offset
+//	_manifest/TOPIC/partition=PARTITION/latest.json  a copy of the last commit
+//	_manifest/TOPIC/partition=PARTITION/pending.json the commit being written
+type ArchiveRepo struct {
+	store  objectstore.Store
+	prefix string
+}
+
+// NewArchiveRepo -.
+func NewArchiveRepo(store objectstore.Store, prefix string) *ArchiveRepo {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &ArchiveRepo{store: store, prefix: prefix}
+}
+
+// FileKey - Offsets are zero-padded, so the files of a directory sort in journal
+// order per partition.
+func (r *ArchiveRepo) FileKey(pos entity.Position, last int64, date, eventType string) string {
+	return fmt.Sprintf("%sevents/date=%s/type=%s/%s-p%d-%020d-%020d.parquet",
+		r.prefix, date, pathSegment(eventType), pathSegment(pos.Topic), pos.Partition, pos.Offset, last)
+}
+
+// WriteFile - Rows are zstd compressed. The file is built in memory, its size is
+// bounded by the roll size.
+func (r *ArchiveRepo) WriteFile(ctx context.Context, key string, rows []entity.ArchivedEvent) (int64, error) {
+	var buf bytes.Buffer
+
+	w := parquet.NewGenericWriter[entity.ArchivedEvent](&buf,
+		parquet.Compression(&parquet.Zstd),
+		parquet.CreatedBy("event-archiver", "", ""))
+	if _, err := w.Write(rows); err != nil {
+		return 0, fmt.Errorf("ArchiveRepo - WriteFile - Write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return 0, fmt.Errorf("ArchiveRepo - WriteFile - Close: %w", err)
+	}
+
+	size := int64(buf.Len())
+	if err := r.store.Put(ctx, key, &buf, size); err != nil {
+		return 0, fmt.Errorf("ArchiveRepo - WriteFile - Put: %w", err)
+	}
+
+	return size, nil
+}
+
+// DeleteFiles -.
+func (r *ArchiveRepo) DeleteFiles(ctx context.Context, files []entity.ArchiveFile) error {
+	for _, f := range files {
+		if err := r.store.Delete(ctx, f.Key); err != nil {
+			return fmt.Errorf("ArchiveRepo - DeleteFiles: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetPending -.
+func (r *ArchiveRepo) GetPending(ctx context.Context, topic string, partition int32) (*entity.ArchiveCommit, error) {
+	commit, err := r.get(ctx, r.manifestKey(topic, partition, "pending.json"))
+	if err != nil {
+		return nil, fmt.Errorf("ArchiveRepo - GetPending: %w", err)
+	}
+	return commit, nil
+}
+
+// PutPending -.
+func (r *ArchiveRepo) PutPending(ctx context.Context, commit entity.ArchiveCommit) error {
+	if err := r.put(ctx, r.manifestKey(commit.Topic, commit.Partition, "pending.json"), commit); err != nil {
+		return fmt.Errorf("ArchiveRepo - PutPending: %w", err)
+	}
+	return nil
+}
+
+// DeletePending -.
+func (r *ArchiveRepo) DeletePending(ctx context.Context, topic string, partition int32) error {
+	if err := r.store.Delete(ctx, r.manifestKey(topic, partition, "pending.json")); err != nil {
+		return fmt.Errorf("ArchiveRepo - DeletePending: %w", err)
+	}
+	return nil
+}
+
+// GetCommit -.
+func (r *ArchiveRepo) GetCommit(ctx context.Context, topic string, partition int32, nextOffset int64) (*entity.ArchiveCommit, error) {
+	commit, err := r.get(ctx, r.manifestKey(topic, partition, commitName(nextOffset)))
+	if err != nil {
+		return nil, fmt.Errorf("ArchiveRepo - GetCommit: %w", err)
+	}
+	return commit, nil
+}
+
+// PutCommit - The commit object is written first. A crash before latest.json is
+// written leaves the pending commit, which the next Resume completes.
+func (r *ArchiveRepo) PutCommit(ctx context.Context, commit entity.ArchiveCommit) error {
+	if err := r.put(ctx, r.manifestKey(commit.Topic, commit.Partition, commitName(commit.NextOffset())), commit); err != nil {
+		return fmt.Errorf("ArchiveRepo - PutCommit - commit: %w", err)
+	}
+	if err := r.put(ctx, r.manifestKey(commit.Topic, commit.Partition, "latest.json"), commit); err != nil {
+		return fmt.Errorf("ArchiveRepo - PutCommit - latest: %w", err)
+	}
+	return nil
+}
+
+// LastCommit -.
+func (r *ArchiveRepo) LastCommit(ctx context.Context, topic string, partition int32) (*entity.ArchiveCommit, error) {
+	commit, err := r.get(ctx, r.manifestKey(topic, partition, "latest.json"))
+	if err != nil {
+		return nil, fmt.Errorf("ArchiveRepo - LastCommit: %w", err)
+	}
+	return commit, nil
+}
+
+func (r *ArchiveRepo) manifestKey(topic string, partition int32, name string) string {
+	return fmt.Sprintf("%s_manifest/%s/partition=%d/%s", r.prefix, pathSegment(topic), partition, name)
+}
+
+// get reads the commit at key, nil if there is none.
+func (r *ArchiveRepo) get(ctx context.Context, key string) (*entity.ArchiveCommit, error) {
+	obj, err := r.store.Get(ctx, key)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var commit entity.ArchiveCommit
+	if err := json.Unmarshal(data, &commit); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return &commit, nil
+}
+
+func (r *ArchiveRepo) put(ctx context.Context, key string, commit entity.ArchiveCommit) error {
+	data, err := json.MarshalIndent(commit, "", "  ")
+	if err != nil {
+		return err
+	}
+	return r.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)))
+}
+
+func commitName(nextOffset int64) string {
+	return fmt.Sprintf("%020d.json", nextOffset)
+}
+
+// pathSegment replaces the characters of a key segment that are not letters,
+// digits, '-' or '_'.
+func pathSegment(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type (
+	// Config -.
+	Config struct {
+		App       `yaml:"app"`
+		HTTP      `yaml:"http"`
+		Log       `yaml:"logger"`
+		PG        `yaml:"postgres"`
+		Kafka     `yaml:"kafka"`
+		Messaging `yaml:"messaging"`
+		Notify    `yaml:"notify"`
+		SMTP      `yaml:"smtp"`
+		Message   `yaml:"message"`
+	}
+
+	// App -.
+	App struct {
+		Name    string `env-required:"true" yaml:"name"    env:"APP_NAME"`
+		Version string `env-required:"true" yaml:"version" env:"APP_VERSION"`
+	}
+
+	// HTTP -.
+	HTTP struct {
+		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+	}
+
+	// Log -.
+	Log struct {
+		Level string `env-required:"true" yaml:"log_level"   env:"LOG_LEVEL"`
+	}
+
+	// PG -.
+	PG struct {
+		PoolMax int    `env-required:"true" yaml:"pool_max" env:"PG_POOL_MAX"`
+		URL     string `env-required:"true"                 env:"PG_URL"`
+	}
+
+	Kafka struct {
+		KAFKA_BROKER       string `env-required:"true"  yaml:"KAFKA_BROKER"  env:"KAFKA_BROKER"`
+		EVENT_TOPIC        string `env-required:"true"  yaml:"EVENT_TOPIC"  env:"EVENT_TOPIC"`
+		REPLICATION_FACTOR int    `env-required:"true"  yaml:"REPLICATION_FACTOR"  env:"KAFKA_REPLICATION_FACTOR"`
+	}
+
+	// Messaging -.
+	Messaging struct {
+		DRIVER string `env-required:"true"  yaml:"DRIVER"  env:"MESSAGING_DRIVER"` // "kafka" or "postgres"
+		PG_URL string `yaml:"PG_URL"  env:"MESSAGING_PG_URL"`                      // Queue database of the "postgres" driver
+	}
+
+	// Notify -.
+	Notify struct {
+		WORKERS       int           `env-required:"true"  yaml:"WORKERS"  env:"NOTIFY_WORKERS"`             // Notifications sent at once
+		POLL_INTERVAL time.Duration `env-required:"true"  yaml:"POLL_INTERVAL"  env:"NOTIFY_POLL_INTERVAL"` // Between looks for due notifications
+		MAX_ATTEMPTS  int           `env-required:"true"  yaml:"MAX_ATTEMPTS"  env:"NOTIFY_MAX_ATTEMPTS"`
+		MIN_BACKOFF   time.Duration `env-required:"true"  yaml:"MIN_BACKOFF"  env:"NOTIFY_MIN_BACKOFF"`
+		MAX_BACKOFF   time.Duration `env-required:"true"  yaml:"MAX_BACKOFF"  env:"NOTIFY_MAX_BACKOFF"`
+		MAX_EVENT_AGE time.Duration `yaml:"MAX_EVENT_AGE"  env:"NOTIFY_MAX_EVENT_AGE"`                      // Older events are not notified, 0 notifies every event
+		UPCOMING_LEAD time.Duration `env-required:"true"  yaml:"UPCOMING_LEAD"  env:"NOTIFY_UPCOMING_LEAD"` // Reminder before a scheduled transfer executes
+		TEMPLATE_DIR  string        `yaml:"TEMPLATE_DIR"  env:"NOTIFY_TEMPLATE_DIR"`                        // Optional <kind>.tmpl files replacing the built-in templates
+	}
+
+	// SMTP -. The email channel is disabled without a host
+	SMTP struct {
+		HOST     string        `yaml:"HOST"  env:"SMTP_HOST"`
+		PORT     int           `yaml:"PORT"  env:"SMTP_PORT"`
+		USERNAME string        `yaml:"USERNAME"  env:"SMTP_USERNAME"` // No authentication when empty
+		PASSWORD string        `yaml:"PASSWORD"  env:"SMTP_PASSWORD"`
+		FROM     string        `yaml:"FROM"  env:"SMTP_FROM"`
+		SECURITY string        `yaml:"SECURITY"  env:"SMTP_SECURITY"` // "none", "starttls" or "tls"
+		TIMEOUT  time.Duration `yaml:"TIMEOUT"  env:"SMTP_TIMEOUT"`
+	}
+
+	// Message -. The message channel is disabled without a URL
+	Message struct {
+		URL     string        `yaml:"URL"  env:"MESSAGE_URL"` // Message sender HTTP API
+		TIMEOUT time.Duration `yaml:"TIMEOUT"  env:"MESSAGE_TIMEOUT"`
+	}
+)
+
+func NewConfig() (*Config, error) {
+	cfg := &Config{}
+
+	err := cleanenv.ReadConfig("./config/config.yml", cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
+	err = cleanenv.ReadEnv(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/controller"
+	v1 "github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/controller/http/v1"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/usecase/repo"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/usecase/webapi"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/httpserver"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/admin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const (
+	_consumerGroupID = "notification-service-group"
+	_batchPerWorker  = 10               // Notifications claimed per worker at once
+	_leaseMargin     = 30 * time.Second // Added to the send timeout
+)
+
+// Notification Service (Serves preferences and the inbox, consumes the event journal and sends notifications)
+func Run(cfg *config.Config) {
+	l := logger.New(cfg.Log.Level)
+
+	// Kafka topics
+	if cfg.Messaging.DRIVER == "kafka" {
+		if err := admin.Ensure(cfg.Kafka.KAFKA_BROKER, Topics(cfg)); err != nil {
+			l.Fatal(fmt.Errorf("app - Run - admin.Ensure: %w", err))
+		}
+	}
+
+	// Repository
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - postgres.New: %w", err))
+	}
+	defer pg.Close()
+
+	notificationRepo := repo.NewNotificationRepo(pg)
+
+	// Channels
+	senders, err := newChannelSenders(cfg)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newChannelSenders: %w", err))
+	}
+	channels := make([]string, 0, len(senders))
+	for _, channel := range []string{entity.ChannelInbox, entity.ChannelEmail, entity.ChannelMessage} {
+		if _, ok := senders[channel]; ok {
+			channels = append(channels, channel)
+		} else {
+			l.Warn("Notification channel %s is not configured, its notifications are not queued", channel)
+		}
+	}
+
+	templates, err := usecase.NewMessageTemplates(cfg.Notify.TEMPLATE_DIR)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - usecase.NewMessageTemplates: %w", err))
+	}
+
+	// Use cases
+	notificationUseCase := usecase.NewNotificationUseCase(notificationRepo, l)
+	notifyUseCase := usecase.NewNotifyUseCase(notificationRepo, templates, usecase.NotifyPolicy{
+		Channels:     channels,
+		MaxEventAge:  cfg.Notify.MAX_EVENT_AGE,
+		UpcomingLead: cfg.Notify.UPCOMING_LEAD,
+	}, l)
+	dispatchUseCase := usecase.NewDispatchUseCase(notificationRepo, senders, usecase.DispatchPolicy{
+		Workers:     cfg.Notify.WORKERS,
+		BatchSize:   cfg.Notify.WORKERS * _batchPerWorker,
+		Lease:       max(cfg.SMTP.TIMEOUT, cfg.Message.TIMEOUT) + _leaseMargin,
+		MaxAttempts: cfg.Notify.MAX_ATTEMPTS,
+		MinBackoff:  cfg.Notify.MIN_BACKOFF,
+		MaxBackoff:  cfg.Notify.MAX_BACKOFF,
+	}, l)
+
+	// HTTP Server
+	handler := gin.New()
+	v1.NewRouter(handler, l, notificationUseCase)
+	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Event consumer
+	subscriber, err := newSubscriber(cfg)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newSubscriber: %w", err))
+	}
+	eventConsumer := controller.NewEventConsumer(subscriber, notifyUseCase, l)
+
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		l.Info("Consuming %s for notifications...", cfg.Kafka.EVENT_TOPIC)
+		eventConsumer.Start(ctx)
+	}()
+
+	// Dispatcher
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		dispatch(ctx, dispatchUseCase, cfg.Notify.POLL_INTERVAL, l)
+	}()
+
+	// Waiting signal
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case s := <-interrupt:
+		l.Info("app - Run - signal: " + s.String())
+	case err = <-httpServer.Notify():
+		l.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
+	}
+
+	// Shutdown, notifications on their way are completed and recorded
+	cancel()
+	<-consumerDone
+	eventConsumer.Close()
+	<-dispatcherDone
+
+	err = httpServer.Shutdown()
+	if err != nil {
+		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
+	}
+}
+
+// newChannelSenders creates the senders of the configured channels. The inbox is always
+// available; email needs an SMTP host and messages a message sender URL.
+func newChannelSenders(cfg *config.Config) (map[string]usecase.ChannelSender, error) {
+	senders := map[string]usecase.ChannelSender{entity.ChannelInbox: usecase.InboxChannel{}}
+
+	if cfg.SMTP.HOST != "" {
+		smtpSender, err := webapi.NewSMTPSenderWebAPI(webapi.SMTPConfig{
+			Host:     cfg.SMTP.HOST,
+			Port:     cfg.SMTP.PORT,
+			Username: cfg.SMTP.USERNAME,
+			Password: cfg.SMTP.PASSWORD,
+			From:     cfg.SMTP.FROM,
+			Security: cfg.SMTP.SECURITY,
+			Timeout:  cfg.SMTP.TIMEOUT,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("webapi.NewSMTPSenderWebAPI: %w", err)
+		}
+		senders[entity.ChannelEmail] = smtpSender
+	}
+
+	if cfg.Message.URL != "" {
+		senders[entity.ChannelMessage] = webapi.NewMessageSenderWebAPI(cfg.Message.URL, cfg.Message.TIMEOUT)
+	}
+
+	return senders, nil
+}
+
+// dispatch sends the due notifications every interval until ctx is done, batch after batch until
+// none is due.
+func dispatch(ctx context.Context, dispatcher usecase.NotificationDispatcher, interval time.Duration, l logger.Interface) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			sent, err := dispatcher.DispatchDue(ctx)
+			if err != nil {
+				l.Error(fmt.Errorf("app - dispatch: %w", err))
+				break
+			}
+			if sent == 0 {
+				break
+			}
+		}
+	}
+}
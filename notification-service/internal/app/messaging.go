@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/config"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/kafka/consumer"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging/pgqueue"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const _queuePoolSize = 2 // A subscriber holds two connections
+
+// newSubscriber creates the event-journal subscriber of the configured driver.
+func newSubscriber(cfg *config.Config) (messaging.Subscriber, error) {
+	switch cfg.Messaging.DRIVER {
+	case "kafka":
+		// Events of aborted asset-processor transactions are skipped. A new consumer group starts at
+		// the end of the journal, so the history is not notified.
+		kafkaConsumer, err := consumer.NewKafkaConsumer(cfg.Kafka.KAFKA_BROKER, _consumerGroupID, cfg.Kafka.EVENT_TOPIC,
+			consumer.WithReadCommitted(), consumer.WithAutoOffsetReset("latest"))
+		if err != nil {
+			return nil, fmt.Errorf("consumer.NewKafkaConsumer: %w", err)
+		}
+		return kafkaConsumer, nil
+	case "postgres":
+		pg, err := postgres.New(cfg.Messaging.PG_URL, postgres.MaxPoolSize(_queuePoolSize))
+		if err != nil {
+			return nil, fmt.Errorf("postgres.New: %w", err)
+		}
+		queue, err := pgqueue.New(pg)
+		if err != nil {
+			pg.Close()
+			return nil, fmt.Errorf("pgqueue.New: %w", err)
+		}
+		return queue.Subscriber(_consumerGroupID, cfg.Kafka.EVENT_TOPIC), nil
+	default:
+		return nil, fmt.Errorf("unknown messaging driver: %s", cfg.Messaging.DRIVER)
+	}
+}
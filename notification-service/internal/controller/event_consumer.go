@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/messaging"
+)
+
+const _retryBackoff = time.Second
+
+type EventConsumer struct {
+	reader   messaging.Subscriber
+	notifier usecase.EventNotifier
+	log      logger.Interface
+}
+
+func NewEventConsumer(reader messaging.Subscriber, notifier usecase.EventNotifier, log logger.Interface) *EventConsumer {
+	return &EventConsumer{reader, notifier, log}
+}
+
+// Start consuming events until ctx is done. An event whose notifications could
+// not be queued is consumed again after a short backoff, notifications queued
+// before are kept.
+func (c *EventConsumer) Start(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := c.reader.Subscribe(ctx, func(ctx context.Context, msg messaging.Message) error {
+			return c.notifier.Notify(ctx, msg.Value)
+		})
+		if err == nil {
+			return
+		}
+
+		c.log.Error(err, "EventConsumer - Start - event failed")
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(_retryBackoff):
+		}
+	}
+}
+
+// Close the subscriber
+func (c *EventConsumer) Close() {
+	c.reader.Close()
+}
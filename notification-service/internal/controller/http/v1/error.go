@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type response struct {
+	Error string `json:"error" example:"detail"`
+}
+
+func errorResponse(c *gin.Context, code int, msg string) {
+	c.AbortWithStatusJSON(code, response{msg})
+}
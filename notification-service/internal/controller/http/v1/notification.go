@@ -0,0 +1,324 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/usecase"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+type notificationRoutes struct {
+	t usecase.NotificationHandler
+	l logger.Interface
+}
+
+func newNotificationRoutes(handler *gin.RouterGroup, t usecase.NotificationHandler, l logger.Interface) {
+	r := &notificationRoutes{t, l}
+
+	h := handler.Group("/wallets/:id")
+	{
+		h.GET("/notification-preferences", r.GetPreferences)   // Channels of each notification kind
+		h.PUT("/notification-preferences", r.SetPreferences)   // Replace the preferences
+		h.GET("/notifications", r.ListNotifications)           // Notifications on every channel
+		h.GET("/inbox", r.ListInbox)                           // In-app inbox with the unread count
+		h.POST("/inbox/read-all", r.MarkAllRead)               // Mark the whole inbox read
+		h.POST("/inbox/:notification_id/read", r.MarkRead)     // Mark an inbox notification read
+		h.POST("/inbox/:notification_id/unread", r.MarkUnread) // Mark an inbox notification unread
+	}
+}
+
+type notificationsResponse struct {
+	Notifications []entity.Notification `json:"notifications"`
+	NextCursor    string                `json:"next_cursor,omitempty"` // Pass as cursor to get the next page
+}
+
+type inboxResponse struct {
+	Notifications []entity.Notification `json:"notifications"`
+	UnreadCount   int                   `json:"unread_count"`
+	NextCursor    string                `json:"next_cursor,omitempty"` // Pass as cursor to get the next page
+}
+
+type markAllReadResponse struct {
+	Marked int `json:"marked"` // Notifications that were unread
+}
+
+// @Summary     Retrieve notification preferences
+// @Description Get the channels each notification kind of a wallet is sent through. A wallet that never set its preferences keeps every kind in its inbox and is sent no email or message.
+// @ID          get-preferences
+// @Tags        preferences
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Success     200 {object} entity.Preferences
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/notification-preferences [get]
+func (r *notificationRoutes) GetPreferences(c *gin.Context) {
+	walletID, ok := walletParam(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := r.t.GetPreferences(c.Request.Context(), walletID)
+	if err != nil {
+		r.useCaseError(c, err, "GetPreferences", "Failed to retrieve preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// @Summary     Set notification preferences
+// @Description Replace the preferences of a wallet. Each channel lists the notification kinds it sends: withdraw, deposit, transfer, balance_correction, transfer_scheduled, transfer_upcoming, the reminder before a scheduled transfer executes, and alert, fired by an alert rule of the wallet. Kinds left out of a channel are not sent through it; email and message_to are required to send email and messages.
+// @ID          set-preferences
+// @Tags        preferences
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Param       preferences body entity.PreferencesRequest true "Preferences"
+// @Success     200 {object} entity.Preferences
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/notification-preferences [put]
+func (r *notificationRoutes) SetPreferences(c *gin.Context) {
+	walletID, ok := walletParam(c)
+	if !ok {
+		return
+	}
+
+	var req entity.PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - SetPreferences - invalid input")
+		errorResponse(c, http.StatusBadRequest, "Invalid input")
+		return
+	}
+
+	preferences, err := r.t.SetPreferences(c.Request.Context(), walletID, req)
+	if err != nil {
+		r.useCaseError(c, err, "SetPreferences", "Failed to set preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// @Summary     List the notifications of a wallet
+// @Description Get the notifications of a wallet on every channel, newest first, with their delivery status, in pages of limit (default 50, max 500). Pass the returned next_cursor as cursor to get the next page.
+// @ID          list-notifications
+// @Tags        notifications
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Param       channel query string false "Only notifications of this channel" Enums(email, message, inbox)
+// @Param       status query string false "Only notifications with this status" Enums(pending, sent, failed, cancelled)
+// @Param       limit query int false "Notifications per page"
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Success     200 {object} notificationsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/notifications [get]
+func (r *notificationRoutes) ListNotifications(c *gin.Context) {
+	walletID, ok := walletParam(c)
+	if !ok {
+		return
+	}
+
+	filter := entity.NotificationFilter{WalletID: walletID, Channel: c.Query("channel"), Status: c.Query("status")}
+	switch filter.Channel {
+	case "", entity.ChannelEmail, entity.ChannelMessage, entity.ChannelInbox:
+	default:
+		errorResponse(c, http.StatusBadRequest, "Invalid channel")
+		return
+	}
+	switch filter.Status {
+	case "", entity.NotificationPending, entity.NotificationSent, entity.NotificationFailed, entity.NotificationCancelled:
+	default:
+		errorResponse(c, http.StatusBadRequest, "Invalid status")
+		return
+	}
+	if filter.Limit, ok = limitParam(c); !ok {
+		return
+	}
+
+	page, err := r.t.ListNotifications(c.Request.Context(), filter, c.Query("cursor"))
+	if err != nil {
+		r.useCaseError(c, err, "ListNotifications", "Failed to retrieve notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, notificationsResponse{Notifications: page.Notifications, NextCursor: page.NextCursor})
+}
+
+// @Summary     Retrieve the inbox of a wallet
+// @Description Get the in-app notifications of a wallet, newest first, with the number of unread ones. Reminders show up once they are due. Pages work like the notification list.
+// @ID          list-inbox
+// @Tags        inbox
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Param       unread query bool false "Only unread notifications"
+// @Param       limit query int false "Notifications per page"
+// @Param       cursor query string false "next_cursor of the previous page"
+// @Success     200 {object} inboxResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/inbox [get]
+func (r *notificationRoutes) ListInbox(c *gin.Context) {
+	walletID, ok := walletParam(c)
+	if !ok {
+		return
+	}
+
+	unread := false
+	if value := c.Query("unread"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "Invalid unread")
+			return
+		}
+		unread = parsed
+	}
+	limit, ok := limitParam(c)
+	if !ok {
+		return
+	}
+
+	page, err := r.t.ListInbox(c.Request.Context(), walletID, unread, limit, c.Query("cursor"))
+	if err != nil {
+		r.useCaseError(c, err, "ListInbox", "Failed to retrieve inbox")
+		return
+	}
+
+	count, err := r.t.UnreadCount(c.Request.Context(), walletID)
+	if err != nil {
+		r.useCaseError(c, err, "ListInbox", "Failed to retrieve inbox")
+		return
+	}
+
+	c.JSON(http.StatusOK, inboxResponse{Notifications: page.Notifications, UnreadCount: count, NextCursor: page.NextCursor})
+}
+
+// @Summary     Mark the inbox read
+// @Description Mark every unread inbox notification of a wallet read
+// @ID          mark-all-read
+// @Tags        inbox
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Success     200 {object} markAllReadResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/inbox/read-all [post]
+func (r *notificationRoutes) MarkAllRead(c *gin.Context) {
+	walletID, ok := walletParam(c)
+	if !ok {
+		return
+	}
+
+	marked, err := r.t.MarkAllRead(c.Request.Context(), walletID)
+	if err != nil {
+		r.useCaseError(c, err, "MarkAllRead", "Failed to mark inbox read")
+		return
+	}
+
+	c.JSON(http.StatusOK, markAllReadResponse{Marked: marked})
+}
+
+// @Summary     Mark an inbox notification read
+// @Description Mark an inbox notification of a wallet read. A notification read before keeps its read_at.
+// @ID          mark-read
+// @Tags        inbox
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Param       notification_id path int true "Notification ID"
+// @Success     200 {object} entity.Notification
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/inbox/{notification_id}/read [post]
+func (r *notificationRoutes) MarkRead(c *gin.Context) {
+	r.markRead(c, true)
+}
+
+// @Summary     Mark an inbox notification unread
+// @Description Mark an inbox notification of a wallet unread again
+// @ID          mark-unread
+// @Tags        inbox
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Wallet ID"
+// @Param       notification_id path int true "Notification ID"
+// @Success     200 {object} entity.Notification
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /wallets/{id}/inbox/{notification_id}/unread [post]
+func (r *notificationRoutes) MarkUnread(c *gin.Context) {
+	r.markRead(c, false)
+}
+
+func (r *notificationRoutes) markRead(c *gin.Context, read bool) {
+	walletID, ok := walletParam(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("notification_id"), 10, 64)
+	if err != nil || id <= 0 {
+		errorResponse(c, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	notification, err := r.t.MarkRead(c.Request.Context(), walletID, id, read)
+	if err != nil {
+		r.useCaseError(c, err, "MarkRead", "Failed to mark notification")
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// useCaseError answers the error of a use case: 404 for a missing notification, 400 for invalid
+// preferences or cursor and 500 otherwise.
+func (r *notificationRoutes) useCaseError(c *gin.Context, err error, method, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		errorResponse(c, http.StatusNotFound, "Not found")
+	case errors.Is(err, usecase.ErrInvalidPreferences):
+		errorResponse(c, http.StatusBadRequest, errors.Unwrap(err).Error())
+	case errors.Is(err, usecase.ErrInvalidCursor):
+		errorResponse(c, http.StatusBadRequest, "Invalid cursor")
+	default:
+		r.l.Error(err, "http - v1 - "+method)
+		errorResponse(c, http.StatusInternalServerError, message)
+	}
+}
+
+// walletParam reads the wallet id path parameter, answering 400 when it is not a positive integer.
+func walletParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		errorResponse(c, http.StatusBadRequest, "Invalid wallet ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// limitParam reads the optional limit query parameter, answering 400 when it is not a positive
+// integer.
+func limitParam(c *gin.Context) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		errorResponse(c, http.StatusBadRequest, "Invalid limit")
+		return 0, false
+	}
+	return limit, true
+}
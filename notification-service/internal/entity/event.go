@@ -0,0 +1,11 @@
+package entity
+
+// AlertDetails are the metadata of an alert event, fired by an alert rule of asset-query-service.
+type AlertDetails struct {
+	RuleID         int64   `json:"rule_id"`
+	Condition      string  `json:"condition"` // "balance_below" or "movement_above"
+	Threshold      float64 `json:"threshold"`
+	Balance        float64 `json:"balance"`          // Balance of the wallet asset after the movement
+	TriggerEventID string  `json:"trigger_event_id"` // Event of the movement
+	Description    string  `json:"description,omitempty"`
+}
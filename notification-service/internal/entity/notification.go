@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Notification kinds, one per event type and the reminder of a scheduled transfer
+const (
+	KindWithdraw          = "withdraw"
+	KindDeposit           = "deposit"
+	KindTransfer          = "transfer"
+	KindBalanceCorrection = "balance_correction"
+	KindTransferScheduled = "transfer_scheduled"
+	KindTransferUpcoming  = "transfer_upcoming" // Sent before a scheduled transfer executes
+	KindAlert             = "alert"             // An alert rule of the wallet fired
+)
+
+// Kinds are the notification kinds a wallet can choose per channel.
+var Kinds = []string{KindWithdraw, KindDeposit, KindTransfer, KindBalanceCorrection, KindTransferScheduled, KindTransferUpcoming, KindAlert}
+
+// Channels
+const (
+	ChannelEmail   = "email"
+	ChannelMessage = "message" // Sent through the message sender HTTP API
+	ChannelInbox   = "inbox"
+)
+
+// Notification statuses
+const (
+	NotificationPending   = "pending"
+	NotificationSent      = "sent"
+	NotificationFailed    = "failed"    // Out of attempts
+	NotificationCancelled = "cancelled" // A reminder of a transfer that already ran
+)
+
+// PreferencesRequest represents the request payload when setting the preferences of a wallet
+type PreferencesRequest struct {
+	Email        string   `json:"email,omitempty" example:"treasury@example.com"` // Required to send email
+	MessageTo    string   `json:"message_to,omitempty" example:"+905551112233"`   // Required to send messages
+	EmailKinds   []string `json:"email_kinds,omitempty" example:"withdraw,transfer_upcoming"`
+	MessageKinds []string `json:"message_kinds,omitempty"`
+	InboxKinds   []string `json:"inbox_kinds,omitempty" example:"withdraw,deposit,transfer"`
+}
+
+// Preferences select the channels each notification kind of a wallet is sent through.
+type Preferences struct {
+	WalletID     int        `json:"wallet_id" example:"1"`
+	Email        string     `json:"email"`
+	MessageTo    string     `json:"message_to"`
+	EmailKinds   []string   `json:"email_kinds"`
+	MessageKinds []string   `json:"message_kinds"`
+	InboxKinds   []string   `json:"inbox_kinds"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"` // Empty while the wallet has the defaults
+}
+
+// DefaultPreferences keeps every kind in the inbox and sends nothing out, for wallets that did not
+// set their preferences.
+func DefaultPreferences(walletID int) Preferences {
+	return Preferences{
+		WalletID:     walletID,
+		EmailKinds:   []string{},
+		MessageKinds: []string{},
+		InboxKinds:   slices.Clone(Kinds),
+	}
+}
+
+// Recipient returns where a notification of kind goes on channel, and whether the wallet wants it
+// there at all.
+func (p Preferences) Recipient(channel, kind string) (string, bool) {
+	switch channel {
+	case ChannelEmail:
+		return p.Email, p.Email != "" && slices.Contains(p.EmailKinds, kind)
+	case ChannelMessage:
+		return p.MessageTo, p.MessageTo != "" && slices.Contains(p.MessageKinds, kind)
+	case ChannelInbox:
+		return "", slices.Contains(p.InboxKinds, kind)
+	default:
+		return "", false
+	}
+}
+
+// Message is a rendered notification.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Notification is a message of a wallet on one channel.
+type Notification struct {
+	ID            int64      `json:"id" example:"1"`
+	WalletID      int        `json:"wallet_id" example:"1"`
+	EventID       string     `json:"event_id"`
+	CommandID     string     `json:"-"`
+	Kind          string     `json:"kind" example:"withdraw"`
+	Channel       string     `json:"channel" enums:"email,message,inbox"`
+	Recipient     string     `json:"recipient,omitempty"`
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status" enums:"pending,sent,failed,cancelled"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"` // Set while pending
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	ReadAt        *time.Time `json:"read_at,omitempty"` // Inbox only, empty while unread
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NotificationFilter selects a page of the notifications of a wallet, newest first.
+type NotificationFilter struct {
+	WalletID int
+	Channel  string // Optional
+	Status   string // Optional
+	Unread   bool
+	Limit    int
+	After    *NotificationCursor // Optional, position of the last notification of the previous page
+}
+
+// NotificationCursor is the ordering key of the last notification of a page.
+type NotificationCursor struct {
+	ID int64 `json:"id"`
+}
+
+// NotificationPage is a page of notifications.
+type NotificationPage struct {
+	Notifications []Notification
+	NextCursor    string // Empty on the last page
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// DispatchPolicy controls how notifications are sent and retried.
+type DispatchPolicy struct {
+	Workers     int           // Notifications sent at once
+	BatchSize   int           // Notifications claimed at once
+	Lease       time.Duration // How long a claimed notification is not claimed again, longer than a send
+	MaxAttempts int           // Attempts before a notification fails
+	MinBackoff  time.Duration // Wait after the first failed attempt, doubled after each one
+	MaxBackoff  time.Duration
+}
+
+// retryAt returns when a notification is sent again after its attempts failed, or zero when it is
+// out of attempts.
+func (p DispatchPolicy) retryAt(attempts int, now time.Time) time.Time {
+	if attempts >= p.MaxAttempts {
+		return time.Time{}
+	}
+	wait := p.MinBackoff << (attempts - 1)
+	if wait <= 0 || wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return now.Add(wait)
+}
+
+// DispatchUseCase sends due notifications through their channels.
+type DispatchUseCase struct {
+	repo     NotificationRepositoryHandler
+	senders  map[string]ChannelSender
+	channels []string
+	policy   DispatchPolicy
+	log      logger.Interface
+}
+
+// NewDispatchUseCase creates a new instance of DispatchUseCase. Notifications of channels without
+// a sender stay pending.
+func NewDispatchUseCase(r NotificationRepositoryHandler, senders map[string]ChannelSender, policy DispatchPolicy, log logger.Interface) *DispatchUseCase {
+	channels := make([]string, 0, len(senders))
+	for channel := range senders {
+		channels = append(channels, channel)
+	}
+	return &DispatchUseCase{repo: r, senders: senders, channels: channels, policy: policy, log: log}
+}
+
+// DispatchDue claims a batch of due notifications and sends them, Workers at a time. Sends that
+// are on their way when ctx is done are completed and recorded.
+func (uc *DispatchUseCase) DispatchDue(ctx context.Context) (int, error) {
+	notifications, err := uc.repo.ClaimDueNotifications(ctx, uc.channels, uc.policy.BatchSize, uc.policy.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("DispatchUseCase - DispatchDue: %w", err)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	sem := make(chan struct{}, uc.policy.Workers)
+	var wg sync.WaitGroup
+	for _, notification := range notifications {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(notification entity.Notification) {
+			defer func() { <-sem; wg.Done() }()
+			uc.send(ctx, notification)
+		}(notification)
+	}
+	wg.Wait()
+
+	return len(notifications), nil
+}
+
+// send sends a notification and records the result. A notification whose result cannot be
+// recorded is sent again when its lease ends.
+func (uc *DispatchUseCase) send(ctx context.Context, notification entity.Notification) {
+	sendErr := uc.senders[notification.Channel].Send(ctx, notification)
+	now := time.Now()
+
+	var retryAt time.Time
+	if sendErr != nil {
+		retryAt = uc.policy.retryAt(notification.Attempts+1, now)
+	}
+
+	if err := uc.repo.RecordResult(ctx, notification.ID, now, sendErr, retryAt); err != nil {
+		uc.log.Error("DispatchUseCase - send - notification %d: %s", notification.ID, err)
+		return
+	}
+
+	switch {
+	case sendErr == nil:
+		uc.log.Debug("Notification %d sent by %s to wallet %d", notification.ID, notification.Channel, notification.WalletID)
+	case retryAt.IsZero():
+		uc.log.Warn("Notification %d failed after %d attempts: %s", notification.ID, notification.Attempts+1, sendErr)
+	default:
+		uc.log.Debug("Notification %d attempt %d failed, retry at %s: %s", notification.ID, notification.Attempts+1,
+			retryAt.Format(time.RFC3339), sendErr)
+	}
+}
+
+// InboxChannel delivers inbox notifications. The inbox is the notifications table itself, so a
+// notification is delivered by being marked sent, which shows it in the inbox.
+type InboxChannel struct{}
+
+// Send -.
+func (InboxChannel) Send(context.Context, entity.Notification) error {
+	return nil
+}
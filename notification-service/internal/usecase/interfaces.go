@@ -0,0 +1,61 @@
+// Package usecase implements application business logic. Each logic group in own file.
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+)
+
+type (
+	/* Notification UseCase Interface */
+	NotificationHandler interface {
+		GetPreferences(ctx context.Context, walletID int) (*entity.Preferences, error)                                            // Preferences of a wallet, the defaults when it has none
+		SetPreferences(ctx context.Context, walletID int, req entity.PreferencesRequest) (*entity.Preferences, error)             // Replace the preferences of a wallet
+		ListNotifications(ctx context.Context, filter entity.NotificationFilter, cursor string) (*entity.NotificationPage, error) // Notifications of a wallet on every channel
+		ListInbox(ctx context.Context, walletID int, unread bool, limit int, cursor string) (*entity.NotificationPage, error)     // Inbox of a wallet
+		UnreadCount(ctx context.Context, walletID int) (int, error)                                                               // Unread inbox notifications of a wallet
+		MarkRead(ctx context.Context, walletID int, id int64, read bool) (*entity.Notification, error)                            // Mark an inbox notification read or unread
+		MarkAllRead(ctx context.Context, walletID int) (int, error)                                                               // Mark the whole inbox of a wallet read
+	}
+
+	/* Event notifier UseCase Interface */
+	EventNotifier interface {
+		Notify(ctx context.Context, value []byte) error // Queue the notifications of an event of the journal
+	}
+
+	/* Dispatcher UseCase Interface */
+	NotificationDispatcher interface {
+		DispatchDue(ctx context.Context) (int, error) // Send a batch of due notifications, returns how many were sent
+	}
+
+	/* Notification Repository Interface */
+	NotificationRepositoryHandler interface {
+		// GetPreferences returns the stored preferences of the wallets, wallets without any are missing.
+		GetPreferences(ctx context.Context, walletIDs []int) (map[int]entity.Preferences, error)
+		SetPreferences(ctx context.Context, preferences entity.Preferences) (*entity.Preferences, error)
+
+		// InsertNotifications queues notifications. A notification queued before for the same event, wallet,
+		// kind and channel is skipped. Returns how many were queued.
+		InsertNotifications(ctx context.Context, notifications []entity.Notification) (int, error)
+		// CancelReminders cancels the pending reminders of a scheduled transfer command.
+		CancelReminders(ctx context.Context, commandID string) (int, error)
+		ListNotifications(ctx context.Context, filter entity.NotificationFilter) ([]entity.Notification, error)
+		CountUnread(ctx context.Context, walletID int) (int, error)
+		MarkRead(ctx context.Context, walletID int, id int64, read bool) (*entity.Notification, error) // nil when the wallet has no such inbox notification
+		MarkAllRead(ctx context.Context, walletID int) (int, error)
+
+		// ClaimDueNotifications leases up to limit due notifications of channels for lease, so they are not
+		// sent twice. A notification whose result is not recorded is due again when the lease ends.
+		ClaimDueNotifications(ctx context.Context, channels []string, limit int, lease time.Duration) ([]entity.Notification, error)
+		// RecordResult marks a notification sent, or counts a failed attempt. A failed notification with a
+		// zero retryAt is out of attempts.
+		RecordResult(ctx context.Context, id int64, at time.Time, sendErr error, retryAt time.Time) error
+	}
+
+	/* Channel Sender WebAPI Interface */
+	ChannelSender interface {
+		Send(ctx context.Context, notification entity.Notification) error // Send a notification to its recipient
+	}
+)
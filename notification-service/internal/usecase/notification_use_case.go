@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+const (
+	_defaultNotificationPage = 50
+	_maxNotificationPage     = 500
+	_maxRecipientLength      = 255
+)
+
+var (
+	// ErrNotFound is returned for an inbox notification the wallet does not have.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidPreferences is returned for preferences that cannot be stored.
+	ErrInvalidPreferences = errors.New("invalid preferences")
+	// ErrInvalidCursor is returned for a page token that was not issued for a notification list.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// NotificationUseCase implements the business logic for preferences and the inbox.
+type NotificationUseCase struct {
+	repo NotificationRepositoryHandler
+	log  logger.Interface
+}
+
+// NewNotificationUseCase creates a new instance of NotificationUseCase.
+func NewNotificationUseCase(r NotificationRepositoryHandler, log logger.Interface) *NotificationUseCase {
+	return &NotificationUseCase{
+		repo: r,
+		log:  log,
+	}
+}
+
+// GetPreferences retrieves the preferences of a wallet, or the defaults when it never set any.
+func (uc *NotificationUseCase) GetPreferences(ctx context.Context, walletID int) (*entity.Preferences, error) {
+	stored, err := uc.repo.GetPreferences(ctx, []int{walletID})
+	if err != nil {
+		return nil, fmt.Errorf("NotificationUseCase - GetPreferences: %w", err)
+	}
+
+	preferences, ok := stored[walletID]
+	if !ok {
+		preferences = entity.DefaultPreferences(walletID)
+	}
+	return &preferences, nil
+}
+
+// SetPreferences replaces the preferences of a wallet. Kinds missing from a channel are not sent
+// through it.
+func (uc *NotificationUseCase) SetPreferences(ctx context.Context, walletID int, req entity.PreferencesRequest) (*entity.Preferences, error) {
+	if err := validatePreferences(&req); err != nil {
+		return nil, fmt.Errorf("NotificationUseCase - SetPreferences: %w", err)
+	}
+
+	preferences, err := uc.repo.SetPreferences(ctx, entity.Preferences{
+		WalletID:     walletID,
+		Email:        req.Email,
+		MessageTo:    req.MessageTo,
+		EmailKinds:   req.EmailKinds,
+		MessageKinds: req.MessageKinds,
+		InboxKinds:   req.InboxKinds,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("NotificationUseCase - SetPreferences: %w", err)
+	}
+
+	uc.log.Info("Notification preferences of wallet %d updated", walletID)
+	return preferences, nil
+}
+
+// ListNotifications retrieves a page of the notifications of a wallet, newest first.
+func (uc *NotificationUseCase) ListNotifications(ctx context.Context, filter entity.NotificationFilter, cursor string) (*entity.NotificationPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = _defaultNotificationPage
+	}
+	filter.Limit = min(filter.Limit, _maxNotificationPage)
+
+	if cursor != "" {
+		after := &entity.NotificationCursor{}
+		if err := decodeCursor(cursor, after); err != nil || after.ID <= 0 {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = after
+	}
+
+	// One extra row tells whether there is a next page
+	pageSize := filter.Limit
+	filter.Limit++
+
+	notifications, err := uc.repo.ListNotifications(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationUseCase - ListNotifications: %w", err)
+	}
+
+	page := &entity.NotificationPage{Notifications: notifications}
+	if len(notifications) > pageSize {
+		page.Notifications = notifications[:pageSize]
+		page.NextCursor = encodeCursor(entity.NotificationCursor{ID: page.Notifications[pageSize-1].ID})
+	}
+
+	return page, nil
+}
+
+// ListInbox retrieves a page of the inbox of a wallet, newest first. Reminders only show up once
+// they are due.
+func (uc *NotificationUseCase) ListInbox(ctx context.Context, walletID int, unread bool, limit int, cursor string) (*entity.NotificationPage, error) {
+	return uc.ListNotifications(ctx, entity.NotificationFilter{
+		WalletID: walletID,
+		Channel:  entity.ChannelInbox,
+		Status:   entity.NotificationSent,
+		Unread:   unread,
+		Limit:    limit,
+	}, cursor)
+}
+
+// UnreadCount counts the unread inbox notifications of a wallet.
+func (uc *NotificationUseCase) UnreadCount(ctx context.Context, walletID int) (int, error) {
+	count, err := uc.repo.CountUnread(ctx, walletID)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationUseCase - UnreadCount: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks an inbox notification of a wallet read, or unread again.
+func (uc *NotificationUseCase) MarkRead(ctx context.Context, walletID int, id int64, read bool) (*entity.Notification, error) {
+	notification, err := uc.repo.MarkRead(ctx, walletID, id, read)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationUseCase - MarkRead: %w", err)
+	}
+	if notification == nil {
+		return nil, fmt.Errorf("NotificationUseCase - MarkRead: notification %d of wallet %d %w", id, walletID, ErrNotFound)
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks every unread inbox notification of a wallet read and returns how many.
+func (uc *NotificationUseCase) MarkAllRead(ctx context.Context, walletID int) (int, error) {
+	count, err := uc.repo.MarkAllRead(ctx, walletID)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationUseCase - MarkAllRead: %w", err)
+	}
+	return count, nil
+}
+
+// validatePreferences checks the recipients and kinds of a request and removes duplicate kinds.
+// The message of the error it returns can be shown to the client.
+func validatePreferences(req *entity.PreferencesRequest) error {
+	if req.Email != "" {
+		address, err := mail.ParseAddress(req.Email)
+		if err != nil || address.Address != req.Email || len(req.Email) > _maxRecipientLength {
+			return fmt.Errorf("%w: invalid email %q", ErrInvalidPreferences, req.Email)
+		}
+	}
+	if len(req.MessageTo) > _maxRecipientLength {
+		return fmt.Errorf("%w: message_to must have at most %d characters", ErrInvalidPreferences, _maxRecipientLength)
+	}
+
+	var err error
+	if req.EmailKinds, err = validateKinds(req.EmailKinds); err != nil {
+		return err
+	}
+	if req.MessageKinds, err = validateKinds(req.MessageKinds); err != nil {
+		return err
+	}
+	if req.InboxKinds, err = validateKinds(req.InboxKinds); err != nil {
+		return err
+	}
+
+	if len(req.EmailKinds) > 0 && req.Email == "" {
+		return fmt.Errorf("%w: email is required with email_kinds", ErrInvalidPreferences)
+	}
+	if len(req.MessageKinds) > 0 && req.MessageTo == "" {
+		return fmt.Errorf("%w: message_to is required with message_kinds", ErrInvalidPreferences)
+	}
+
+	return nil
+}
+
+func validateKinds(kinds []string) ([]string, error) {
+	valid := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		if !slices.Contains(entity.Kinds, kind) {
+			return nil, fmt.Errorf("%w: unknown notification kind %q", ErrInvalidPreferences, kind)
+		}
+		if !slices.Contains(valid, kind) {
+			valid = append(valid, kind)
+		}
+	}
+	return valid, nil
+}
+
+// encodeCursor makes an opaque page token of an ordering key.
+func encodeCursor(cursor any) string {
+	b, _ := json.Marshal(cursor) //nolint:errchkjson // plain struct
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor reads the ordering key of a page token into cursor.
+func decodeCursor(token string, cursor any) error {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, cursor)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/events"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/logger"
+)
+
+// NotifyPolicy controls which events are notified and when reminders are sent.
+type NotifyPolicy struct {
+	Channels     []string      // Channels that can send, the others are skipped
+	MaxEventAge  time.Duration // Older events are not notified, 0 notifies every event
+	UpcomingLead time.Duration // How long before a scheduled transfer executes its reminder is sent
+}
+
+// NotifyUseCase turns the events of the journal into notifications.
+type NotifyUseCase struct {
+	repo      NotificationRepositoryHandler
+	templates *MessageTemplates
+	policy    NotifyPolicy
+	log       logger.Interface
+}
+
+// NewNotifyUseCase creates a new instance of NotifyUseCase.
+func NewNotifyUseCase(r NotificationRepositoryHandler, templates *MessageTemplates, policy NotifyPolicy, log logger.Interface) *NotifyUseCase {
+	return &NotifyUseCase{repo: r, templates: templates, policy: policy, log: log}
+}
+
+// Notify queues the notifications of an event for the wallets it touches, on the channels their
+// preferences select. Messages that are not events are logged and skipped, a repository error is
+// returned so the event is consumed again.
+func (uc *NotifyUseCase) Notify(ctx context.Context, value []byte) error {
+	data, err := events.DecodePayload(value)
+	if err != nil {
+		uc.log.Error("NotifyUseCase - Notify - base64 decode error: %s", err)
+		return nil
+	}
+
+	var event events.WalletEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		uc.log.Error("NotifyUseCase - Notify - JSON unmarshal error: %s", err)
+		return nil
+	}
+	if event.EventID == "" || event.Type == "" {
+		uc.log.Error("NotifyUseCase - Notify - event without event_id or type: %s", data)
+		return nil
+	}
+
+	now := time.Now()
+	occurredAt := now
+	if event.Timestamp != 0 {
+		occurredAt = time.Unix(event.Timestamp, 0)
+	}
+
+	// The transfer of a scheduled command has run, its reminder is no longer upcoming
+	if event.Type == entity.KindTransfer && event.CommandID != "" {
+		if _, err := uc.repo.CancelReminders(ctx, event.CommandID); err != nil {
+			return fmt.Errorf("NotifyUseCase - Notify: %w", err)
+		}
+	}
+
+	if uc.policy.MaxEventAge > 0 && now.Sub(occurredAt) > uc.policy.MaxEventAge {
+		uc.log.Debug("Event %s is older than %s, not notified", event.EventID, uc.policy.MaxEventAge)
+		return nil
+	}
+
+	kinds := uc.kinds(event, now)
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	walletIDs := event.WalletIDs()
+	stored, err := uc.repo.GetPreferences(ctx, walletIDs)
+	if err != nil {
+		return fmt.Errorf("NotifyUseCase - Notify: %w", err)
+	}
+
+	var notifications []entity.Notification
+	for _, walletID := range walletIDs {
+		preferences, ok := stored[walletID]
+		if !ok {
+			preferences = entity.DefaultPreferences(walletID)
+		}
+
+		for kind, dueAt := range kinds {
+			var message *entity.Message
+			for _, channel := range uc.policy.Channels {
+				recipient, ok := preferences.Recipient(channel, kind)
+				if !ok {
+					continue
+				}
+
+				// Rendered once for every channel of the wallet
+				if message == nil {
+					rendered, err := uc.templates.Render(kind, uc.messageData(event, walletID, occurredAt))
+					if err != nil {
+						uc.log.Error("NotifyUseCase - Notify - event %s: %s", event.EventID, err)
+						break
+					}
+					message = &rendered
+				}
+
+				notifications = append(notifications, entity.Notification{
+					WalletID:      walletID,
+					EventID:       event.EventID,
+					CommandID:     event.CommandID,
+					Kind:          kind,
+					Channel:       channel,
+					Recipient:     recipient,
+					Subject:       message.Subject,
+					Body:          message.Body,
+					NextAttemptAt: &dueAt,
+				})
+			}
+		}
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	queued, err := uc.repo.InsertNotifications(ctx, notifications)
+	if err != nil {
+		return fmt.Errorf("NotifyUseCase - Notify: %w", err)
+	}
+	if queued > 0 {
+		uc.log.Debug("Event %s queued %d notifications", event.EventID, queued)
+	}
+
+	return nil
+}
+
+// kinds returns the notification kinds of an event with the time each one is due. A scheduled
+// transfer also gets a reminder before it executes, unless it is about to execute already.
+func (uc *NotifyUseCase) kinds(event events.WalletEvent, now time.Time) map[string]time.Time {
+	if !slices.Contains(entity.Kinds, event.Type) || event.Type == entity.KindTransferUpcoming {
+		return nil
+	}
+
+	kinds := map[string]time.Time{event.Type: now}
+	if event.Type == entity.KindTransferScheduled && event.ExecuteTime != 0 {
+		executeAt := time.Unix(event.ExecuteTime, 0)
+		remindAt := executeAt.Add(-uc.policy.UpcomingLead)
+		if executeAt.After(now) && remindAt.After(now) {
+			kinds[entity.KindTransferUpcoming] = remindAt
+		}
+	}
+	return kinds
+}
+
+// messageData describes an event from the point of view of walletID.
+func (uc *NotifyUseCase) messageData(event events.WalletEvent, walletID int, occurredAt time.Time) MessageData {
+	data := MessageData{
+		WalletID: walletID,
+		EventID:  event.EventID,
+		Asset:    event.AssetName,
+		Amount:   math.Abs(event.Amount),
+		Time:     occurredAt,
+	}
+	if event.ExecuteTime != 0 {
+		data.ExecuteTime = time.Unix(event.ExecuteTime, 0)
+	}
+
+	switch event.Type {
+	case entity.KindDeposit:
+		data.Incoming = true
+	case entity.KindBalanceCorrection:
+		data.Incoming = event.Amount > 0
+	case entity.KindAlert:
+		data.Incoming = event.Amount > 0
+		if err := json.Unmarshal([]byte(event.Metadata), &data.Alert); err != nil {
+			uc.log.Error("NotifyUseCase - Notify - alert %s without rule details: %s", event.EventID, err)
+		}
+	case entity.KindTransfer, entity.KindTransferScheduled:
+		data.Incoming = walletID == event.TargetWalletID && walletID != event.WalletID
+		data.Counterparty = event.TargetWalletID
+		if data.Incoming {
+			data.Counterparty = event.WalletID
+		}
+	}
+	return data
+}
@@ -0,0 +1,310 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/pkg/postgres"
+)
+
+const (
+	_preferencesColumns  = "wallet_id, email, message_to, email_kinds, message_kinds, inbox_kinds, updated_at"
+	_notificationColumns = "n.id, n.wallet_id, n.event_id, COALESCE(n.command_id, ''), n.kind, n.channel, n.recipient, n.subject, n.body, n.status, n.attempts, n.next_attempt_at, COALESCE(n.last_error, ''), n.sent_at, n.read_at, n.created_at"
+)
+
+type NotificationRepo struct {
+	*postgres.Postgres
+}
+
+// NewNotificationRepo creates a new instance of NotificationRepo
+func NewNotificationRepo(pg *postgres.Postgres) *NotificationRepo {
+	return &NotificationRepo{pg}
+}
+
+// GetPreferences retrieves the stored preferences of the wallets
+func (r *NotificationRepo) GetPreferences(ctx context.Context, walletIDs []int) (map[int]entity.Preferences, error) {
+	rows, err := r.Pool.Query(ctx,
+		`SELECT `+_preferencesColumns+` FROM notification_preferences WHERE wallet_id = ANY($1::int[])`, walletIDs)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - GetPreferences - Query: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make(map[int]entity.Preferences, len(walletIDs))
+	for rows.Next() {
+		p, err := scanPreferences(rows)
+		if err != nil {
+			return nil, fmt.Errorf("NotificationRepo - GetPreferences - Scan: %w", err)
+		}
+		preferences[p.WalletID] = *p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("NotificationRepo - GetPreferences - Rows: %w", err)
+	}
+
+	return preferences, nil
+}
+
+// SetPreferences inserts or replaces the preferences of a wallet
+func (r *NotificationRepo) SetPreferences(ctx context.Context, p entity.Preferences) (*entity.Preferences, error) {
+	sql, args, err := r.Builder.
+		Insert("notification_preferences").
+		Columns("wallet_id", "email", "message_to", "email_kinds", "message_kinds", "inbox_kinds").
+		Values(p.WalletID, p.Email, p.MessageTo, kinds(p.EmailKinds), kinds(p.MessageKinds), kinds(p.InboxKinds)).
+		Suffix(`ON CONFLICT (wallet_id) DO UPDATE SET
+			email = EXCLUDED.email, message_to = EXCLUDED.message_to, email_kinds = EXCLUDED.email_kinds,
+			message_kinds = EXCLUDED.message_kinds, inbox_kinds = EXCLUDED.inbox_kinds, updated_at = NOW()
+			RETURNING ` + _preferencesColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - SetPreferences - Builder: %w", err)
+	}
+
+	preferences, err := scanPreferences(r.Pool.QueryRow(ctx, sql, args...))
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - SetPreferences - QueryRow: %w", err)
+	}
+
+	return preferences, nil
+}
+
+// InsertNotifications queues notifications in one statement, skipping the ones queued before, so
+// a redelivered event is notified once
+func (r *NotificationRepo) InsertNotifications(ctx context.Context, notifications []entity.Notification) (int, error) {
+	query := r.Builder.
+		Insert("notifications").
+		Columns("wallet_id", "event_id", "command_id", "kind", "channel", "recipient", "subject", "body", "next_attempt_at").
+		Suffix("ON CONFLICT (event_id, wallet_id, kind, channel) DO NOTHING")
+	for _, n := range notifications {
+		var commandID *string
+		if n.CommandID != "" {
+			commandID = &n.CommandID
+		}
+		query = query.Values(n.WalletID, n.EventID, commandID, n.Kind, n.Channel, n.Recipient, n.Subject, n.Body, n.NextAttemptAt)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("NotificationRepo - InsertNotifications - Builder: %w", err)
+	}
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationRepo - InsertNotifications - Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// CancelReminders cancels the pending reminders of a scheduled transfer command
+func (r *NotificationRepo) CancelReminders(ctx context.Context, commandID string) (int, error) {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE notifications SET status = $4
+		WHERE command_id = $1 AND kind = $2 AND status = $3`,
+		commandID, entity.KindTransferUpcoming, entity.NotificationPending, entity.NotificationCancelled)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationRepo - CancelReminders - Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ListNotifications retrieves a page of the notifications of a wallet, newest first
+func (r *NotificationRepo) ListNotifications(ctx context.Context, filter entity.NotificationFilter) ([]entity.Notification, error) {
+	query := r.Builder.
+		Select(_notificationColumns).
+		From("notifications n").
+		Where("n.wallet_id = ?", filter.WalletID).
+		OrderBy("n.id DESC").
+		Limit(uint64(filter.Limit))
+	if filter.Channel != "" {
+		query = query.Where("n.channel = ?", filter.Channel)
+	}
+	if filter.Status != "" {
+		query = query.Where("n.status = ?", filter.Status)
+	}
+	if filter.Unread {
+		query = query.Where("n.read_at IS NULL")
+	}
+	if filter.After != nil {
+		query = query.Where("n.id < ?", filter.After.ID)
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - ListNotifications - Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - ListNotifications - Query: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]entity.Notification, 0)
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("NotificationRepo - ListNotifications - Scan: %w", err)
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("NotificationRepo - ListNotifications - Rows: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread counts the unread inbox notifications of a wallet
+func (r *NotificationRepo) CountUnread(ctx context.Context, walletID int) (int, error) {
+	var count int
+	err := r.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM notifications
+		WHERE wallet_id = $1 AND channel = $2 AND status = $3 AND read_at IS NULL`,
+		walletID, entity.ChannelInbox, entity.NotificationSent).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationRepo - CountUnread - QueryRow: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead sets or clears the read time of an inbox notification of a wallet, nil when the wallet
+// has no such notification. Marking a read notification read again keeps its read time.
+func (r *NotificationRepo) MarkRead(ctx context.Context, walletID int, id int64, read bool) (*entity.Notification, error) {
+	notification, err := scanNotification(r.Pool.QueryRow(ctx, `
+		UPDATE notifications n SET read_at = CASE WHEN $3::boolean THEN COALESCE(n.read_at, NOW()) END
+		WHERE n.id = $1 AND n.wallet_id = $2 AND n.channel = $4 AND n.status = $5
+		RETURNING `+_notificationColumns,
+		id, walletID, read, entity.ChannelInbox, entity.NotificationSent))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - MarkRead - QueryRow: %w", err)
+	}
+
+	return notification, nil
+}
+
+// MarkAllRead marks every unread inbox notification of a wallet read
+func (r *NotificationRepo) MarkAllRead(ctx context.Context, walletID int) (int, error) {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE notifications SET read_at = NOW()
+		WHERE wallet_id = $1 AND channel = $2 AND status = $3 AND read_at IS NULL`,
+		walletID, entity.ChannelInbox, entity.NotificationSent)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationRepo - MarkAllRead - Exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDueNotifications moves the next attempt of up to limit due notifications of channels to
+// the end of the lease and returns them, oldest due first. SKIP LOCKED lets several instances
+// claim at once without sending a notification twice.
+func (r *NotificationRepo) ClaimDueNotifications(ctx context.Context, channels []string, limit int, lease time.Duration) ([]entity.Notification, error) {
+	rows, err := r.Pool.Query(ctx, `
+		WITH due AS (
+			SELECT id
+			FROM notifications
+			WHERE status = $1 AND next_attempt_at <= NOW() AND channel = ANY($2::text[])
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notifications n SET next_attempt_at = NOW() + $4 * INTERVAL '1 millisecond'
+		FROM due
+		WHERE n.id = due.id
+		RETURNING `+_notificationColumns,
+		entity.NotificationPending, channels, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("NotificationRepo - ClaimDueNotifications - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []entity.Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("NotificationRepo - ClaimDueNotifications - Scan: %w", err)
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("NotificationRepo - ClaimDueNotifications - Rows: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// RecordResult marks a notification sent at at, or counts its failed attempt and schedules the
+// next one. A zero retryAt fails the notification.
+func (r *NotificationRepo) RecordResult(ctx context.Context, id int64, at time.Time, sendErr error, retryAt time.Time) error {
+	var (
+		status, nextAttemptAt = entity.NotificationSent, at
+		sentAt                *time.Time
+		lastError             string
+	)
+	switch {
+	case sendErr == nil:
+		sentAt = &at
+	case retryAt.IsZero():
+		status, lastError = entity.NotificationFailed, sendErr.Error()
+	default:
+		status, nextAttemptAt, lastError = entity.NotificationPending, retryAt, sendErr.Error()
+	}
+
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE notifications
+		SET status = $2, attempts = attempts + 1, next_attempt_at = $3, sent_at = $4, last_error = NULLIF($5, '')
+		WHERE id = $1`,
+		id, status, nextAttemptAt, sentAt, lastError)
+	if err != nil {
+		return fmt.Errorf("NotificationRepo - RecordResult - Exec: %w", err)
+	}
+
+	return nil
+}
+
+func scanPreferences(row pgx.Row) (*entity.Preferences, error) {
+	var (
+		p         entity.Preferences
+		updatedAt time.Time
+	)
+	err := row.Scan(&p.WalletID, &p.Email, &p.MessageTo, &p.EmailKinds, &p.MessageKinds, &p.InboxKinds, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.UpdatedAt = &updatedAt
+	return &p, nil
+}
+
+func scanNotification(row pgx.Row) (*entity.Notification, error) {
+	var n entity.Notification
+	err := row.Scan(&n.ID, &n.WalletID, &n.EventID, &n.CommandID, &n.Kind, &n.Channel, &n.Recipient, &n.Subject, &n.Body,
+		&n.Status, &n.Attempts, &n.NextAttemptAt, &n.LastError, &n.SentAt, &n.ReadAt, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != entity.NotificationPending {
+		n.NextAttemptAt = nil
+	}
+	return &n, nil
+}
+
+// kinds keeps an empty list an empty array instead of NULL.
+func kinds(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+)
+
+//go:embed templates/*.tmpl
+var _defaultTemplates embed.FS
+
+// MessageData is what the template of a notification renders, from the point of view of the
+// notified wallet.
+type MessageData struct {
+	WalletID     int
+	EventID      string
+	Asset        string
+	Amount       float64 // Absolute
+	Incoming     bool    // Whether the balance of the wallet goes up
+	Counterparty int     // Other wallet of a transfer
+	Time         time.Time
+	ExecuteTime  time.Time           // Of a scheduled transfer
+	Alert        entity.AlertDetails // Of an alert
+}
+
+var _templateFuncs = template.FuncMap{
+	"amount": func(amount float64) string { return strconv.FormatFloat(amount, 'f', -1, 64) },
+	"utc":    func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+}
+
+// MessageTemplates render notifications. Each kind has a <kind>.tmpl template that defines a
+// "subject" and a "body".
+type MessageTemplates struct {
+	templates map[string]*template.Template
+}
+
+// NewMessageTemplates parses the templates of every kind. A <kind>.tmpl file in dir replaces the
+// built-in template of that kind; dir is optional.
+func NewMessageTemplates(dir string) (*MessageTemplates, error) {
+	t := &MessageTemplates{templates: make(map[string]*template.Template, len(entity.Kinds))}
+	for _, kind := range entity.Kinds {
+		name := kind + ".tmpl"
+
+		var source fs.FS = _defaultTemplates
+		pattern := "templates/" + name
+		if dir != "" {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				source, pattern = os.DirFS(dir), name
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("MessageTemplates - %s: %w", name, err)
+			}
+		}
+
+		parsed, err := template.New(name).Funcs(_templateFuncs).ParseFS(source, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("MessageTemplates - %s: %w", name, err)
+		}
+		for _, part := range []string{"subject", "body"} {
+			if parsed.Lookup(part) == nil {
+				return nil, fmt.Errorf("MessageTemplates - %s: no %q template", name, part)
+			}
+		}
+		t.templates[kind] = parsed
+	}
+
+	return t, nil
+}
+
+// Render renders the notification of kind. The subject is kept on one line.
+func (t *MessageTemplates) Render(kind string, data MessageData) (entity.Message, error) {
+	parsed, ok := t.templates[kind]
+	if !ok {
+		return entity.Message{}, fmt.Errorf("MessageTemplates - Render: unknown kind %q", kind)
+	}
+
+	var subject, body bytes.Buffer
+	if err := parsed.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return entity.Message{}, fmt.Errorf("MessageTemplates - Render - %s subject: %w", kind, err)
+	}
+	if err := parsed.ExecuteTemplate(&body, "body", data); err != nil {
+		return entity.Message{}, fmt.Errorf("MessageTemplates - Render - %s body: %w", kind, err)
+	}
+
+	return entity.Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Body:    strings.TrimSpace(body.String()),
+	}, nil
+}
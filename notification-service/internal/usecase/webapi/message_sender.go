@@ -0,0 +1,84 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+)
+
+type MessagePayload struct {
+	To      string `json:"to"`
+	Content string `json:"content"`
+}
+
+type MessageSenderWebAPI struct {
+	URL    string
+	client *http.Client
+}
+
+func NewMessageSenderWebAPI(url string, timeout time.Duration) *MessageSenderWebAPI {
+	return &MessageSenderWebAPI{
+		URL:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send sends a notification as a message to its recipient, with the subject on the first line.
+func (wc *MessageSenderWebAPI) Send(ctx context.Context, notification entity.Notification) error {
+	_, err := wc.sendMessage(ctx, notification.Recipient, notification.Subject+"\n\n"+notification.Body)
+	return err
+}
+
+func (wc *MessageSenderWebAPI) SendMessage(to, content string) (string, error) {
+	return wc.sendMessage(context.Background(), to, content)
+}
+
+func (wc *MessageSenderWebAPI) sendMessage(ctx context.Context, to, content string) (string, error) {
+	payload := MessagePayload{
+		To:      to,
+		Content: content,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", wc.URL, bytes.NewBuffer(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wc.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		return "", fmt.Errorf("unexpected response code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var responseMap map[string]interface{}
+	if err := json.Unmarshal(body, &responseMap); err != nil {
+		return "", fmt.Errorf("failed to parse response body: %w", err)
+	}
+
+	messageID, ok := responseMap["messageId"].(string)
+	if !ok {
+		return "", fmt.Errorf("messageId not found in response")
+	}
+
+	return messageID, nil
+}
@@ -0,0 +1,145 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ozlemugur/go-cqrs-event-sourcing-tt/notification-service/internal/entity"
+)
+
+// SMTP connection security
+const (
+	SMTPPlain    = "none"     // Unencrypted, for a local SMTP stand-in
+	SMTPStartTLS = "starttls" // Upgraded after connecting, usually on port 587
+	SMTPTLS      = "tls"      // Implicit TLS, usually on port 465
+)
+
+// SMTPConfig is the mail server email notifications are sent through.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Optional, no authentication without it
+	Password string
+	From     string
+	Security string // SMTPPlain, SMTPStartTLS or SMTPTLS
+	Timeout  time.Duration
+}
+
+// SMTPSenderWebAPI sends email notifications through a mail server.
+type SMTPSenderWebAPI struct {
+	cfg  SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPSenderWebAPI creates an email sender of cfg.
+func NewSMTPSenderWebAPI(cfg SMTPConfig) (*SMTPSenderWebAPI, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", cfg.From, err)
+	}
+	switch cfg.Security {
+	case SMTPPlain, SMTPStartTLS, SMTPTLS:
+	default:
+		return nil, fmt.Errorf("unknown SMTP security %q", cfg.Security)
+	}
+	return &SMTPSenderWebAPI{cfg: cfg, from: from}, nil
+}
+
+// Send mails a notification to its recipient as plain text.
+func (s *SMTPSenderWebAPI) Send(ctx context.Context, notification entity.Notification) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		return fmt.Errorf("failed to greet: %w", err)
+	}
+	defer client.Close()
+
+	if s.cfg.Security == SMTPStartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	if err := client.Rcpt(notification.Recipient); err != nil {
+		return fmt.Errorf("failed to set recipient: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start data: %w", err)
+	}
+	if _, err := w.Write(s.message(notification)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (s *SMTPSenderWebAPI) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	if s.cfg.Security == SMTPTLS {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}}
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", address)
+}
+
+// message builds the mail of a notification. The Message-ID is derived from the notification, so
+// a mail sent again after a lost reply carries the same one.
+func (s *SMTPSenderWebAPI) message(notification entity.Notification) []byte {
+	domain := s.from.Address[strings.LastIndex(s.from.Address, "@")+1:]
+
+	var msg bytes.Buffer
+	header := func(key, value string) { fmt.Fprintf(&msg, "%s: %s\r\n", key, value) }
+	header("From", s.from.String())
+	header("To", notification.Recipient)
+	header("Subject", mime.QEncoding.Encode("utf-8", notification.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<notification-%d@%s>", notification.ID, domain))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	header("X-Notification-Kind", notification.Kind)
+	msg.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&msg)
+	_, _ = body.Write([]byte(strings.ReplaceAll(notification.Body, "\n", "\r\n")))
+	_ = body.Close()
+
+	return msg.Bytes()
+}
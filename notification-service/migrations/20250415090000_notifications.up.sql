@@ -0,0 +1,36 @@
+-- Where and about what each wallet is notified, wallets without a row get the defaults
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    wallet_id INT PRIMARY KEY,
+    email VARCHAR(255) NOT NULL DEFAULT '',      -- Recipient of the email channel
+    message_to VARCHAR(255) NOT NULL DEFAULT '', -- Recipient of the message channel
+    email_kinds TEXT[] NOT NULL DEFAULT '{}',    -- Notification kinds sent by email
+    message_kinds TEXT[] NOT NULL DEFAULT '{}',  -- Notification kinds sent as messages
+    inbox_kinds TEXT[] NOT NULL DEFAULT '{}',    -- Notification kinds kept in the inbox
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One notification per wallet, event, kind and channel; the inbox is the sent inbox notifications
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_id INT NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    command_id VARCHAR(255),                     -- Cancels the reminder of a scheduled transfer once it runs
+    kind VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,                -- email, message or inbox
+    recipient VARCHAR(255) NOT NULL DEFAULT '',
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, sent, failed or cancelled
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    read_at TIMESTAMPTZ,                         -- Inbox only
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (event_id, wallet_id, kind, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notifications_wallet ON notifications (wallet_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (wallet_id) WHERE channel = 'inbox' AND status = 'sent' AND read_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_command ON notifications (command_id) WHERE status = 'pending';